@@ -21,3 +21,8 @@ const (
 //
 var ErrConnectionIsClosed = errors.New("Connection is closed, command aborted")
 var ErrNoConnectionsAvailable = errors.New("No Connections available")
+var ErrBlockingTimeoutRequired = errors.New("Blocking command requires a timeout > 0")
+var ErrQueueIsFull = errors.New("Queue is full, command dropped")
+var ErrQueueIsClosed = errors.New("Queue is closed, command dropped")
+var ErrQueueIsSpilled = errors.New("Queue is full, command spilled to disk")
+var ErrCrossShardCommand = errors.New("Command keys span shards, command aborted")
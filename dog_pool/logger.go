@@ -0,0 +1,116 @@
+//
+// Pluggable logging
+//
+// Every type logs through the Logger interface, a nil Logger is the NopLogger.
+// Adapters are provided for log4go, the standard library "log" and "log/slog" (Go 1.21+).
+//
+
+package dog_pool
+
+import "fmt"
+import "log"
+import "os"
+
+//
+// Severity of a log message, in the same order as log4go's levels
+//
+type LogLevel int
+
+const (
+	LogFinest LogLevel = iota
+	LogFine
+	LogDebug
+	LogTrace
+	LogInfo
+	LogWarning
+	LogError
+	LogCritical
+)
+
+var log_level_names = []string{"FINEST", "FINE", "DEBUG", "TRACE", "INFO", "WARNING", "ERROR", "CRITICAL"}
+
+func (level LogLevel) String() string {
+	if level < LogFinest || level > LogCritical {
+		return fmt.Sprintf("LogLevel(%d)", int(level))
+	}
+	return log_level_names[level]
+}
+
+//
+// Logger used by the connections, pools and queues
+//
+type Logger interface {
+	// Enabled reports whether messages at the level are logged,
+	// callers check it before building expensive messages.
+	Enabled(level LogLevel) bool
+
+	// Log the message at the level, formatted with fmt.Sprintf.
+	Log(level LogLevel, format string, args ...interface{})
+}
+
+//
+// Log the message, if the logger is enabled at the level
+//
+// The args are boxed before the check, hot paths wrap the call in isLogEnabled(...)
+//
+func logAt(logger Logger, level LogLevel, format string, args ...interface{}) {
+	if nil != logger && logger.Enabled(level) {
+		logger.Log(level, format, args...)
+	}
+}
+
+//
+// Is the logger enabled at the level? nil loggers are never enabled
+//
+func isLogEnabled(logger Logger, level LogLevel) bool {
+	return nil != logger && logger.Enabled(level)
+}
+
+//
+// ==================================================
+//
+// No-op Logger, the default:
+//
+// ==================================================
+//
+
+type NopLogger struct{}
+
+func (p NopLogger) Enabled(level LogLevel) bool                            { return false }
+func (p NopLogger) Log(level LogLevel, format string, args ...interface{}) {}
+
+//
+// ==================================================
+//
+// Standard library "log" adapter:
+//
+// ==================================================
+//
+
+//
+// Log to a standard library logger, prefixing each message with its level
+//
+type StdLogger struct {
+	Logger *log.Logger "Logger to write to"
+	Level  LogLevel    "Minimum level to log"
+}
+
+//
+// Log messages at or above the level, defaults to stderr for a nil logger
+//
+func MakeStdLogger(logger *log.Logger, level LogLevel) *StdLogger {
+	if nil == logger {
+		logger = log.New(os.Stderr, "", log.LstdFlags)
+	}
+	return &StdLogger{Logger: logger, Level: level}
+}
+
+func (p *StdLogger) Enabled(level LogLevel) bool {
+	return level >= p.Level
+}
+
+func (p *StdLogger) Log(level LogLevel, format string, args ...interface{}) {
+	if p.Enabled(level) {
+		p.Logger.Printf("[%s] %s", level, fmt.Sprintf(format, args...))
+	}
+}
@@ -0,0 +1,37 @@
+//
+// log4go adapter for the Logger interface
+//
+
+package dog_pool
+
+import "github.com/alecthomas/log4go"
+
+//
+// Log to a log4go logger
+//
+type Log4goLogger struct {
+	Logger log4go.Logger "Logger to write to"
+}
+
+//
+// Wrap the log4go logger
+//
+func MakeLog4goLogger(logger log4go.Logger) *Log4goLogger {
+	return &Log4goLogger{Logger: logger}
+}
+
+//
+// Enabled if any filter accepts the level
+//
+func (p *Log4goLogger) Enabled(level LogLevel) bool {
+	for _, filter := range p.Logger {
+		if filter.Level <= log4go.Level(level) {
+			return true
+		}
+	}
+	return false
+}
+
+func (p *Log4goLogger) Log(level LogLevel, format string, args ...interface{}) {
+	p.Logger.Logf(log4go.Level(level), format, args...)
+}
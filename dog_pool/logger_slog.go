@@ -0,0 +1,57 @@
+//go:build go1.21
+// +build go1.21
+
+//
+// log/slog adapter for the Logger interface
+//
+
+package dog_pool
+
+import "context"
+import "fmt"
+import "log/slog"
+
+//
+// Log to a slog logger
+//
+// FINEST, FINE, DEBUG & TRACE are logged at slog.LevelDebug, CRITICAL at slog.LevelError+4
+//
+type SlogLogger struct {
+	Logger *slog.Logger "Logger to write to"
+}
+
+//
+// Wrap the slog logger, defaults to slog.Default() for a nil logger
+//
+func MakeSlogLogger(logger *slog.Logger) *SlogLogger {
+	if nil == logger {
+		logger = slog.Default()
+	}
+	return &SlogLogger{Logger: logger}
+}
+
+func (p *SlogLogger) Enabled(level LogLevel) bool {
+	return p.Logger.Enabled(context.Background(), slogLevel(level))
+}
+
+func (p *SlogLogger) Log(level LogLevel, format string, args ...interface{}) {
+	ctx := context.Background()
+	if slog_level := slogLevel(level); p.Logger.Enabled(ctx, slog_level) {
+		p.Logger.Log(ctx, slog_level, fmt.Sprintf(format, args...))
+	}
+}
+
+func slogLevel(level LogLevel) slog.Level {
+	switch {
+	case level <= LogTrace:
+		return slog.LevelDebug
+	case level == LogInfo:
+		return slog.LevelInfo
+	case level == LogWarning:
+		return slog.LevelWarn
+	case level == LogError:
+		return slog.LevelError
+	default:
+		return slog.LevelError + 4
+	}
+}
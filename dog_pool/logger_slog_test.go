@@ -0,0 +1,40 @@
+//go:build go1.21
+// +build go1.21
+
+package dog_pool
+
+import "bytes"
+import "log/slog"
+import "strings"
+import "testing"
+import "github.com/orfjackal/gospec/src/gospec"
+
+func TestSlogLoggerSpecs(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping test in benchmark mode.")
+		return
+	}
+	r := gospec.NewRunner()
+	r.AddSpec(SlogLoggerSpecs)
+	gospec.MainGoTest(r, t)
+}
+
+// Helpers
+func SlogLoggerSpecs(c gospec.Context) {
+
+	c.Specify("[SlogLogger] Maps the levels onto slog's", func() {
+		buffer := bytes.NewBuffer(nil)
+		logger := MakeSlogLogger(slog.New(slog.NewTextHandler(buffer, &slog.HandlerOptions{Level: slog.LevelInfo})))
+
+		c.Expect(logger.Enabled(LogTrace), gospec.Equals, false)
+		c.Expect(logger.Enabled(LogInfo), gospec.Equals, true)
+
+		logAt(logger, LogTrace, "Hidden %v", 1)
+		logAt(logger, LogError, "Shown %v", 2)
+		c.Expect(strings.Contains(buffer.String(), "Hidden"), gospec.Equals, false)
+		c.Expect(strings.Contains(buffer.String(), `level=ERROR msg="Shown 2"`), gospec.Equals, true)
+
+		c.Expect(slogLevel(LogFinest), gospec.Equals, slog.LevelDebug)
+		c.Expect(slogLevel(LogCritical), gospec.Equals, slog.LevelError+4)
+	})
+}
@@ -0,0 +1,59 @@
+package dog_pool
+
+import "bytes"
+import "log"
+import "testing"
+import "github.com/orfjackal/gospec/src/gospec"
+import "github.com/alecthomas/log4go"
+
+func TestLoggerSpecs(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping test in benchmark mode.")
+		return
+	}
+	r := gospec.NewRunner()
+	r.AddSpec(LoggerSpecs)
+	gospec.MainGoTest(r, t)
+}
+
+// Helpers
+func LoggerSpecs(c gospec.Context) {
+
+	c.Specify("[LogLevel] Formats as a string", func() {
+		c.Expect(LogFinest.String(), gospec.Equals, "FINEST")
+		c.Expect(LogWarning.String(), gospec.Equals, "WARNING")
+		c.Expect(LogCritical.String(), gospec.Equals, "CRITICAL")
+		c.Expect(LogLevel(99).String(), gospec.Equals, "LogLevel(99)")
+	})
+
+	c.Specify("[logAt] Nil loggers & the NopLogger are never enabled", func() {
+		c.Expect(isLogEnabled(nil, LogCritical), gospec.Equals, false)
+		c.Expect(isLogEnabled(NopLogger{}, LogCritical), gospec.Equals, false)
+
+		// Don't panic
+		logAt(nil, LogCritical, "Hello %v", "World")
+		logAt(NopLogger{}, LogCritical, "Hello %v", "World")
+	})
+
+	c.Specify("[StdLogger] Logs at or above the level", func() {
+		buffer := bytes.NewBuffer(nil)
+		logger := MakeStdLogger(log.New(buffer, "", 0), LogInfo)
+
+		c.Expect(logger.Enabled(LogTrace), gospec.Equals, false)
+		c.Expect(logger.Enabled(LogInfo), gospec.Equals, true)
+		c.Expect(logger.Enabled(LogError), gospec.Equals, true)
+
+		logAt(logger, LogTrace, "Hidden %v", 1)
+		logAt(logger, LogWarning, "Shown %v", 2)
+		c.Expect(buffer.String(), gospec.Equals, "[WARNING] Shown 2\n")
+	})
+
+	c.Specify("[Log4goLogger] Enabled if any filter accepts the level", func() {
+		logger := MakeLog4goLogger(log4go.NewDefaultLogger(log4go.WARNING))
+		c.Expect(logger.Enabled(LogInfo), gospec.Equals, false)
+		c.Expect(logger.Enabled(LogWarning), gospec.Equals, true)
+		c.Expect(logger.Enabled(LogCritical), gospec.Equals, true)
+
+		c.Expect(MakeLog4goLogger(log4go.Logger{}).Enabled(LogCritical), gospec.Equals, false)
+	})
+}
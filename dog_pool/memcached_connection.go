@@ -5,12 +5,11 @@
 package dog_pool
 
 import "bytes"
+import "context"
 import "fmt"
 import "runtime"
-import "strconv"
 import "strings"
 import "time"
-import "github.com/alecthomas/log4go"
 import memcached "github.com/bradfitz/gomemcache/memcache"
 
 //
@@ -21,17 +20,21 @@ type MemcachedConnection struct {
 
 	Id string "(optional) Identifier for distingushing between memcached connections"
 
-	Logger *log4go.Logger "Handle to the logger we are using"
+	Logger Logger "(optional) Handle to the logger we are using, nil is the NopLogger"
 
 	Timeout time.Duration "Timeout"
 
+	Tracer Tracer "(optional) Opens a span for every operation, see GetContext, SetContext, etc"
+
+	Redaction *RedactionPolicy "(optional) Hides & truncates the logged keys & values, defaults to DefaultRedactionPolicy"
+
 	client *memcached.Client "Connection to a Memcached, may be nil"
 }
 
 //
 // Lazily make a Redis Connection
 //
-func makeLazyMemcachedConnection(url string, id string, timeout time.Duration, logger *log4go.Logger) (*MemcachedConnection, error) {
+func makeLazyMemcachedConnection(url string, id string, timeout time.Duration, logger Logger) (*MemcachedConnection, error) {
 	// Create a new factory instance
 	p := &MemcachedConnection{Url: url, Id: id, Logger: logger, Timeout: timeout}
 
@@ -42,7 +45,7 @@ func makeLazyMemcachedConnection(url string, id string, timeout time.Duration, l
 //
 // Agressively make a Memcached Connection
 //
-func makeAgressiveMemcachedConnection(url string, id string, timeout time.Duration, logger *log4go.Logger) (*MemcachedConnection, error) {
+func makeAgressiveMemcachedConnection(url string, id string, timeout time.Duration, logger Logger) (*MemcachedConnection, error) {
 	// Create a new factory instance
 	p, _ := makeLazyMemcachedConnection(url, id, timeout, logger)
 
@@ -67,7 +70,9 @@ func (p *MemcachedConnection) recoverPanic(cmd string, keys []string) error {
 	}
 
 	// Panic error
-	p.Logger.Critical("[MemcachedConnection][%s][%s/%s] Memcached Keys = '%s' --> Panic Error = '%v'", cmd, p.Url, p.Id, strings.Join(keys, ", "), r)
+	if isLogEnabled(p.Logger, LogCritical) {
+		logAt(p.Logger, LogCritical, "[MemcachedConnection][%s][%s/%s] Memcached Keys = '%s' --> Panic Error = '%v'", cmd, p.Url, p.Id, strings.Join(redactionPolicyOr(p.Redaction).FormatKeys(keys), ", "), r)
+	}
 
 	// Close the connection
 	p.Close()
@@ -89,15 +94,23 @@ func (p *MemcachedConnection) checkIsOpen(cmd string, keys []string) error {
 	// Did opening the connection fail?
 	err := p.Open()
 	if nil != err {
-		p.Logger.Warn("[MemcachedConnection][%s][%s/%s] Memcached Keys = '%s' --> Open Error = '%v'", cmd, p.Url, p.Id, strings.Join(keys, ","), err)
+		if isLogEnabled(p.Logger, LogWarning) {
+			logAt(p.Logger, LogWarning, "[MemcachedConnection][%s][%s/%s] Memcached Keys = '%s' --> Open Error = '%v'", cmd, p.Url, p.Id, strings.Join(redactionPolicyOr(p.Redaction).FormatKeys(keys), ","), err)
+		}
 	}
 
 	// Return the error, may be nil
 	return err
 }
 
-func toStringSlice(item *memcached.Item) []string {
-	return []string{item.Key, bytes.NewBuffer(item.Value).String(), strconv.Itoa(int(item.Expiration))}
+// Key for the logs, formatted by the RedactionPolicy only if it is logged
+func (p *MemcachedConnection) logKey(key string) redactedKey {
+	return redactedKey{policy: redactionPolicyOr(p.Redaction), key: key}
+}
+
+// Value for the logs, formatted by the RedactionPolicy only if it is logged
+func (p *MemcachedConnection) logValue(cmd, key string, value []byte) redactedValue {
+	return redactedValue{policy: redactionPolicyOr(p.Redaction), cmd: cmd, key: key, value: value}
 }
 
 //
@@ -113,6 +126,15 @@ func toStringSlice(item *memcached.Item) []string {
 // cache misses. Each key must be at most 250 bytes in length.
 // If no error is returned, the returned map will also be non-nil.
 func (p *MemcachedConnection) GetMulti(keys []string) (output map[string]*memcached.Item, err error) {
+	return p.GetMultiContext(context.Background(), keys)
+}
+
+// GetMultiContext is GetMulti, in a child span of the context.
+func (p *MemcachedConnection) GetMultiContext(ctx context.Context, keys []string) (output map[string]*memcached.Item, err error) {
+	// Trace the operation, after any panic is recovered
+	span := p.startSpan(ctx, "GetMulti", len(keys))
+	defer func() { endMemcachedSpan(span, err) }()
+
 	// Recover from panic'd errors
 	defer func() {
 		if recovered_err := p.recoverPanic("GetMulti", keys); nil != recovered_err {
@@ -130,22 +152,24 @@ func (p *MemcachedConnection) GetMulti(keys []string) (output map[string]*memcac
 	// Perform the memcached request
 	// stop_watch := MakeStopWatchTags(p, p.Logger, append([]string{p.Url, p.Id, "GetMulti"}, keys...)).Start()
 	output, err = p.client.GetMulti(keys)
-	// stop_watch.Stop().LogDurationAt(log4go.TRACE)
+	// stop_watch.Stop().LogDurationAt(LogTrace)
 
 	switch err {
 	case nil:
-		p.Logger.Trace(func() string {
+		if isLogEnabled(p.Logger, LogTrace) {
 			buffer := make([]string, len(output))
 			i := 0
 			for key, item := range output {
-				buffer[i] = fmt.Sprintf("%s=%s", key, bytes.NewBuffer(item.Value).String())
+				buffer[i] = fmt.Sprintf("%v=%v", p.logKey(key), p.logValue("GetMulti", key, item.Value))
 				i++
 			}
 
-			return fmt.Sprintf("[MemcachedConnection][Get][%s/%s] Keys = '%v' --> Got Values = [%s]!", p.Url, p.Id, strings.Join(keys, ","), strings.Join(buffer, ", "))
-		})
+			logAt(p.Logger, LogTrace, "[MemcachedConnection][Get][%s/%s] Keys = '%v' --> Got Values = [%s]!", p.Url, p.Id, strings.Join(redactionPolicyOr(p.Redaction).FormatKeys(keys), ","), strings.Join(buffer, ", "))
+		}
 	default:
-		p.Logger.Error("[MemcachedConnection][Get][%s/%s] Key = '%v' --> Fatal Error = '%v'", p.Url, p.Id, strings.Join(keys, ","), err)
+		if isLogEnabled(p.Logger, LogError) {
+			logAt(p.Logger, LogError, "[MemcachedConnection][Get][%s/%s] Key = '%v' --> Fatal Error = '%v'", p.Url, p.Id, strings.Join(redactionPolicyOr(p.Redaction).FormatKeys(keys), ","), err)
+		}
 		p.Close()
 	}
 
@@ -155,6 +179,15 @@ func (p *MemcachedConnection) GetMulti(keys []string) (output map[string]*memcac
 // Get gets the item for the given key. ErrCacheMiss is returned for a
 // memcache cache miss. The key must be at most 250 bytes in length.
 func (p *MemcachedConnection) Get(key string) (item *memcached.Item, err error) {
+	return p.GetContext(context.Background(), key)
+}
+
+// GetContext is Get, in a child span of the context.
+func (p *MemcachedConnection) GetContext(ctx context.Context, key string) (item *memcached.Item, err error) {
+	// Trace the operation, after any panic is recovered
+	span := p.startSpan(ctx, "Get", 1)
+	defer func() { endMemcachedSpan(span, err) }()
+
 	// Recover from panic'd errors
 	defer func() {
 		if recovered_err := p.recoverPanic("Get", []string{key}); nil != recovered_err {
@@ -171,15 +204,21 @@ func (p *MemcachedConnection) Get(key string) (item *memcached.Item, err error)
 	// Perform the memcached request
 	// stop_watch := MakeStopWatchTags(p, p.Logger, []string{p.Url, p.Id, "Get", key}).Start()
 	item, err = p.client.Get(key)
-	// stop_watch.Stop().LogDurationAt(log4go.TRACE)
+	// stop_watch.Stop().LogDurationAt(LogTrace)
 
 	switch err {
 	case nil:
-		p.Logger.Trace("[MemcachedConnection][Get][%s/%s] Key = '%v' --> Got Value = '%v'!", p.Url, p.Id, key, bytes.NewBuffer(item.Value).String())
+		if isLogEnabled(p.Logger, LogTrace) {
+			logAt(p.Logger, LogTrace, "[MemcachedConnection][Get][%s/%s] Key = '%v' --> Got Value = '%v'!", p.Url, p.Id, p.logKey(key), p.logValue("Get", key, item.Value))
+		}
 	case memcached.ErrCacheMiss:
-		p.Logger.Trace("[MemcachedConnection][Get][%s/%s] Key = '%v' --> Not Stored = '%v'", p.Url, p.Id, key, err)
+		if isLogEnabled(p.Logger, LogTrace) {
+			logAt(p.Logger, LogTrace, "[MemcachedConnection][Get][%s/%s] Key = '%v' --> Not Stored = '%v'", p.Url, p.Id, p.logKey(key), err)
+		}
 	default:
-		p.Logger.Error("[MemcachedConnection][Get][%s/%s] Key = '%v' --> Fatal Error = '%v'", p.Url, p.Id, key, err)
+		if isLogEnabled(p.Logger, LogError) {
+			logAt(p.Logger, LogError, "[MemcachedConnection][Get][%s/%s] Key = '%v' --> Fatal Error = '%v'", p.Url, p.Id, p.logKey(key), err)
+		}
 		p.Close()
 	}
 
@@ -188,33 +227,43 @@ func (p *MemcachedConnection) Get(key string) (item *memcached.Item, err error)
 
 // Set writes the given item, unconditionally.
 func (p *MemcachedConnection) Set(item *memcached.Item) (err error) {
-	log_item := toStringSlice(item)
+	return p.SetContext(context.Background(), item)
+}
+
+// SetContext is Set, in a child span of the context.
+func (p *MemcachedConnection) SetContext(ctx context.Context, item *memcached.Item) (err error) {
+	// Trace the operation, after any panic is recovered
+	span := p.startSpan(ctx, "Set", 1)
+	defer func() { endMemcachedSpan(span, err) }()
 
 	// Recover from panic'd errors
 	defer func() {
-		if recovered_err := p.recoverPanic("Set", log_item); nil != recovered_err {
+		if recovered_err := p.recoverPanic("Set", []string{item.Key}); nil != recovered_err {
 			err = recovered_err
 			return
 		}
 	}()
 
 	// Open the connection if necessary
-	if err := p.checkIsOpen("Set", log_item); nil != err {
+	if err := p.checkIsOpen("Set", []string{item.Key}); nil != err {
 		return err
 	}
 
 	// Perform the memcached request
 	// stop_watch := MakeStopWatchTags(p, p.Logger, []string{p.Url, p.Id, "Set", item.Key}).Start()
 	err = p.client.Set(item)
-	// stop_watch.Stop().LogDurationAt(log4go.TRACE)
+	// stop_watch.Stop().LogDurationAt(LogTrace)
 
 	key := item.Key
-	delta := bytes.NewBuffer(item.Value).String()
 	switch err {
 	case nil:
-		p.Logger.Trace("[MemcachedConnection][Set][%s/%s] Key = '%v', Value = '%v', Expires = %d(s) --> Set Value!", p.Url, p.Id, key, delta, item.Expiration)
+		if isLogEnabled(p.Logger, LogTrace) {
+			logAt(p.Logger, LogTrace, "[MemcachedConnection][Set][%s/%s] Key = '%v', Value = '%v', Expires = %d(s) --> Set Value!", p.Url, p.Id, p.logKey(key), p.logValue("Set", key, item.Value), item.Expiration)
+		}
 	default:
-		p.Logger.Error("[MemcachedConnection][Set][%s/%s] Key = '%v', Value = '%v', Expires = %d(s) --> Fatal Error = '%v'", p.Url, p.Id, key, delta, item.Expiration, err)
+		if isLogEnabled(p.Logger, LogError) {
+			logAt(p.Logger, LogError, "[MemcachedConnection][Set][%s/%s] Key = '%v', Value = '%v', Expires = %d(s) --> Fatal Error = '%v'", p.Url, p.Id, p.logKey(key), p.logValue("Set", key, item.Value), item.Expiration, err)
+		}
 		p.Close()
 	}
 
@@ -224,6 +273,15 @@ func (p *MemcachedConnection) Set(item *memcached.Item) (err error) {
 // Delete deletes the item with the provided key. The error ErrCacheMiss is
 // returned if the item didn't already exist in the cache.
 func (p *MemcachedConnection) Delete(key string) (err error) {
+	return p.DeleteContext(context.Background(), key)
+}
+
+// DeleteContext is Delete, in a child span of the context.
+func (p *MemcachedConnection) DeleteContext(ctx context.Context, key string) (err error) {
+	// Trace the operation, after any panic is recovered
+	span := p.startSpan(ctx, "Delete", 1)
+	defer func() { endMemcachedSpan(span, err) }()
+
 	// Recover from panic'd errors
 	defer func() {
 		if recovered_err := p.recoverPanic("Delete", []string{key}); nil != recovered_err {
@@ -240,15 +298,21 @@ func (p *MemcachedConnection) Delete(key string) (err error) {
 	// Perform the memcached request
 	// stop_watch := MakeStopWatchTags(p, p.Logger, []string{p.Url, p.Id, "Delete", key}).Start()
 	err = p.client.Delete(key)
-	// stop_watch.Stop().LogDurationAt(log4go.TRACE)
+	// stop_watch.Stop().LogDurationAt(LogTrace)
 
 	switch err {
 	case nil:
-		p.Logger.Trace("[MemcachedConnection][Delete][%s/%s] Key = '%v' --> Deleted Value!", p.Url, p.Id, key)
+		if isLogEnabled(p.Logger, LogTrace) {
+			logAt(p.Logger, LogTrace, "[MemcachedConnection][Delete][%s/%s] Key = '%v' --> Deleted Value!", p.Url, p.Id, p.logKey(key))
+		}
 	case memcached.ErrCacheMiss:
-		p.Logger.Trace("[MemcachedConnection][Delete][%s/%s] Key = '%v' --> Not Stored = '%v'", p.Url, p.Id, key, err)
+		if isLogEnabled(p.Logger, LogTrace) {
+			logAt(p.Logger, LogTrace, "[MemcachedConnection][Delete][%s/%s] Key = '%v' --> Not Stored = '%v'", p.Url, p.Id, p.logKey(key), err)
+		}
 	default:
-		p.Logger.Error("[MemcachedConnection][Delete][%s/%s] Key = '%v' --> Fatal Error = '%v'", p.Url, p.Id, key, err)
+		if isLogEnabled(p.Logger, LogError) {
+			logAt(p.Logger, LogError, "[MemcachedConnection][Delete][%s/%s] Key = '%v' --> Fatal Error = '%v'", p.Url, p.Id, p.logKey(key), err)
+		}
 		p.Close()
 	}
 
@@ -258,35 +322,47 @@ func (p *MemcachedConnection) Delete(key string) (err error) {
 // Add writes the given item, if no value already exists for its
 // key. ErrNotStored is returned if that condition is not met.
 func (p *MemcachedConnection) Add(item *memcached.Item) (err error) {
-	log_item := toStringSlice(item)
+	return p.AddContext(context.Background(), item)
+}
+
+// AddContext is Add, in a child span of the context.
+func (p *MemcachedConnection) AddContext(ctx context.Context, item *memcached.Item) (err error) {
+	// Trace the operation, after any panic is recovered
+	span := p.startSpan(ctx, "Add", 1)
+	defer func() { endMemcachedSpan(span, err) }()
 
 	// Recover from panic'd errors
 	defer func() {
-		if recovered_err := p.recoverPanic("Add", log_item); nil != recovered_err {
+		if recovered_err := p.recoverPanic("Add", []string{item.Key}); nil != recovered_err {
 			err = recovered_err
 			return
 		}
 	}()
 
 	// Open the connection if necessary
-	if err := p.checkIsOpen("Add", log_item); nil != err {
+	if err := p.checkIsOpen("Add", []string{item.Key}); nil != err {
 		return err
 	}
 
 	// Perform the memcached request
 	// stop_watch := MakeStopWatchTags(p, p.Logger, []string{p.Url, p.Id, "Add", item.Key}).Start()
 	err = p.client.Add(item)
-	// stop_watch.Stop().LogDurationAt(log4go.TRACE)
+	// stop_watch.Stop().LogDurationAt(LogTrace)
 
 	key := item.Key
-	delta := bytes.NewBuffer(item.Value).String()
 	switch err {
 	case nil:
-		p.Logger.Trace("[MemcachedConnection][Add][%s/%s] Key = '%v', Value = '%v' --> Added Value!", p.Url, p.Id, key, delta)
+		if isLogEnabled(p.Logger, LogTrace) {
+			logAt(p.Logger, LogTrace, "[MemcachedConnection][Add][%s/%s] Key = '%v', Value = '%v' --> Added Value!", p.Url, p.Id, p.logKey(key), p.logValue("Add", key, item.Value))
+		}
 	case memcached.ErrNotStored:
-		p.Logger.Trace("[MemcachedConnection][Add][%s/%s] Key = '%v', Value = '%v' --> Not Stored = '%v'", p.Url, p.Id, key, delta, err)
+		if isLogEnabled(p.Logger, LogTrace) {
+			logAt(p.Logger, LogTrace, "[MemcachedConnection][Add][%s/%s] Key = '%v', Value = '%v' --> Not Stored = '%v'", p.Url, p.Id, p.logKey(key), p.logValue("Add", key, item.Value), err)
+		}
 	default:
-		p.Logger.Error("[MemcachedConnection][Add][%s/%s] Key = '%v', Value = '%v' --> Fatal Error = '%v'", p.Url, p.Id, key, delta, err)
+		if isLogEnabled(p.Logger, LogError) {
+			logAt(p.Logger, LogError, "[MemcachedConnection][Add][%s/%s] Key = '%v', Value = '%v' --> Fatal Error = '%v'", p.Url, p.Id, p.logKey(key), p.logValue("Add", key, item.Value), err)
+		}
 		p.Close()
 	}
 
@@ -299,7 +375,16 @@ func (p *MemcachedConnection) Add(item *memcached.Item) (err error) {
 // memcached must be an decimal number, or an error will be returned.
 // On 64-bit overflow, the new value wraps around.
 func (p *MemcachedConnection) Increment(key string, delta uint64) (newValue uint64, err error) {
-	log_keys := []string{key, strconv.Itoa(int(delta))}
+	return p.IncrementContext(context.Background(), key, delta)
+}
+
+// IncrementContext is Increment, in a child span of the context.
+func (p *MemcachedConnection) IncrementContext(ctx context.Context, key string, delta uint64) (newValue uint64, err error) {
+	// Trace the operation, after any panic is recovered
+	span := p.startSpan(ctx, "Increment", 1)
+	defer func() { endMemcachedSpan(span, err) }()
+
+	log_keys := []string{key}
 
 	// Recover from panic'd errors
 	defer func() {
@@ -317,15 +402,21 @@ func (p *MemcachedConnection) Increment(key string, delta uint64) (newValue uint
 	// Perform the memcached request
 	// stop_watch := MakeStopWatchTags(p, p.Logger, []string{p.Url, p.Id, "Increment", key}).Start()
 	newValue, err = p.client.Increment(key, delta)
-	// stop_watch.Stop().LogDurationAt(log4go.TRACE)
+	// stop_watch.Stop().LogDurationAt(LogTrace)
 
 	switch err {
 	case nil:
-		p.Logger.Trace("[MemcachedConnection][Increment][%s/%s] Key = '%v', Delta = %d --> Incremented Value = %d!", p.Url, p.Id, key, delta, newValue)
+		if isLogEnabled(p.Logger, LogTrace) {
+			logAt(p.Logger, LogTrace, "[MemcachedConnection][Increment][%s/%s] Key = '%v', Delta = %d --> Incremented Value = %d!", p.Url, p.Id, p.logKey(key), delta, newValue)
+		}
 	case memcached.ErrCacheMiss:
-		p.Logger.Trace("[MemcachedConnection][Increment][%s/%s] Key = '%v', Delta = %d --> Not Stored = '%v'", p.Url, p.Id, key, delta, err)
+		if isLogEnabled(p.Logger, LogTrace) {
+			logAt(p.Logger, LogTrace, "[MemcachedConnection][Increment][%s/%s] Key = '%v', Delta = %d --> Not Stored = '%v'", p.Url, p.Id, p.logKey(key), delta, err)
+		}
 	default:
-		p.Logger.Error("[MemcachedConnection][Increment][%s/%s] Key = '%v', Delta = %d --> Fatal Error = '%v'", p.Url, p.Id, key, err)
+		if isLogEnabled(p.Logger, LogError) {
+			logAt(p.Logger, LogError, "[MemcachedConnection][Increment][%s/%s] Key = '%v', Delta = %d --> Fatal Error = '%v'", p.Url, p.Id, p.logKey(key), delta, err)
+		}
 		p.Close()
 	}
 
@@ -339,7 +430,16 @@ func (p *MemcachedConnection) Increment(key string, delta uint64) (newValue uint
 // On underflow, the new value is capped at zero and does not wrap
 // around.
 func (p *MemcachedConnection) Decrement(key string, delta uint64) (newValue uint64, err error) {
-	log_keys := []string{key, strconv.Itoa(int(delta))}
+	return p.DecrementContext(context.Background(), key, delta)
+}
+
+// DecrementContext is Decrement, in a child span of the context.
+func (p *MemcachedConnection) DecrementContext(ctx context.Context, key string, delta uint64) (newValue uint64, err error) {
+	// Trace the operation, after any panic is recovered
+	span := p.startSpan(ctx, "Decrement", 1)
+	defer func() { endMemcachedSpan(span, err) }()
+
+	log_keys := []string{key}
 
 	// Recover from panic'd errors
 	defer func() {
@@ -357,15 +457,21 @@ func (p *MemcachedConnection) Decrement(key string, delta uint64) (newValue uint
 	// Perform the memcached request
 	// stop_watch := MakeStopWatchTags(p, p.Logger, []string{p.Url, p.Id, "Decrement", key}).Start()
 	newValue, err = p.client.Decrement(key, delta)
-	// stop_watch.Stop().LogDurationAt(log4go.TRACE)
+	// stop_watch.Stop().LogDurationAt(LogTrace)
 
 	switch err {
 	case nil:
-		p.Logger.Trace("[MemcachedConnection][Decrement][%s/%s] Key = '%v', Delta = %d --> Decremented Value = %d!", p.Url, p.Id, key, delta, newValue)
+		if isLogEnabled(p.Logger, LogTrace) {
+			logAt(p.Logger, LogTrace, "[MemcachedConnection][Decrement][%s/%s] Key = '%v', Delta = %d --> Decremented Value = %d!", p.Url, p.Id, p.logKey(key), delta, newValue)
+		}
 	case memcached.ErrCacheMiss:
-		p.Logger.Trace("[MemcachedConnection][Decrement][%s/%s] Key = '%v', Delta = %d --> Not Stored = '%v'", p.Url, p.Id, key, delta, err)
+		if isLogEnabled(p.Logger, LogTrace) {
+			logAt(p.Logger, LogTrace, "[MemcachedConnection][Decrement][%s/%s] Key = '%v', Delta = %d --> Not Stored = '%v'", p.Url, p.Id, p.logKey(key), delta, err)
+		}
 	default:
-		p.Logger.Error("[MemcachedConnection][Decrement][%s/%s] Key = '%v', Delta = %d --> Fatal Error = '%v'", p.Url, p.Id, key, err)
+		if isLogEnabled(p.Logger, LogError) {
+			logAt(p.Logger, LogError, "[MemcachedConnection][Decrement][%s/%s] Key = '%v', Delta = %d --> Fatal Error = '%v'", p.Url, p.Id, p.logKey(key), delta, err)
+		}
 		p.Close()
 	}
 
@@ -380,6 +486,26 @@ func (p *MemcachedConnection) Decrement(key string, delta uint64) (newValue uint
 //  ========================================
 //
 
+// Start a span for the operation
+func (p *MemcachedConnection) startSpan(ctx context.Context, cmd string, key_count int) Span {
+	_, span := startSpan(ctx, p.Tracer, "memcached", cmd, p.Url, func() int { return key_count })
+	return span
+}
+
+// Record the outcome of the operation and end the span, misses are not errors
+func endMemcachedSpan(span Span, err error) {
+	switch err {
+	case nil:
+		endSpan(span, "ok", nil)
+	case memcached.ErrCacheMiss:
+		endSpan(span, "miss", nil)
+	case memcached.ErrNotStored:
+		endSpan(span, "not_stored", nil)
+	default:
+		endSpan(span, "error", err)
+	}
+}
+
 // Create a new (un-opened) copy of this MemcachedConnection
 func (p *MemcachedConnection) Clone() *MemcachedConnection {
 	return &MemcachedConnection{
@@ -387,6 +513,7 @@ func (p *MemcachedConnection) Clone() *MemcachedConnection {
 		Id:      p.Id,
 		Logger:  p.Logger,
 		Timeout: p.Timeout,
+		Tracer:  p.Tracer,
 		client:  nil,
 	}
 }
@@ -443,8 +570,10 @@ func (p *MemcachedConnection) Ping() error {
 func (p *MemcachedConnection) IsOpen() bool {
 	output := nil != p.client
 
-	// Debug logging
-	p.Logger.Trace("[MemcachedConnection][IsOpen][%s/%s] --> %v", p.Url, p.Id, output)
+	// Debug logging, guarded so the args aren't boxed on every call
+	if isLogEnabled(p.Logger, LogTrace) {
+		logAt(p.Logger, LogTrace, "[MemcachedConnection][IsOpen][%s/%s] --> %v", p.Url, p.Id, output)
+	}
 
 	return output
 }
@@ -455,8 +584,10 @@ func (p *MemcachedConnection) IsOpen() bool {
 func (p *MemcachedConnection) IsClosed() bool {
 	output := nil == p.client
 
-	// Debug logging
-	p.Logger.Trace("[MemcachedConnection][IsClosed][%s/%s] --> %v", p.Url, p.Id, output)
+	// Debug logging, guarded so the args aren't boxed on every call
+	if isLogEnabled(p.Logger, LogTrace) {
+		logAt(p.Logger, LogTrace, "[MemcachedConnection][IsClosed][%s/%s] --> %v", p.Url, p.Id, output)
+	}
 
 	return output
 }
@@ -471,7 +602,7 @@ func (p *MemcachedConnection) Open() error {
 	p.client.Timeout = time.Duration(10) * time.Second
 
 	// Log the event
-	p.Logger.Info("[MemcachedConnection][Open][%s/%s] --> Opened!", p.Url, p.Id)
+	logAt(p.Logger, LogInfo, "[MemcachedConnection][Open][%s/%s] --> Opened!", p.Url, p.Id)
 
 	// Perform a basic command on the server
 	item := &memcached.Item{}
@@ -491,7 +622,7 @@ func (p *MemcachedConnection) Open() error {
 		p.client = nil
 
 		// Log the event
-		p.Logger.Error("[MemcachedConnection][Open][%s/%s] --> Error = '%v'", p.Url, p.Id, err)
+		logAt(p.Logger, LogError, "[MemcachedConnection][Open][%s/%s] --> Error = '%v'", p.Url, p.Id, err)
 
 		// Return the error
 		return err
@@ -509,7 +640,7 @@ func (p *MemcachedConnection) Close() (err error) {
 	p.client = nil
 
 	// Log the event
-	p.Logger.Info("[MemcachedConnection][Close][%s/%s] --> Closed!", p.Url, p.Id)
+	logAt(p.Logger, LogInfo, "[MemcachedConnection][Close][%s/%s] --> Closed!", p.Url, p.Id)
 
 	return
 }
@@ -17,10 +17,10 @@ func TestMemcachedConnectionSpecs(t *testing.T) {
 
 // Helpers
 func MemcachedConnectionSpecs(c gospec.Context) {
-	var memcached_connection_logger = log4go.NewDefaultLogger(log4go.CRITICAL)
+	var memcached_connection_logger = MakeLog4goLogger(log4go.NewDefaultLogger(log4go.CRITICAL))
 
 	c.Specify("[MemcachedConnection] New connection is not open", func() {
-		connection := MemcachedConnection{Url: "127.0.0.1:11290", Logger: &memcached_connection_logger}
+		connection := MemcachedConnection{Url: "127.0.0.1:11290", Logger: memcached_connection_logger}
 		defer connection.Close()
 
 		// Should be opposite of each other:
@@ -29,7 +29,7 @@ func MemcachedConnectionSpecs(c gospec.Context) {
 	})
 
 	c.Specify("[MemcachedConnection] Opening connection to Invalid Host/Port has errors", func() {
-		connection := MemcachedConnection{Url: "127.0.0.1:11291", Logger: &memcached_connection_logger}
+		connection := MemcachedConnection{Url: "127.0.0.1:11291", Logger: memcached_connection_logger}
 		defer connection.Close()
 
 		c.Expect(nil != connection.Open(), gospec.Equals, true)
@@ -37,8 +37,8 @@ func MemcachedConnectionSpecs(c gospec.Context) {
 	})
 
 	c.Specify("[MemcachedConnection] Opening connection to Valid Host/Port has no errors", func() {
-		logger := log4go.NewDefaultLogger(log4go.CRITICAL)
-		server, err := StartMemcachedServer(&logger)
+		logger := MakeLog4goLogger(log4go.NewDefaultLogger(log4go.CRITICAL))
+		server, err := StartMemcachedServer(logger)
 		if nil != err {
 			panic(err)
 		}
@@ -50,8 +50,8 @@ func MemcachedConnectionSpecs(c gospec.Context) {
 	})
 
 	c.Specify("[MemcachedConnection] Ping (-->Set-->Delete) (re-)opens the connection automatically", func() {
-		logger := log4go.NewDefaultLogger(log4go.CRITICAL)
-		server, err := StartMemcachedServer(&logger)
+		logger := MakeLog4goLogger(log4go.NewDefaultLogger(log4go.CRITICAL))
+		server, err := StartMemcachedServer(logger)
 		if nil != err {
 			panic(err)
 		}
@@ -77,8 +77,8 @@ func MemcachedConnectionSpecs(c gospec.Context) {
 	})
 
 	c.Specify("[MemcachedConnection] Clone+Ping (-->Set-->Delete) (re-)opens the connection automatically", func() {
-		logger := log4go.NewDefaultLogger(log4go.CRITICAL)
-		server, err := StartMemcachedServer(&logger)
+		logger := MakeLog4goLogger(log4go.NewDefaultLogger(log4go.CRITICAL))
+		server, err := StartMemcachedServer(logger)
 		if nil != err {
 			panic(err)
 		}
@@ -107,8 +107,8 @@ func MemcachedConnectionSpecs(c gospec.Context) {
 	})
 
 	c.Specify("[MemcachedConnection][Get] Returns Cache Miss", func() {
-		logger := log4go.NewDefaultLogger(log4go.CRITICAL)
-		server, err := StartMemcachedServer(&logger)
+		logger := MakeLog4goLogger(log4go.NewDefaultLogger(log4go.CRITICAL))
+		server, err := StartMemcachedServer(logger)
 		if nil != err {
 			panic(err)
 		}
@@ -120,8 +120,8 @@ func MemcachedConnectionSpecs(c gospec.Context) {
 	})
 
 	c.Specify("[MemcachedConnection][Set+Get] Returns Value", func() {
-		logger := log4go.NewDefaultLogger(log4go.CRITICAL)
-		server, err := StartMemcachedServer(&logger)
+		logger := MakeLog4goLogger(log4go.NewDefaultLogger(log4go.CRITICAL))
+		server, err := StartMemcachedServer(logger)
 		if nil != err {
 			panic(err)
 		}
@@ -137,8 +137,8 @@ func MemcachedConnectionSpecs(c gospec.Context) {
 	})
 
 	c.Specify("[MemcachedConnection][SetStr+GetStr] Returns Value", func() {
-		logger := log4go.NewDefaultLogger(log4go.CRITICAL)
-		server, err := StartMemcachedServer(&logger)
+		logger := MakeLog4goLogger(log4go.NewDefaultLogger(log4go.CRITICAL))
+		server, err := StartMemcachedServer(logger)
 		if nil != err {
 			panic(err)
 		}
@@ -152,11 +152,49 @@ func MemcachedConnectionSpecs(c gospec.Context) {
 		c.Expect(*ptr, gospec.Equals, "Hello")
 	})
 
+	c.Specify("[MemcachedConnection][IsOpen] Doesn't allocate with the default logger", func() {
+		connection := &MemcachedConnection{Url: "127.0.0.1:11210", Id: "Bob"}
+		defer connection.Close()
+
+		allocs := testing.AllocsPerRun(100, func() {
+			connection.IsOpen()
+			connection.IsClosed()
+		})
+		c.Expect(allocs, gospec.Equals, float64(0))
+	})
+
+	c.Specify("[MemcachedConnection][Get+Set+Delete] Doesn't allocate for logging or tracing by default", func() {
+		server, err := StartMemcachedServer(nil)
+		if nil != err {
+			panic(err)
+		}
+		defer server.Close()
+
+		connection := server.Connection()
+		c.Expect(connection.Ping(), gospec.Equals, nil)
+		item := &memcached.Item{Key: "BOB", Value: []byte("Hello")}
+
+		// Allocations of the client, everything else is logging & tracing
+		get, set, del := connection.client.Get, connection.client.Set, connection.client.Delete
+		client := testing.AllocsPerRun(100, func() {
+			set(item)
+			get("BOB")
+			del("BOB")
+		})
+
+		allocs := testing.AllocsPerRun(100, func() {
+			connection.Set(item)
+			connection.Get("BOB")
+			connection.Delete("BOB")
+		})
+		c.Expect(allocs, gospec.Satisfies, allocs <= client)
+	})
+
 }
 
 func Benchmark_MemcachedConnection_Get(b *testing.B) {
-	logger := log4go.NewDefaultLogger(log4go.CRITICAL)
-	server, err := StartMemcachedServer(&logger)
+	logger := MakeLog4goLogger(log4go.NewDefaultLogger(log4go.CRITICAL))
+	server, err := StartMemcachedServer(logger)
 	if nil != err {
 		panic(err)
 	}
@@ -171,8 +209,8 @@ func Benchmark_MemcachedConnection_Get(b *testing.B) {
 }
 
 func Benchmark_MemcachedConnection_Set(b *testing.B) {
-	logger := log4go.NewDefaultLogger(log4go.CRITICAL)
-	server, err := StartMemcachedServer(&logger)
+	logger := MakeLog4goLogger(log4go.NewDefaultLogger(log4go.CRITICAL))
+	server, err := StartMemcachedServer(logger)
 	if nil != err {
 		panic(err)
 	}
@@ -185,8 +223,8 @@ func Benchmark_MemcachedConnection_Set(b *testing.B) {
 }
 
 func Benchmark_MemcachedConnection_SetGet(b *testing.B) {
-	logger := log4go.NewDefaultLogger(log4go.CRITICAL)
-	server, err := StartMemcachedServer(&logger)
+	logger := MakeLog4goLogger(log4go.NewDefaultLogger(log4go.CRITICAL))
+	server, err := StartMemcachedServer(logger)
 	if nil != err {
 		panic(err)
 	}
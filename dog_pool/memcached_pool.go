@@ -7,18 +7,19 @@ package dog_pool
 import "fmt"
 import "errors"
 import "time"
-import "github.com/alecthomas/log4go"
 
 //
 // Memcached Connection Pool wrapper
 //
 type MemcachedConnectionPool struct {
-	Mode    ConnectionMode         "How should we prepare the connection pool?"
-	Size    int                    "(Max) Pool size"
-	Urls    []string               "Memcached URLs to connect to"
-	Logger  log4go.Logger          "Logger we are using in the connection pool"
-	Timeout time.Duration          "Timeout to use for Memcached Connections"
-	myPool  *ConnectionPoolWrapper "Connection Pool wrapper"
+	Mode      ConnectionMode         "How should we prepare the connection pool?"
+	Size      int                    "(Max) Pool size"
+	Urls      []string               "Memcached URLs to connect to"
+	Logger    Logger                 "(optional) Logger we are using in the connection pool, nil is the NopLogger"
+	Timeout   time.Duration          "Timeout to use for Memcached Connections"
+	Tracer    Tracer                 "(optional) Opens a span for every operation, on every connection"
+	Redaction *RedactionPolicy       "(optional) Hides & truncates the logged values, on every connection"
+	myPool    *ConnectionPoolWrapper "Connection Pool wrapper"
 }
 
 //
@@ -69,7 +70,7 @@ func (p *MemcachedConnectionPool) Open() error {
 		// DON'T Test the connection
 		initfn = func() (interface{}, error) {
 			values := nextUrl()
-			return makeLazyMemcachedConnection(values[0], values[1], p.Timeout, &p.Logger)
+			return makeLazyMemcachedConnection(values[0], values[1], p.Timeout, p.Logger)
 		}
 	case AGRESSIVE:
 		// Create the factory
@@ -77,13 +78,26 @@ func (p *MemcachedConnectionPool) Open() error {
 		// AND Test the connection
 		initfn = func() (interface{}, error) {
 			values := nextUrl()
-			return makeAgressiveMemcachedConnection(values[0], values[1], p.Timeout, &p.Logger)
+			return makeAgressiveMemcachedConnection(values[0], values[1], p.Timeout, p.Logger)
 		}
 		// No mode specified!
 	default:
 		return errors.New(fmt.Sprintf("Invalid connection mode: %v", p.Mode))
 	}
 
+	// Trace & redact every connection
+	if nil != p.Tracer || nil != p.Redaction {
+		makeConnection := initfn
+		initfn = func() (interface{}, error) {
+			connection, err := makeConnection()
+			if nil == err {
+				connection.(*MemcachedConnection).Tracer = p.Tracer
+				connection.(*MemcachedConnection).Redaction = p.Redaction
+			}
+			return connection, err
+		}
+	}
+
 	// Create the new pool
 	pool, err := MakeConnectionPoolWrapper(p.Size, initfn)
 
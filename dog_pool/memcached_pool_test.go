@@ -16,7 +16,7 @@ func TestMemcachedPoolSpecs(t *testing.T) {
 
 // Helpers
 func MemcachedPoolSpecs(c gospec.Context) {
-	var memcached_pool_logger = log4go.NewDefaultLogger(log4go.CRITICAL)
+	var memcached_pool_logger = MakeLog4goLogger(log4go.NewDefaultLogger(log4go.CRITICAL))
 
 	c.Specify("[MemcachedConnectionPool] New Pool is not open", func() {
 		pool := MemcachedConnectionPool{Mode: AGRESSIVE, Size: 0, Urls: []string{}, Logger: memcached_pool_logger}
@@ -92,7 +92,7 @@ func MemcachedPoolSpecs(c gospec.Context) {
 	})
 
 	c.Specify("[MemcachedConnectionPool] Opening connection to Valid Host/Port has no errors", func() {
-		server, err := StartMemcachedServer(&memcached_pool_logger)
+		server, err := StartMemcachedServer(memcached_pool_logger)
 		if nil != err {
 			panic(err)
 		}
@@ -108,7 +108,7 @@ func MemcachedPoolSpecs(c gospec.Context) {
 	})
 
 	c.Specify("[MemcachedConnectionPool] 10x AGRESSIVE Pool Pops 10x open connections", func() {
-		server, err := StartMemcachedServer(&memcached_pool_logger)
+		server, err := StartMemcachedServer(memcached_pool_logger)
 		if nil != err {
 			panic(err)
 		}
@@ -135,7 +135,7 @@ func MemcachedPoolSpecs(c gospec.Context) {
 	})
 
 	c.Specify("[MemcachedConnectionPool] 10x LAZY Pool Pops 10x closed connections", func() {
-		server, err := StartMemcachedServer(&memcached_pool_logger)
+		server, err := StartMemcachedServer(memcached_pool_logger)
 		if nil != err {
 			panic(err)
 		}
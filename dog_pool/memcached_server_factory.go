@@ -2,22 +2,17 @@ package dog_pool
 
 import "fmt"
 import "os/exec"
-import "errors"
 import "time"
-import "github.com/alecthomas/log4go"
 
 type MemcachedServerProcess struct {
 	port       int
-	logger     *log4go.Logger
+	logger     Logger
 	connection *MemcachedConnection
 	cmd        *exec.Cmd
 }
 
-func StartMemcachedServer(logger *log4go.Logger) (*MemcachedServerProcess, error) {
+func StartMemcachedServer(logger Logger) (*MemcachedServerProcess, error) {
 	var err error
-	if nil == logger {
-		return nil, errors.New("Nil logger")
-	}
 
 	server := &MemcachedServerProcess{}
 	server.port, err = findPort()
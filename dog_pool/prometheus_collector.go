@@ -0,0 +1,369 @@
+//
+// Prometheus metrics for pools, commands and batch queues
+//
+// Writes the Prometheus text exposition format directly, without depending on the Prometheus client library.
+//
+// Usage:
+//   collector := MakePrometheusCollector()
+//   connection.Hooks = RedisHooks{collector.RedisHook()}
+//   collector.AddRedisPool("cache", pool)
+//   http.Handle("/metrics", collector)
+//
+
+package dog_pool
+
+import "fmt"
+import "io"
+import "net/http"
+import "sort"
+import "strings"
+import "sync"
+import "github.com/RUNDSP/radix/redis"
+
+//
+// Default histogram buckets for command latencies, in seconds
+//
+var PrometheusLatencyBuckets = []float64{0.0005, 0.001, 0.0025, 0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10}
+
+//
+// Default histogram buckets for batch sizes, in commands
+//
+var PrometheusBatchSizeBuckets = []float64{1, 2, 5, 10, 25, 50, 100, 250, 500, 1000}
+
+//
+// Histogram of observations
+//
+type prometheusHistogram struct {
+	buckets []float64 "Upper bounds of the buckets"
+	counts  []uint64  "Observations per bucket (not cumulative)"
+	sum     float64   "Sum of the observations"
+	count   uint64    "Number of observations"
+}
+
+func makePrometheusHistogram(buckets []float64) *prometheusHistogram {
+	return &prometheusHistogram{buckets: buckets, counts: make([]uint64, len(buckets))}
+}
+
+func (p *prometheusHistogram) observe(value float64) {
+	for i, bound := range p.buckets {
+		if value <= bound {
+			p.counts[i]++
+			break
+		}
+	}
+	p.sum += value
+	p.count++
+}
+
+//
+// Collector for dog_pool metrics
+//
+type PrometheusCollector struct {
+	Namespace string "Prefix of the metric names, defaults to 'dog_pool'"
+
+	mutex          sync.Mutex
+	latencies      map[string]*prometheusHistogram "[command, url] --> latency histogram"
+	errors         map[string]uint64               "[url, class] --> error counter"
+	batch_sizes    map[string]*prometheusHistogram "[queue] --> batch size histogram"
+	redis_pools    map[string]*RedisConnectionPool
+	memcache_pools map[string]*MemcachedConnectionPool
+	batch_queues   map[string]*RedisBatchQueue
+}
+
+//
+// Make a new, empty, collector
+//
+func MakePrometheusCollector() *PrometheusCollector {
+	return &PrometheusCollector{
+		Namespace:      "dog_pool",
+		latencies:      map[string]*prometheusHistogram{},
+		errors:         map[string]uint64{},
+		batch_sizes:    map[string]*prometheusHistogram{},
+		redis_pools:    map[string]*RedisConnectionPool{},
+		memcache_pools: map[string]*MemcachedConnectionPool{},
+		batch_queues:   map[string]*RedisBatchQueue{},
+	}
+}
+
+//
+// ==================================================
+//
+// Registering sources:
+//
+// ==================================================
+//
+
+//
+// Hook recording the latency & errors of every command, attach it to a RedisConnection or RedisConnectionPool
+//
+func (p *PrometheusCollector) RedisHook() RedisHook {
+	return RedisHookFuncs{
+		After: func(call *RedisHookCall, reply *redis.Reply) *redis.Reply {
+			p.ObserveCommand(call.Cmd, call.Connection.Url, call.Elapsed().Seconds(), reply.Err)
+			return reply
+		},
+	}
+}
+
+//
+// Export the size, idle and in-use gauges of the pool
+//
+func (p *PrometheusCollector) AddRedisPool(name string, pool *RedisConnectionPool) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	p.redis_pools[name] = pool
+}
+
+//
+// Export the size, idle and in-use gauges of the pool
+//
+func (p *PrometheusCollector) AddMemcachedPool(name string, pool *MemcachedConnectionPool) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	p.memcache_pools[name] = pool
+}
+
+//
+// Export the depth & capacity gauges of the queue
+//
+func (p *PrometheusCollector) AddRedisBatchQueue(name string, queue *RedisBatchQueue) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	p.batch_queues[name] = queue
+}
+
+//
+// Stop exporting the queue, unless another queue was added under the same name since
+//
+func (p *PrometheusCollector) RemoveRedisBatchQueue(name string, queue *RedisBatchQueue) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	if p.batch_queues[name] == queue {
+		delete(p.batch_queues, name)
+	}
+}
+
+//
+// ==================================================
+//
+// Observations:
+//
+// ==================================================
+//
+
+//
+// Record the latency (in seconds) and error (may be nil) of a command
+//
+func (p *PrometheusCollector) ObserveCommand(cmd, url string, seconds float64, err error) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	key := prometheusLabels("command", strings.ToUpper(cmd), "url", url)
+	histogram, ok := p.latencies[key]
+	if !ok {
+		histogram = makePrometheusHistogram(PrometheusLatencyBuckets)
+		p.latencies[key] = histogram
+	}
+	histogram.observe(seconds)
+
+	if nil != err {
+		p.errors[prometheusLabels("url", url, "class", RedisErrorClass(err))]++
+	}
+}
+
+//
+// Record the number of commands in a batch run by a RedisBatchQueue
+//
+func (p *PrometheusCollector) ObserveBatchSize(queue string, size int) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	key := prometheusLabels("queue", queue)
+	histogram, ok := p.batch_sizes[key]
+	if !ok {
+		histogram = makePrometheusHistogram(PrometheusBatchSizeBuckets)
+		p.batch_sizes[key] = histogram
+	}
+	histogram.observe(float64(size))
+}
+
+//
+// ==================================================
+//
+// Exposition:
+//
+// ==================================================
+//
+
+//
+// Serve the metrics in the Prometheus text exposition format
+//
+func (p *PrometheusCollector) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4")
+	p.WriteTo(w)
+}
+
+//
+// Write the metrics in the Prometheus text exposition format
+//
+func (p *PrometheusCollector) WriteTo(w io.Writer) (int64, error) {
+	// Query the pools & queues without holding the mutex, they take locks of their own
+	p.mutex.Lock()
+	redis_pools := make(map[string]*RedisConnectionPool, len(p.redis_pools))
+	for name, pool := range p.redis_pools {
+		redis_pools[name] = pool
+	}
+	memcache_pools := make(map[string]*MemcachedConnectionPool, len(p.memcache_pools))
+	for name, pool := range p.memcache_pools {
+		memcache_pools[name] = pool
+	}
+	batch_queues := make(map[string]*RedisBatchQueue, len(p.batch_queues))
+	for name, queue := range p.batch_queues {
+		batch_queues[name] = queue
+	}
+	p.mutex.Unlock()
+
+	// Pools
+	sizes := map[string]int{}
+	idles := map[string]int{}
+	for name, pool := range redis_pools {
+		if pool.IsOpen() {
+			labels := prometheusLabels("pool", name, "backend", "redis")
+			sizes[labels] = pool.Size
+			idles[labels] = pool.Len()
+		}
+	}
+	for name, pool := range memcache_pools {
+		if pool.IsOpen() {
+			labels := prometheusLabels("pool", name, "backend", "memcached")
+			sizes[labels] = pool.Size
+			idles[labels] = pool.Len()
+		}
+	}
+
+	// Batch queues
+	depths := map[string]int{}
+	lane_depths := map[string]int{}
+	stats := map[string]RedisBatchQueueStats{}
+	for name, queue := range batch_queues {
+		labels := prometheusLabels("queue", name)
+		stats[labels] = queue.Stats()
+		depths[labels] = stats[labels].Len
+		for lane, depth := range stats[labels].Lanes {
+			lane_depths[prometheusLabels("queue", name, "lane", lane)] = depth
+		}
+	}
+
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	lines := make([]string, 64)[0:0]
+
+	// Command latencies & errors
+	lines = p.appendHistograms(lines, "redis_command_duration_seconds", "Latency of Redis commands, in seconds", p.latencies)
+	lines = p.appendHeader(lines, "redis_command_errors_total", "Redis command errors, by error class", "counter")
+	for _, labels := range sortedKeys(p.errors) {
+		lines = append(lines, fmt.Sprintf("%s_redis_command_errors_total{%s} %d", p.Namespace, labels, p.errors[labels]))
+	}
+
+	// Pools
+	lines = p.appendGauges(lines, "pool_size", "Number of connections in the pool", sizes, func(labels string) int { return sizes[labels] })
+	lines = p.appendGauges(lines, "pool_idle", "Number of idle connections in the pool", sizes, func(labels string) int { return idles[labels] })
+	lines = p.appendGauges(lines, "pool_in_use", "Number of connections popped from the pool", sizes, func(labels string) int { return sizes[labels] - idles[labels] })
+
+	// Batch queues
+	lines = p.appendGauges(lines, "batch_queue_depth", "Number of commands waiting in the RedisBatchQueue", depths, func(labels string) int { return depths[labels] })
+	lines = p.appendGauges(lines, "batch_queue_workers", "Number of workers running in the RedisBatchQueue", depths, func(labels string) int { return stats[labels].Workers })
+	lines = p.appendGauges(lines, "batch_queue_lane_depth", "Number of commands waiting in the RedisBatchQueue's named lanes", lane_depths, func(labels string) int { return lane_depths[labels] })
+	lines = p.appendGauges(lines, "batch_queue_capacity", "Capacity of the RedisBatchQueue", depths, func(labels string) int { return stats[labels].Cap })
+	lines = p.appendHeader(lines, "batch_queue_dropped_total", "Commands dropped by the RedisBatchQueue's overflow policy", "counter")
+	for _, labels := range sortedKeys(depths) {
+		lines = append(lines, fmt.Sprintf("%s_batch_queue_dropped_total{%s} %d", p.Namespace, labels, stats[labels].Dropped))
+	}
+	lines = p.appendHeader(lines, "batch_queue_spilled_total", "Commands spilled by the RedisBatchQueue's overflow policy", "counter")
+	for _, labels := range sortedKeys(depths) {
+		lines = append(lines, fmt.Sprintf("%s_batch_queue_spilled_total{%s} %d", p.Namespace, labels, stats[labels].Spilled))
+	}
+	lines = p.appendHeader(lines, "batch_queue_coalesced_total", "Commands merged into another by the RedisBatchQueue", "counter")
+	for _, labels := range sortedKeys(depths) {
+		lines = append(lines, fmt.Sprintf("%s_batch_queue_coalesced_total{%s} %d", p.Namespace, labels, stats[labels].Coalesced))
+	}
+	lines = p.appendHeader(lines, "batch_queue_throttled_seconds_total", "Time the RedisBatchQueue's workers waited on the rate limit, in seconds", "counter")
+	for _, labels := range sortedKeys(depths) {
+		lines = append(lines, fmt.Sprintf("%s_batch_queue_throttled_seconds_total{%s} %v", p.Namespace, labels, stats[labels].Throttled.Seconds()))
+	}
+	lines = p.appendHistograms(lines, "batch_queue_batch_size", "Number of commands per batch run by the RedisBatchQueue", p.batch_sizes)
+
+	n, err := io.WriteString(w, strings.Join(lines, "\n")+"\n")
+	return int64(n), err
+}
+
+func (p *PrometheusCollector) appendHeader(lines []string, name, help, kind string) []string {
+	return append(lines,
+		fmt.Sprintf("# HELP %s_%s %s", p.Namespace, name, help),
+		fmt.Sprintf("# TYPE %s_%s %s", p.Namespace, name, kind),
+	)
+}
+
+func (p *PrometheusCollector) appendGauges(lines []string, name, help string, keys map[string]int, value func(labels string) int) []string {
+	lines = p.appendHeader(lines, name, help, "gauge")
+	for _, labels := range sortedKeys(keys) {
+		lines = append(lines, fmt.Sprintf("%s_%s{%s} %d", p.Namespace, name, labels, value(labels)))
+	}
+	return lines
+}
+
+func (p *PrometheusCollector) appendHistograms(lines []string, name, help string, histograms map[string]*prometheusHistogram) []string {
+	lines = p.appendHeader(lines, name, help, "histogram")
+	for _, labels := range sortedKeys(histograms) {
+		histogram := histograms[labels]
+
+		cumulative := uint64(0)
+		for i, bound := range histogram.buckets {
+			cumulative += histogram.counts[i]
+			lines = append(lines, fmt.Sprintf("%s_%s_bucket{%s,le=\"%v\"} %d", p.Namespace, name, labels, bound, cumulative))
+		}
+		lines = append(lines,
+			fmt.Sprintf("%s_%s_bucket{%s,le=\"+Inf\"} %d", p.Namespace, name, labels, histogram.count),
+			fmt.Sprintf("%s_%s_sum{%s} %v", p.Namespace, name, labels, histogram.sum),
+			fmt.Sprintf("%s_%s_count{%s} %d", p.Namespace, name, labels, histogram.count),
+		)
+	}
+	return lines
+}
+
+//
+// Format the label pairs: name, value, name, value, ... --> name="value",name="value"
+//
+func prometheusLabels(pairs ...string) string {
+	escaper := strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+	labels := make([]string, len(pairs)/2)
+	for i := range labels {
+		labels[i] = fmt.Sprintf("%s=\"%s\"", pairs[2*i], escaper.Replace(pairs[2*i+1]))
+	}
+	return strings.Join(labels, ",")
+}
+
+//
+// Sorted keys of the map, so the output is stable
+//
+func sortedKeys(values interface{}) []string {
+	output := []string{}
+	switch typed := values.(type) {
+	case map[string]uint64:
+		for key := range typed {
+			output = append(output, key)
+		}
+	case map[string]int:
+		for key := range typed {
+			output = append(output, key)
+		}
+	case map[string]*prometheusHistogram:
+		for key := range typed {
+			output = append(output, key)
+		}
+	}
+	sort.Strings(output)
+	return output
+}
@@ -0,0 +1,127 @@
+package dog_pool
+
+import "bytes"
+import "context"
+import "errors"
+import "net/http/httptest"
+import "strings"
+import "testing"
+import "github.com/orfjackal/gospec/src/gospec"
+import "github.com/alecthomas/log4go"
+
+func TestPrometheusCollectorSpecs(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping test in benchmark mode.")
+		return
+	}
+	r := gospec.NewRunner()
+	r.AddSpec(PrometheusCollectorSpecs)
+	gospec.MainGoTest(r, t)
+}
+
+// Helpers
+func PrometheusCollectorSpecs(c gospec.Context) {
+
+	c.Specify("[PrometheusCollector][ObserveCommand] Records latency histograms and error counters", func() {
+		collector := MakePrometheusCollector()
+		collector.ObserveCommand("get", "127.0.0.1:6379", 0.002, nil)
+		collector.ObserveCommand("GET", "127.0.0.1:6379", 0.2, errors.New("WRONGTYPE"))
+
+		buffer := bytes.NewBuffer(nil)
+		collector.WriteTo(buffer)
+		output := buffer.String()
+
+		c.Expect(strings.Contains(output, "# TYPE dog_pool_redis_command_duration_seconds histogram"), gospec.Equals, true)
+		c.Expect(strings.Contains(output, `dog_pool_redis_command_duration_seconds_bucket{command="GET",url="127.0.0.1:6379",le="0.0025"} 1`), gospec.Equals, true)
+		c.Expect(strings.Contains(output, `dog_pool_redis_command_duration_seconds_bucket{command="GET",url="127.0.0.1:6379",le="0.25"} 2`), gospec.Equals, true)
+		c.Expect(strings.Contains(output, `dog_pool_redis_command_duration_seconds_bucket{command="GET",url="127.0.0.1:6379",le="+Inf"} 2`), gospec.Equals, true)
+		c.Expect(strings.Contains(output, `dog_pool_redis_command_duration_seconds_count{command="GET",url="127.0.0.1:6379"} 2`), gospec.Equals, true)
+		c.Expect(strings.Contains(output, `dog_pool_redis_command_errors_total{url="127.0.0.1:6379",class="command"} 1`), gospec.Equals, true)
+	})
+
+	c.Specify("[PrometheusCollector][ObserveBatchSize] Records batch size histograms", func() {
+		collector := MakePrometheusCollector()
+		collector.ObserveBatchSize("events", 3)
+
+		buffer := bytes.NewBuffer(nil)
+		collector.WriteTo(buffer)
+		output := buffer.String()
+
+		c.Expect(strings.Contains(output, `dog_pool_batch_queue_batch_size_bucket{queue="events",le="2"} 0`), gospec.Equals, true)
+		c.Expect(strings.Contains(output, `dog_pool_batch_queue_batch_size_bucket{queue="events",le="5"} 1`), gospec.Equals, true)
+		c.Expect(strings.Contains(output, `dog_pool_batch_queue_batch_size_sum{queue="events"} 3`), gospec.Equals, true)
+	})
+
+	c.Specify("[PrometheusCollector][ServeHTTP] Exports the hook, pool and queue metrics", func() {
+		logger := MakeLog4goLogger(log4go.NewDefaultLogger(log4go.CRITICAL))
+		server, err := StartRedisServer(logger)
+		if nil != err {
+			panic(err)
+		}
+		defer server.Close()
+
+		collector := MakePrometheusCollector()
+		url := server.Connection().Url
+
+		pool := &RedisConnectionPool{Mode: LAZY, Size: 2, Urls: []string{url}, Logger: logger, Hooks: RedisHooks{collector.RedisHook()}}
+		c.Expect(pool.Open(), gospec.Equals, nil)
+		defer pool.Close()
+		collector.AddRedisPool("cache", pool)
+
+		connection, err := pool.Pop()
+		c.Expect(err, gospec.Equals, nil)
+		c.Expect(connection.Cmd("SET", "Key", "Value").Err, gospec.Equals, nil)
+
+		queue := &RedisBatchQueue{Logger: logger, Connection: server.Connection(), QueueSize: 10, WorkersSize: 1, WorkersBatchSize: 5, Name: "events", Metrics: collector}
+		c.Expect(queue.Open(), gospec.Equals, nil)
+		defer queue.Close(context.Background())
+
+		recorder := httptest.NewRecorder()
+		collector.ServeHTTP(recorder, httptest.NewRequest("GET", "/metrics", nil))
+		output := recorder.Body.String()
+
+		c.Expect(recorder.Header().Get("Content-Type"), gospec.Equals, "text/plain; version=0.0.4")
+		c.Expect(strings.Contains(output, `dog_pool_redis_command_duration_seconds_count{command="SET",url="`+url+`"} 1`), gospec.Equals, true)
+		c.Expect(strings.Contains(output, `dog_pool_pool_size{pool="cache",backend="redis"} 2`), gospec.Equals, true)
+		c.Expect(strings.Contains(output, `dog_pool_pool_idle{pool="cache",backend="redis"} 1`), gospec.Equals, true)
+		c.Expect(strings.Contains(output, `dog_pool_pool_in_use{pool="cache",backend="redis"} 1`), gospec.Equals, true)
+		c.Expect(strings.Contains(output, `dog_pool_batch_queue_depth{queue="events"} 0`), gospec.Equals, true)
+		c.Expect(strings.Contains(output, `dog_pool_batch_queue_capacity{queue="events"} 10`), gospec.Equals, true)
+		c.Expect(strings.Contains(output, `dog_pool_batch_queue_workers{queue="events"} 1`), gospec.Equals, true)
+		c.Expect(strings.Contains(output, `dog_pool_batch_queue_dropped_total{queue="events"} 0`), gospec.Equals, true)
+		c.Expect(strings.Contains(output, `dog_pool_batch_queue_spilled_total{queue="events"} 0`), gospec.Equals, true)
+		c.Expect(strings.Contains(output, `dog_pool_batch_queue_coalesced_total{queue="events"} 0`), gospec.Equals, true)
+		c.Expect(strings.Contains(output, `dog_pool_batch_queue_throttled_seconds_total{queue="events"} 0`), gospec.Equals, true)
+	})
+
+	c.Specify("[PrometheusCollector][RemoveRedisBatchQueue] Only open, named queues are exported", func() {
+		collector := MakePrometheusCollector()
+		connection := &RedisConnection{Url: "127.0.0.1:6991"}
+
+		unnamed := &RedisBatchQueue{Connection: connection, QueueSize: 10, WorkersSize: 1, WorkersBatchSize: 1, Metrics: collector}
+		c.Expect(unnamed.Open().Error(), gospec.Equals, "[RedisBatchQueue][Open] Name is required to export the Metrics!")
+
+		queue := &RedisBatchQueue{Connection: connection, QueueSize: 10, WorkersSize: 1, WorkersBatchSize: 1, Name: "events", Metrics: collector}
+		c.Expect(queue.Open(), gospec.Equals, nil)
+
+		output := &bytes.Buffer{}
+		collector.WriteTo(output)
+		c.Expect(strings.Contains(output.String(), `dog_pool_batch_queue_capacity{queue="events"} 10`), gospec.Equals, true)
+
+		// Another queue under the same name isn't removed
+		collector.RemoveRedisBatchQueue("events", unnamed)
+		output.Reset()
+		collector.WriteTo(output)
+		c.Expect(strings.Contains(output.String(), `queue="events"`), gospec.Equals, true)
+
+		_, err := queue.Close(context.Background())
+		c.Expect(err, gospec.Equals, nil)
+		output.Reset()
+		collector.WriteTo(output)
+		c.Expect(strings.Contains(output.String(), `queue="events"`), gospec.Equals, false)
+	})
+
+	c.Specify("[prometheusLabels] Escapes the label values", func() {
+		c.Expect(prometheusLabels("a", `x"y\z`, "b", "1\n2"), gospec.Equals, `a="x\"y\\z",b="1\n2"`)
+	})
+}
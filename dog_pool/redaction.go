@@ -0,0 +1,203 @@
+//
+// Redaction & truncation of the values written to the logs
+//
+// Usage:
+//   dog_pool.DefaultRedactionPolicy = &dog_pool.RedactionPolicy{
+//     Commands:       []string{"AUTH", "SET"},
+//     Keys:           []string{"session:*"},
+//     MaxValueLength: 256,
+//     HashKeys:       true,
+//   }
+//
+
+package dog_pool
+
+import "crypto/sha256"
+import "fmt"
+import "path"
+import "strings"
+
+//
+// Policy applied to commands, keys and values before they are logged.
+// A nil policy logs everything as-is.
+//
+type RedactionPolicy struct {
+	Commands       []string "Commands whose values are hidden, i.e. AUTH, SET, Set (memcached)"
+	Keys           []string "Glob patterns (see path.Match) of keys whose values are hidden, i.e. 'session:*'"
+	MaxValueLength int      "Truncate arguments, values & replies to this many bytes, 0 is unlimited"
+	HashKeys       bool     "Log a hash of each key instead of the key"
+}
+
+//
+// Policy used by connections without a policy of their own, and by RedisBatchCommand.String()
+//
+var DefaultRedactionPolicy *RedactionPolicy
+
+//
+// Replaces hidden values in the logs
+//
+var RedactedValue = "[REDACTED]"
+
+//
+// Use the policy, or fallback to the DefaultRedactionPolicy
+//
+func redactionPolicyOr(policy *RedactionPolicy) *RedactionPolicy {
+	if nil != policy {
+		return policy
+	}
+	return DefaultRedactionPolicy
+}
+
+//
+// Should the values of the command (and its reply) be hidden?
+//
+func (p *RedactionPolicy) RedactsCommand(cmd string, keys ...string) bool {
+	if nil == p {
+		return false
+	}
+
+	for _, redacted := range p.Commands {
+		if strings.EqualFold(redacted, cmd) {
+			return true
+		}
+	}
+
+	for _, key := range keys {
+		for _, pattern := range p.Keys {
+			if matched, _ := path.Match(pattern, key); matched {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+//
+// Format the key, hashed if HashKeys is set
+//
+func (p *RedactionPolicy) FormatKey(key string) string {
+	if nil == p {
+		return key
+	}
+	if p.HashKeys {
+		sum := sha256.Sum256([]byte(key))
+		return fmt.Sprintf("#%x", sum[:8])
+	}
+	return p.truncate(key)
+}
+
+//
+// Format the keys, hashed if HashKeys is set
+//
+func (p *RedactionPolicy) FormatKeys(keys []string) []string {
+	output := make([]string, len(keys))
+	for i, key := range keys {
+		output[i] = p.FormatKey(key)
+	}
+	return output
+}
+
+//
+// Format the value, hidden if redact is true, or truncated to MaxValueLength
+//
+func (p *RedactionPolicy) FormatValue(redact bool, value []byte) string {
+	switch {
+	case nil == p:
+		return string(value)
+	case redact:
+		return RedactedValue
+	default:
+		return p.truncate(string(value))
+	}
+}
+
+//
+// Format a Redis command & its arguments:
+// - Keys are hashed if HashKeys is set
+// - All other arguments are hidden if the command or any key is redacted, or truncated to MaxValueLength
+// - All arguments of commands missing from RedisNamespaceCommands are hidden, their keys are unknown
+//
+// Returns:
+//   "CMD arg arg ...", true  --> The reply should be hidden too
+//   "CMD arg arg ...", false --> The reply may be logged
+//
+func (p *RedactionPolicy) FormatCommand(cmd string, args []interface{}) (string, bool) {
+	args = flattenArgs(args)
+
+	is_key, keys, known := redisCommandKeyArgs(cmd, args)
+	redact := (!known && nil != p) || p.RedactsCommand(cmd, keys...)
+
+	words := make([]string, 1+len(args))[0:0]
+	words = append(words, cmd)
+	for i, arg := range args {
+		if is_key[i] {
+			words = append(words, p.FormatKey(toArgString(arg)))
+		} else {
+			words = append(words, p.FormatValue(redact, []byte(toArgString(arg))))
+		}
+	}
+
+	return strings.Join(words, " "), redact
+}
+
+//
+// Which arguments of the command are keys?
+//
+// Returns:
+//   is_key, keys, true   --> The command is in RedisNamespaceCommands, positions past the arguments are skipped
+//   is_key, nil,  false  --> Unknown command, no argument is known to be a key
+//
+func redisCommandKeyArgs(cmd string, args []interface{}) ([]bool, []string, bool) {
+	is_key := make([]bool, len(args))
+	positions, ok := RedisNamespaceCommands[strings.ToUpper(cmd)]
+	if !ok {
+		return is_key, nil, false
+	}
+
+	keys := make([]string, len(args))[0:0]
+	for _, i := range positions(args) {
+		if i < 0 || i >= len(args) {
+			continue
+		}
+		is_key[i] = true
+		keys = append(keys, toArgString(args[i]))
+	}
+	return is_key, keys, true
+}
+
+//
+// Truncate the string to MaxValueLength bytes
+//
+func (p *RedactionPolicy) truncate(value string) string {
+	if p.MaxValueLength <= 0 || len(value) <= p.MaxValueLength {
+		return value
+	}
+	return fmt.Sprintf("%s...(%d bytes)", value[:p.MaxValueLength], len(value))
+}
+
+//
+// Key formatted by the policy only when it is logged, see fmt.Stringer
+//
+type redactedKey struct {
+	policy *RedactionPolicy
+	key    string
+}
+
+func (p redactedKey) String() string {
+	return p.policy.FormatKey(p.key)
+}
+
+//
+// Value formatted by the policy only when it is logged, see fmt.Stringer
+//
+type redactedValue struct {
+	policy *RedactionPolicy
+	cmd    string
+	key    string
+	value  []byte
+}
+
+func (p redactedValue) String() string {
+	return p.policy.FormatValue(p.policy.RedactsCommand(p.cmd, p.key), p.value)
+}
@@ -0,0 +1,156 @@
+package dog_pool
+
+import "bytes"
+import "log"
+import "strings"
+import "testing"
+import "github.com/orfjackal/gospec/src/gospec"
+
+func TestRedactionPolicySpecs(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping test in benchmark mode.")
+		return
+	}
+	r := gospec.NewRunner()
+	r.AddSpec(RedactionPolicySpecs)
+	gospec.MainGoTest(r, t)
+}
+
+// Helpers
+func RedactionPolicySpecs(c gospec.Context) {
+
+	c.Specify("[RedactionPolicy] Nil policy logs everything as-is", func() {
+		var policy *RedactionPolicy
+		c.Expect(policy.RedactsCommand("AUTH"), gospec.Equals, false)
+		c.Expect(policy.FormatKey("Key"), gospec.Equals, "Key")
+		c.Expect(policy.FormatValue(true, []byte("Value")), gospec.Equals, "Value")
+
+		text, redact := policy.FormatCommand("SET", []interface{}{"Key", "Value"})
+		c.Expect(text, gospec.Equals, "SET Key Value")
+		c.Expect(redact, gospec.Equals, false)
+	})
+
+	c.Specify("[RedactionPolicy] Redacts listed commands & key patterns", func() {
+		policy := &RedactionPolicy{Commands: []string{"auth", "Set"}, Keys: []string{"session:*"}}
+		c.Expect(policy.RedactsCommand("AUTH"), gospec.Equals, true)
+		c.Expect(policy.RedactsCommand("GET", "user:1"), gospec.Equals, false)
+		c.Expect(policy.RedactsCommand("GET", "user:1", "session:1"), gospec.Equals, true)
+
+		text, redact := policy.FormatCommand("AUTH", []interface{}{"secret"})
+		c.Expect(text, gospec.Equals, "AUTH [REDACTED]")
+		c.Expect(redact, gospec.Equals, true)
+
+		text, redact = policy.FormatCommand("HSET", []interface{}{"session:1", "token", []byte("secret")})
+		c.Expect(text, gospec.Equals, "HSET session:1 [REDACTED] [REDACTED]")
+		c.Expect(redact, gospec.Equals, true)
+
+		text, redact = policy.FormatCommand("HSET", []interface{}{"user:1", "name", "Bob"})
+		c.Expect(text, gospec.Equals, "HSET user:1 name Bob")
+		c.Expect(redact, gospec.Equals, false)
+	})
+
+	c.Specify("[RedactionPolicy] Truncates long values", func() {
+		policy := &RedactionPolicy{MaxValueLength: 4}
+		c.Expect(policy.FormatValue(false, []byte("1234")), gospec.Equals, "1234")
+		c.Expect(policy.FormatValue(false, []byte("123456")), gospec.Equals, "1234...(6 bytes)")
+
+		text, _ := policy.FormatCommand("MSET", []interface{}{"A", "123456", "B", "1"})
+		c.Expect(text, gospec.Equals, "MSET A 1234...(6 bytes) B 1")
+	})
+
+	c.Specify("[RedactionPolicy] Hashes keys", func() {
+		policy := &RedactionPolicy{HashKeys: true}
+		hashed := policy.FormatKey("user:1")
+		c.Expect(hashed, gospec.Satisfies, strings.HasPrefix(hashed, "#") && 17 == len(hashed))
+		c.Expect(policy.FormatKey("user:1"), gospec.Equals, hashed)
+		c.Expect(policy.FormatKey("user:2") != hashed, gospec.Equals, true)
+
+		text, _ := policy.FormatCommand("SET", []interface{}{"user:1", "Bob"})
+		c.Expect(text, gospec.Equals, "SET "+hashed+" Bob")
+		c.Expect(redactedKey{policy: policy, key: "user:1"}.String(), gospec.Equals, hashed)
+	})
+
+	c.Specify("[RedactionPolicy] Hides every argument of unknown commands", func() {
+		policy := &RedactionPolicy{HashKeys: true}
+		text, redact := policy.FormatCommand("UNKNOWNCMD", []interface{}{"user:1", "Bob"})
+		c.Expect(text, gospec.Equals, "UNKNOWNCMD [REDACTED] [REDACTED]")
+		c.Expect(redact, gospec.Equals, true)
+
+		var nil_policy *RedactionPolicy
+		text, redact = nil_policy.FormatCommand("UNKNOWNCMD", []interface{}{"user:1", "Bob"})
+		c.Expect(text, gospec.Equals, "UNKNOWNCMD user:1 Bob")
+		c.Expect(redact, gospec.Equals, false)
+	})
+
+	c.Specify("[RedactionPolicy] Formats commands missing their key arguments", func() {
+		policy := &RedactionPolicy{HashKeys: true}
+		for _, cmd := range []string{"ZUNIONSTORE", "ZINTERSTORE", "ZDIFFSTORE"} {
+			text, redact := policy.FormatCommand(cmd, []interface{}{"Dest", "3", "A"})
+			c.Expect(text, gospec.Equals, cmd+" "+policy.FormatKey("Dest")+" 3 "+policy.FormatKey("A"))
+			c.Expect(redact, gospec.Equals, false)
+
+			text, _ = policy.FormatCommand(cmd, []interface{}{})
+			c.Expect(text, gospec.Equals, cmd)
+		}
+
+		defer func(policy *RedactionPolicy) { DefaultRedactionPolicy = policy }(DefaultRedactionPolicy)
+		DefaultRedactionPolicy = policy
+
+		value := &RedisBatchCommand{cmd: "ZUNIONSTORE", args: [][]byte{[]byte("Dest"), []byte("2")}}
+		c.Expect(value.String(), gospec.Equals, "RedisBatchCommand { Cmd=[ZUNIONSTORE], Args.Length=[2], Arg[0]=["+policy.FormatKey("Dest")+"], Arg[1]=[2], Reply=[(*redis.Reply)(nil)] }")
+
+		value = &RedisBatchCommand{cmd: "UNKNOWNCMD", args: [][]byte{[]byte("user:1")}}
+		c.Expect(value.String(), gospec.Equals, "RedisBatchCommand { Cmd=[UNKNOWNCMD], Args.Length=[1], Arg[0]=[[REDACTED]], Reply=[(*redis.Reply)(nil)] }")
+	})
+
+	c.Specify("[RedisBatchCommand][String] Applies the DefaultRedactionPolicy", func() {
+		defer func(policy *RedactionPolicy) { DefaultRedactionPolicy = policy }(DefaultRedactionPolicy)
+		DefaultRedactionPolicy = &RedactionPolicy{Keys: []string{"session:*"}}
+
+		value := MakeRedisBatchCommandSet("session:1", []byte("secret"))
+		c.Expect(value.String(), gospec.Equals, "RedisBatchCommand { Cmd=[SET], Args.Length=[2], Arg[0]=[session:1], Arg[1]=[[REDACTED]], Reply=[(*redis.Reply)(nil)] }")
+	})
+
+	c.Specify("[RedisConnection] Redacts the logged commands & replies", func() {
+		buffer := bytes.NewBuffer(nil)
+		logger := MakeStdLogger(log.New(buffer, "", 0), LogFinest)
+		server, err := StartRedisServer(logger)
+		if nil != err {
+			panic(err)
+		}
+		defer server.Close()
+
+		connection := server.Connection()
+		connection.Redaction = &RedactionPolicy{Keys: []string{"session:*"}}
+
+		c.Expect(connection.Cmd("SET", "session:1", "secret").Err, gospec.Equals, nil)
+		value, err := connection.Cmd("GET", "session:1").Str()
+		c.Expect(err, gospec.Equals, nil)
+		c.Expect(value, gospec.Equals, "secret")
+
+		c.Expect(strings.Contains(buffer.String(), "session:1"), gospec.Equals, true)
+		c.Expect(strings.Contains(buffer.String(), "secret"), gospec.Equals, false)
+	})
+
+	c.Specify("[MemcachedConnection] Redacts the logged keys & values", func() {
+		buffer := bytes.NewBuffer(nil)
+		logger := MakeStdLogger(log.New(buffer, "", 0), LogFinest)
+		server, err := StartMemcachedServer(logger)
+		if nil != err {
+			panic(err)
+		}
+		defer server.Close()
+
+		connection := server.Connection()
+		connection.Redaction = &RedactionPolicy{Commands: []string{"Set", "Get"}, HashKeys: true}
+
+		c.Expect(connection.SetStr("session:1", "secret", 10), gospec.Equals, nil)
+		value, err := connection.GetStr("session:1")
+		c.Expect(err, gospec.Equals, nil)
+		c.Expect(*value, gospec.Equals, "secret")
+
+		c.Expect(strings.Contains(buffer.String(), "[REDACTED]"), gospec.Equals, true)
+		c.Expect(strings.Contains(buffer.String(), "session:1"), gospec.Equals, false)
+		c.Expect(strings.Contains(buffer.String(), "secret"), gospec.Equals, false)
+	})
+}
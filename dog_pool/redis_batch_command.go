@@ -10,19 +10,52 @@ import "github.com/RUNDSP/radix/redis"
 // Queued Redis Command & Reply
 //
 type RedisBatchCommand struct {
-	cmd   string "Command we are executing"
-	args  [][]byte
-	reply *redis.Reply
+	cmd    string "Command we are executing"
+	args   [][]byte
+	reply  *redis.Reply
+	future *RedisBatchFuture "(optional) Resolved once the reply is filled in, see RedisBatchQueue.RunAsyncFuture"
+
+	coalesced RedisBatchCommands "(optional) Commands merged into this one, they share its reply, see RedisBatchQueue.CoalesceWindow"
 }
 
+//
+// Format as a string, the DefaultRedactionPolicy is applied to the keys, values & reply
+//
 func (p *RedisBatchCommand) String() string {
+	policy := DefaultRedactionPolicy
+
+	// Which arguments are keys? All arguments of unknown commands are hidden
+	is_key := make([]bool, len(p.args))
+	redact := false
+	if nil != policy {
+		var keys []string
+		var known bool
+		is_key, keys, known = redisCommandKeyArgs(p.cmd, p.interfaceArgs())
+		redact = !known || policy.RedactsCommand(p.cmd, keys...)
+	}
+
 	lines := make([]string, 3+len(p.args))[0:0]
 	lines = append(lines, fmt.Sprintf("Cmd=[%v]", p.cmd))
 	lines = append(lines, fmt.Sprintf("Args.Length=[%v]", len(p.args)))
 	for i, arg := range p.args {
-		lines = append(lines, fmt.Sprintf("Arg[%v]=[%v]", i, string(arg)))
+		if is_key[i] {
+			lines = append(lines, fmt.Sprintf("Arg[%v]=[%v]", i, policy.FormatKey(string(arg))))
+		} else {
+			lines = append(lines, fmt.Sprintf("Arg[%v]=[%v]", i, policy.FormatValue(redact, arg)))
+		}
+	}
+
+	switch {
+	case nil == policy || nil == p.reply:
+		lines = append(lines, fmt.Sprintf("Reply=[%#v]", p.reply))
+	case redis.BulkReply == p.reply.Type:
+		b, _ := p.reply.Bytes()
+		lines = append(lines, fmt.Sprintf("Reply=[%v %v]", redisReplyTypeName(p.reply), policy.FormatValue(redact, b)))
+	case redis.MultiReply == p.reply.Type:
+		lines = append(lines, fmt.Sprintf("Reply=[%v %v elements]", redisReplyTypeName(p.reply), len(p.reply.Elems)))
+	default:
+		lines = append(lines, fmt.Sprintf("Reply=[%v %v]", redisReplyTypeName(p.reply), p.reply))
 	}
-	lines = append(lines, fmt.Sprintf("Reply=[%#v]", p.reply))
 	return fmt.Sprintf("RedisBatchCommand { %v }", strings.Join(lines, ", "))
 }
 
@@ -66,10 +99,37 @@ func (p *RedisBatchCommand) GetArgs() []string {
 	return output
 }
 
+// Arguments as the []interface{} accepted by Cmd/Append
+func (p *RedisBatchCommand) interfaceArgs() []interface{} {
+	output := make([]interface{}, len(p.args))
+	for i, arg := range p.args {
+		output[i] = arg
+	}
+	return output
+}
+
 func (p *RedisBatchCommand) Reply() *redis.Reply {
 	return p.reply
 }
 
+//
+// Resolve the command's future, if any, once the reply is filled in
+//
+func (p *RedisBatchCommand) complete() {
+	if future := p.future; nil != future {
+		p.future = nil
+		future.complete()
+	}
+
+	// The merged commands share our reply
+	coalesced := p.coalesced
+	p.coalesced = nil
+	for _, cmd := range coalesced {
+		cmd.reply = p.reply
+		cmd.complete()
+	}
+}
+
 //
 // Return the bool in the Redis Reply; this assumes the redis reply is not NilReply
 //
@@ -146,6 +206,72 @@ func (p *RedisBatchCommand) ReplyToStringPtrs() ([]*string, error) {
 	return ReplyToStringPtrs(p.reply)
 }
 
+//
+// Return the bools in the Redis Reply, i.e. SMISMEMBER
+//
+// Redis/Casting Error --> error
+// Nil Reply Element   --> false
+// All other cases     --> true or false
+//
+func (p *RedisBatchCommand) ReplyToBools() ([]bool, error) {
+	return ReplyToBools(p.reply)
+}
+
+//
+// Return the strings in the Redis Reply, i.e. SMEMBERS or LRANGE
+//
+// Redis/Casting Error --> error
+// Nil Reply Element   --> error
+// All other cases     --> strings, in the order of the reply
+//
+func (p *RedisBatchCommand) ReplyToStrings() ([]string, error) {
+	return ReplyToStrings(p.reply)
+}
+
+//
+// Return the member/score pairs in the Redis Reply, i.e. ZRANGE ... WITHSCORES
+//
+// Redis/Casting Error --> error
+// Empty Sorted Set    --> empty slice
+// All other cases     --> members & scores, in the order of the reply
+//
+func (p *RedisBatchCommand) ReplyToSortedSetMembers() ([]RedisSortedSetMember, error) {
+	return ReplyToSortedSetMembers(p.reply)
+}
+
+//
+// Return the field/value pairs in the Redis Reply as strings, i.e. HGETALL
+//
+// Redis/Casting Error --> error
+// Missing Hash        --> empty map
+// All other cases     --> fields & values
+//
+func (p *RedisBatchCommand) ReplyToStringMap() (map[string]string, error) {
+	return ReplyToStringMap(p.reply)
+}
+
+//
+// Return the field/value pairs in the Redis Reply as int64s, i.e. HGETALL
+//
+// Redis/Casting Error --> error
+// Missing Hash        --> empty map
+// All other cases     --> fields & values
+//
+func (p *RedisBatchCommand) ReplyToInt64Map() (map[string]int64, error) {
+	return ReplyToInt64Map(p.reply)
+}
+
+//
+// Return the field/value pairs in the Redis Reply as float64s, i.e. HGETALL
+//
+// Redis/Casting Error --> error
+// Missing Hash        --> empty map
+// All other cases     --> fields & values
+//
+func (p *RedisBatchCommand) ReplyToFloat64Map() (map[string]float64, error) {
+	return ReplyToFloat64Map(p.reply)
+}
+
 //
 // Helpers:
 //
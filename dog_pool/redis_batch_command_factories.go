@@ -33,7 +33,7 @@ var cmd_incrbyfloat = "INCRBYFLOAT"
 
 // Basic factory method
 func MakeRedisBatchCommand(cmd string) *RedisBatchCommand {
-	return &RedisBatchCommand{cmd, [][]byte{}, nil}
+	return &RedisBatchCommand{cmd, [][]byte{}, nil, nil, nil}
 }
 
 // EXISTS <KEY>
@@ -47,7 +47,7 @@ func MakeRedisBatchCommandExists(key string) *RedisBatchCommand {
 	return output
 }
 
-// EXPIRE <KEY> <SECONDS>
+// EXPIRE <KEY> <SECONDS>, truncated to whole seconds; see MakeRedisBatchCommandExpireInMillis
 func MakeRedisBatchCommandExpireIn(key string, expire_in time.Duration) *RedisBatchCommand {
 	output := &RedisBatchCommand{
 		cmd:   cmd_expire,
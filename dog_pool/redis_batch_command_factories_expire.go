@@ -0,0 +1,212 @@
+//
+// SET options & expiry factory methods
+//
+// Expiries keep their sub-second precision, whole seconds are sent as seconds & the rest as milliseconds:
+//   time.Minute                --> EX 60
+//   1500 * time.Millisecond    --> PX 1500
+//   time.Unix(1700000000, 0)   --> EXAT 1700000000
+//   time.Unix(1700000000, 5e8) --> PXAT 1700000000500
+//
+// Usage:
+//   MakeRedisBatchCommandSetWithOptions("lock:bob", []byte("worker-1"), RedisSetOptions{ExpireIn: 30 * time.Second, NX: true})
+//   MakeRedisBatchCommandSetWithOptions("session:bob", value, RedisSetOptions{KeepTTL: true, Get: true})
+//   MakeRedisBatchCommandGetEx("session:bob", RedisGetExOptions{ExpireIn: 15 * time.Minute})
+//
+
+package dog_pool
+
+import "time"
+
+var cmd_setex = "SETEX"
+var cmd_psetex = "PSETEX"
+var cmd_getex = "GETEX"
+var cmd_getdel = "GETDEL"
+var cmd_pexpire = "PEXPIRE"
+var cmd_expireat = "EXPIREAT"
+var cmd_pexpireat = "PEXPIREAT"
+var cmd_pttl = "PTTL"
+
+//
+// Options of SET, set at most one of ExpireIn, ExpireAt & KeepTTL
+//
+type RedisSetOptions struct {
+	ExpireIn time.Duration "(optional) EX or PX, expire the key after the duration"
+	ExpireAt time.Time     "(optional) EXAT or PXAT, expire the key at the time"
+	KeepTTL  bool          "Keep the key's current expiry"
+	NX       bool          "Only set the key if it doesn't exist; replies nil when not set"
+	XX       bool          "Only set the key if it already exists; replies nil when not set"
+	Get      bool          "Reply with the key's old value, or nil, rather than OK"
+}
+
+//
+// Options of GETEX, set at most one of ExpireIn, ExpireAt & Persist
+//
+type RedisGetExOptions struct {
+	ExpireIn time.Duration "(optional) EX or PX, expire the key after the duration"
+	ExpireAt time.Time     "(optional) EXAT or PXAT, expire the key at the time"
+	Persist  bool          "Remove the key's expiry"
+}
+
+//
+// Factory Methods:
+//
+
+// SET <KEY> <VALUE> [NX|XX] [GET] [EX <SECONDS>|PX <MILLISECONDS>|EXAT <UNIX>|PXAT <UNIX MILLISECONDS>|KEEPTTL]
+func MakeRedisBatchCommandSetWithOptions(key string, value []byte, options RedisSetOptions) *RedisBatchCommand {
+	output := &RedisBatchCommand{
+		cmd:   cmd_set,
+		args:  make([][]byte, 7)[0:0],
+		reply: nil,
+	}
+	output.WriteStringArg(key)
+	output.WriteArg(value)
+	if options.NX {
+		output.WriteStringArg("NX")
+	}
+	if options.XX {
+		output.WriteStringArg("XX")
+	}
+	if options.Get {
+		output.WriteStringArg("GET")
+	}
+	output.writeExpireArgs(options.ExpireIn, options.ExpireAt)
+	if options.KeepTTL {
+		output.WriteStringArg("KEEPTTL")
+	}
+	return output
+}
+
+// SETEX <KEY> <SECONDS> <VALUE> or PSETEX <KEY> <MILLISECONDS> <VALUE>
+func MakeRedisBatchCommandSetExpiresIn(key string, value []byte, expire_in time.Duration) *RedisBatchCommand {
+	output := &RedisBatchCommand{
+		cmd:   cmd_setex,
+		args:  make([][]byte, 3)[0:0],
+		reply: nil,
+	}
+	output.WriteStringArg(key)
+	if isWholeSeconds(expire_in) {
+		output.WriteIntArg(int64(expire_in / time.Second))
+	} else {
+		output.cmd = cmd_psetex
+		output.WriteIntArg(toRedisMilliseconds(expire_in))
+	}
+	output.WriteArg(value)
+	return output
+}
+
+// GETEX <KEY> [EX <SECONDS>|PX <MILLISECONDS>|EXAT <UNIX>|PXAT <UNIX MILLISECONDS>|PERSIST]
+func MakeRedisBatchCommandGetEx(key string, options RedisGetExOptions) *RedisBatchCommand {
+	output := &RedisBatchCommand{
+		cmd:   cmd_getex,
+		args:  make([][]byte, 3)[0:0],
+		reply: nil,
+	}
+	output.WriteStringArg(key)
+	output.writeExpireArgs(options.ExpireIn, options.ExpireAt)
+	if options.Persist {
+		output.WriteStringArg("PERSIST")
+	}
+	return output
+}
+
+// GETDEL <KEY>
+func MakeRedisBatchCommandGetDelete(key string) *RedisBatchCommand {
+	output := &RedisBatchCommand{
+		cmd:   cmd_getdel,
+		args:  make([][]byte, 1)[0:0],
+		reply: nil,
+	}
+	output.WriteStringArg(key)
+	return output
+}
+
+// PEXPIRE <KEY> <MILLISECONDS>
+func MakeRedisBatchCommandExpireInMillis(key string, expire_in time.Duration) *RedisBatchCommand {
+	output := &RedisBatchCommand{
+		cmd:   cmd_pexpire,
+		args:  make([][]byte, 2)[0:0],
+		reply: nil,
+	}
+	output.WriteStringArg(key)
+	output.WriteIntArg(toRedisMilliseconds(expire_in))
+	return output
+}
+
+// EXPIREAT <KEY> <UNIX>
+func MakeRedisBatchCommandExpireAt(key string, expire_at time.Time) *RedisBatchCommand {
+	output := &RedisBatchCommand{
+		cmd:   cmd_expireat,
+		args:  make([][]byte, 2)[0:0],
+		reply: nil,
+	}
+	output.WriteStringArg(key)
+	output.WriteIntArg(expire_at.Unix())
+	return output
+}
+
+// PEXPIREAT <KEY> <UNIX MILLISECONDS>
+func MakeRedisBatchCommandExpireAtMillis(key string, expire_at time.Time) *RedisBatchCommand {
+	output := &RedisBatchCommand{
+		cmd:   cmd_pexpireat,
+		args:  make([][]byte, 2)[0:0],
+		reply: nil,
+	}
+	output.WriteStringArg(key)
+	output.WriteIntArg(toRedisUnixMilliseconds(expire_at))
+	return output
+}
+
+// PTTL <KEY>
+func MakeRedisBatchCommandGetExpiresInMillis(key string) *RedisBatchCommand {
+	output := &RedisBatchCommand{
+		cmd:   cmd_pttl,
+		args:  make([][]byte, 1)[0:0],
+		reply: nil,
+	}
+	output.WriteStringArg(key)
+	return output
+}
+
+//
+// Helpers:
+//
+
+// EX <SECONDS>, PX <MILLISECONDS>, EXAT <UNIX> or PXAT <UNIX MILLISECONDS>, for the expiries that are set
+func (p *RedisBatchCommand) writeExpireArgs(expire_in time.Duration, expire_at time.Time) {
+	switch {
+	case 0 == expire_in:
+	case isWholeSeconds(expire_in):
+		p.WriteStringArg("EX")
+		p.WriteIntArg(int64(expire_in / time.Second))
+	default:
+		p.WriteStringArg("PX")
+		p.WriteIntArg(toRedisMilliseconds(expire_in))
+	}
+
+	switch {
+	case expire_at.IsZero():
+	case 0 == expire_at.Nanosecond():
+		p.WriteStringArg("EXAT")
+		p.WriteIntArg(expire_at.Unix())
+	default:
+		p.WriteStringArg("PXAT")
+		p.WriteIntArg(toRedisUnixMilliseconds(expire_at))
+	}
+}
+
+func isWholeSeconds(duration time.Duration) bool {
+	return 0 == duration%time.Second
+}
+
+// Milliseconds of the duration, rounding up so a positive duration never becomes 0 (an immediate expiry)
+func toRedisMilliseconds(duration time.Duration) int64 {
+	if duration > 0 {
+		duration += time.Millisecond - 1
+	}
+	return int64(duration / time.Millisecond)
+}
+
+// Unix time of the time in milliseconds, rounding up like toRedisMilliseconds
+func toRedisUnixMilliseconds(t time.Time) int64 {
+	return t.Unix()*1000 + toRedisMilliseconds(time.Duration(t.Nanosecond()))
+}
@@ -0,0 +1,124 @@
+package dog_pool
+
+import "time"
+import "github.com/alecthomas/log4go"
+
+import "testing"
+import "github.com/orfjackal/gospec/src/gospec"
+
+func TestRedisBatchCommandExpireFactorySpecs(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping test in benchmark mode.")
+		return
+	}
+	r := gospec.NewRunner()
+	r.AddSpec(RedisBatchCommandExpireFactorySpecs)
+	gospec.MainGoTest(r, t)
+}
+
+func RedisBatchCommandExpireFactorySpecs(c gospec.Context) {
+
+	c.Specify("[MakeRedisBatchCommand][SetWithOptions] Makes command", func() {
+		value := MakeRedisBatchCommandSetWithOptions("K", []byte("V"), RedisSetOptions{})
+		c.Expect(value.GetCmd(), gospec.Equals, "SET")
+		c.Expect(value.GetArgs(), gospec.Equals, []string{"K", "V"})
+
+		value = MakeRedisBatchCommandSetWithOptions("K", []byte("V"), RedisSetOptions{ExpireIn: time.Minute, NX: true})
+		c.Expect(value.GetArgs(), gospec.Equals, []string{"K", "V", "NX", "EX", "60"})
+
+		value = MakeRedisBatchCommandSetWithOptions("K", []byte("V"), RedisSetOptions{ExpireIn: 1500 * time.Millisecond, XX: true, Get: true})
+		c.Expect(value.GetArgs(), gospec.Equals, []string{"K", "V", "XX", "GET", "PX", "1500"})
+
+		value = MakeRedisBatchCommandSetWithOptions("K", []byte("V"), RedisSetOptions{ExpireAt: time.Unix(1700000000, 0)})
+		c.Expect(value.GetArgs(), gospec.Equals, []string{"K", "V", "EXAT", "1700000000"})
+
+		value = MakeRedisBatchCommandSetWithOptions("K", []byte("V"), RedisSetOptions{ExpireAt: time.Unix(1700000000, 5e8)})
+		c.Expect(value.GetArgs(), gospec.Equals, []string{"K", "V", "PXAT", "1700000000500"})
+
+		value = MakeRedisBatchCommandSetWithOptions("K", []byte("V"), RedisSetOptions{KeepTTL: true, Get: true})
+		c.Expect(value.GetArgs(), gospec.Equals, []string{"K", "V", "GET", "KEEPTTL"})
+	})
+
+	c.Specify("[MakeRedisBatchCommand][SetExpiresIn] Makes SETEX or PSETEX", func() {
+		value := MakeRedisBatchCommandSetExpiresIn("K", []byte("V"), 2*time.Second)
+		c.Expect(value.GetCmd(), gospec.Equals, "SETEX")
+		c.Expect(value.GetArgs(), gospec.Equals, []string{"K", "2", "V"})
+
+		value = MakeRedisBatchCommandSetExpiresIn("K", []byte("V"), 2500*time.Millisecond)
+		c.Expect(value.GetCmd(), gospec.Equals, "PSETEX")
+		c.Expect(value.GetArgs(), gospec.Equals, []string{"K", "2500", "V"})
+	})
+
+	c.Specify("[MakeRedisBatchCommand][GetEx] Makes command", func() {
+		value := MakeRedisBatchCommandGetEx("K", RedisGetExOptions{})
+		c.Expect(value.GetCmd(), gospec.Equals, "GETEX")
+		c.Expect(value.GetArgs(), gospec.Equals, []string{"K"})
+
+		value = MakeRedisBatchCommandGetEx("K", RedisGetExOptions{ExpireIn: 250 * time.Millisecond})
+		c.Expect(value.GetArgs(), gospec.Equals, []string{"K", "PX", "250"})
+
+		value = MakeRedisBatchCommandGetEx("K", RedisGetExOptions{Persist: true})
+		c.Expect(value.GetArgs(), gospec.Equals, []string{"K", "PERSIST"})
+	})
+
+	c.Specify("[MakeRedisBatchCommand][Expire...] Makes the expiry commands", func() {
+		for _, test := range []struct {
+			value *RedisBatchCommand
+			cmd   string
+			args  []string
+		}{
+			{MakeRedisBatchCommandGetDelete("K"), "GETDEL", []string{"K"}},
+			{MakeRedisBatchCommandExpireInMillis("K", 1500*time.Millisecond), "PEXPIRE", []string{"K", "1500"}},
+			{MakeRedisBatchCommandExpireInMillis("K", time.Microsecond), "PEXPIRE", []string{"K", "1"}},
+			{MakeRedisBatchCommandExpireInMillis("K", -time.Second), "PEXPIRE", []string{"K", "-1000"}},
+			{MakeRedisBatchCommandExpireAt("K", time.Unix(1700000000, 5e8)), "EXPIREAT", []string{"K", "1700000000"}},
+			{MakeRedisBatchCommandExpireAtMillis("K", time.Unix(1700000000, 5e8)), "PEXPIREAT", []string{"K", "1700000000500"}},
+			{MakeRedisBatchCommandExpireAtMillis("K", time.Unix(1700000000, 1)), "PEXPIREAT", []string{"K", "1700000000001"}},
+			{MakeRedisBatchCommandGetExpiresInMillis("K"), "PTTL", []string{"K"}},
+		} {
+			c.Expect(test.value.GetCmd(), gospec.Equals, test.cmd)
+			c.Expect(test.value.GetArgs(), gospec.Equals, test.args)
+		}
+	})
+
+	c.Specify("[RedisBatchCommands][Expire] Runs the SET & expiry commands", func() {
+		logger := MakeLog4goLogger(log4go.NewDefaultLogger(log4go.CRITICAL))
+		server, err := StartRedisServer(logger)
+		if nil != err {
+			panic(err)
+		}
+		defer server.Close()
+
+		set := MakeRedisBatchCommandSetWithOptions("K", []byte("A"), RedisSetOptions{ExpireIn: 1500 * time.Millisecond, NX: true})
+		set_nx := MakeRedisBatchCommandSetWithOptions("K", []byte("B"), RedisSetOptions{NX: true})
+		set_get := MakeRedisBatchCommandSetWithOptions("K", []byte("C"), RedisSetOptions{KeepTTL: true, Get: true})
+		pttl := MakeRedisBatchCommandGetExpiresInMillis("K")
+		getex := MakeRedisBatchCommandGetEx("K", RedisGetExOptions{Persist: true})
+		ttl := MakeRedisBatchCommandGetExpiresIn("K")
+		pexpireat := MakeRedisBatchCommandExpireAtMillis("K", time.Now().Add(time.Hour))
+		getdel := MakeRedisBatchCommandGetDelete("K")
+		get := MakeRedisBatchCommandGet("K")
+
+		err = RedisBatchCommands{set, set_nx, set_get, pttl, getex, ttl, pexpireat, getdel, get}.ExecuteBatch(server.Connection())
+		c.Expect(err, gospec.Equals, nil)
+
+		str, _ := set_nx.ReplyToStringPtr()
+		c.Expect(str, gospec.Satisfies, nil == str)
+		str, _ = set_get.ReplyToStringPtr()
+		c.Expect(*str, gospec.Equals, "A")
+
+		value, _ := pttl.ReplyToInt64Ptr()
+		c.Expect(*value, gospec.Satisfies, *value > 1000 && *value <= 1500)
+		str, _ = getex.ReplyToStringPtr()
+		c.Expect(*str, gospec.Equals, "C")
+		value, _ = ttl.ReplyToInt64Ptr()
+		c.Expect(*value, gospec.Equals, int64(-1))
+		b, _ := pexpireat.ReplyToBool()
+		c.Expect(b, gospec.Equals, true)
+
+		str, _ = getdel.ReplyToStringPtr()
+		c.Expect(*str, gospec.Equals, "C")
+		str, _ = get.ReplyToStringPtr()
+		c.Expect(str, gospec.Satisfies, nil == str)
+	})
+}
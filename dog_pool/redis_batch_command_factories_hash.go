@@ -0,0 +1,113 @@
+//
+// Hash factory methods
+//
+// Fields are written in sorted order, so the same map always makes the same command.
+// The field expiries need Redis 7.4+, their replies hold a status per field:
+//   HEXPIRE  --> -2 missing field, 0 condition not met, 1 expiry set, 2 field deleted (expiry in the past)
+//   HTTL     --> -2 missing field, -1 no expiry, otherwise the time left
+//   HPERSIST --> -2 missing field, -1 no expiry, 1 expiry removed
+//
+// Usage:
+//   MakeRedisBatchCommandHashSetMap("user:bob", map[string][]byte{"name": []byte("Bob"), "visits": []byte("1")})
+//   MakeRedisBatchCommandHashGetAll("user:bob").ReplyToStringMap()
+//   MakeRedisBatchCommandHashExpireIn("user:bob", time.Hour, "visits")
+//
+
+package dog_pool
+
+import "sort"
+import "time"
+
+var cmd_hgetall = "HGETALL"
+var cmd_hexpire = "HEXPIRE"
+var cmd_hpexpire = "HPEXPIRE"
+var cmd_httl = "HTTL"
+var cmd_hpttl = "HPTTL"
+var cmd_hpersist = "HPERSIST"
+
+//
+// Factory Methods:
+//
+
+// HSET <KEY> <FIELD> <VALUE> <FIELD> <VALUE> ...
+func MakeRedisBatchCommandHashSetMap(key string, values map[string][]byte) *RedisBatchCommand {
+	fields := make([]string, 0, len(values))
+	for field := range values {
+		fields = append(fields, field)
+	}
+	sort.Strings(fields)
+
+	output := &RedisBatchCommand{
+		cmd:   cmd_hset,
+		args:  make([][]byte, 1+2*len(fields))[0:0],
+		reply: nil,
+	}
+	output.WriteStringArg(key)
+	for _, field := range fields {
+		output.WriteStringArg(field)
+		output.WriteArg(values[field])
+	}
+	return output
+}
+
+// HGETALL <KEY>
+func MakeRedisBatchCommandHashGetAll(key string) *RedisBatchCommand {
+	output := &RedisBatchCommand{
+		cmd:   cmd_hgetall,
+		args:  make([][]byte, 1)[0:0],
+		reply: nil,
+	}
+	output.WriteStringArg(key)
+	return output
+}
+
+// HEXPIRE <KEY> <SECONDS> FIELDS <N> <FIELD> ... or HPEXPIRE <KEY> <MILLISECONDS> FIELDS <N> <FIELD> ...
+func MakeRedisBatchCommandHashExpireIn(key string, expire_in time.Duration, fields ...string) *RedisBatchCommand {
+	output := &RedisBatchCommand{
+		cmd:   cmd_hexpire,
+		args:  make([][]byte, 4+len(fields))[0:0],
+		reply: nil,
+	}
+	output.WriteStringArg(key)
+	if isWholeSeconds(expire_in) {
+		output.WriteIntArg(int64(expire_in / time.Second))
+	} else {
+		output.cmd = cmd_hpexpire
+		output.WriteIntArg(toRedisMilliseconds(expire_in))
+	}
+	output.writeHashFieldsArgs(fields)
+	return output
+}
+
+// HTTL <KEY> FIELDS <N> <FIELD> ...
+func MakeRedisBatchCommandHashGetExpiresIn(key string, fields ...string) *RedisBatchCommand {
+	return makeRedisBatchCommandHashFields(cmd_httl, key, fields)
+}
+
+// HPTTL <KEY> FIELDS <N> <FIELD> ...
+func MakeRedisBatchCommandHashGetExpiresInMillis(key string, fields ...string) *RedisBatchCommand {
+	return makeRedisBatchCommandHashFields(cmd_hpttl, key, fields)
+}
+
+// HPERSIST <KEY> FIELDS <N> <FIELD> ...
+func MakeRedisBatchCommandHashPersist(key string, fields ...string) *RedisBatchCommand {
+	return makeRedisBatchCommandHashFields(cmd_hpersist, key, fields)
+}
+
+func makeRedisBatchCommandHashFields(cmd, key string, fields []string) *RedisBatchCommand {
+	output := &RedisBatchCommand{
+		cmd:   cmd,
+		args:  make([][]byte, 3+len(fields))[0:0],
+		reply: nil,
+	}
+	output.WriteStringArg(key)
+	output.writeHashFieldsArgs(fields)
+	return output
+}
+
+// FIELDS <N> <FIELD> ...
+func (p *RedisBatchCommand) writeHashFieldsArgs(fields []string) {
+	p.WriteStringArg("FIELDS")
+	p.WriteIntArg(int64(len(fields)))
+	p.WriteStringArgs(fields)
+}
@@ -0,0 +1,99 @@
+package dog_pool
+
+import "time"
+import "github.com/alecthomas/log4go"
+import "github.com/RUNDSP/radix/redis"
+
+import "testing"
+import "github.com/orfjackal/gospec/src/gospec"
+
+func TestRedisBatchCommandHashFactorySpecs(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping test in benchmark mode.")
+		return
+	}
+	r := gospec.NewRunner()
+	r.AddSpec(RedisBatchCommandHashFactorySpecs)
+	gospec.MainGoTest(r, t)
+}
+
+func RedisBatchCommandHashFactorySpecs(c gospec.Context) {
+
+	c.Specify("[MakeRedisBatchCommand][HashSetMap] Makes command with the fields in order", func() {
+		value := MakeRedisBatchCommandHashSetMap("H", map[string][]byte{"C": []byte("3"), "A": []byte("1"), "B": []byte("2")})
+		c.Expect(value.GetCmd(), gospec.Equals, "HSET")
+		c.Expect(value.GetArgs(), gospec.Equals, []string{"H", "A", "1", "B", "2", "C", "3"})
+	})
+
+	c.Specify("[MakeRedisBatchCommand][Hash...] Makes the hash commands", func() {
+		for _, test := range []struct {
+			value *RedisBatchCommand
+			cmd   string
+			args  []string
+		}{
+			{MakeRedisBatchCommandHashGetAll("H"), "HGETALL", []string{"H"}},
+			{MakeRedisBatchCommandHashExpireIn("H", time.Minute, "A", "B"), "HEXPIRE", []string{"H", "60", "FIELDS", "2", "A", "B"}},
+			{MakeRedisBatchCommandHashExpireIn("H", 1500*time.Millisecond, "A"), "HPEXPIRE", []string{"H", "1500", "FIELDS", "1", "A"}},
+			{MakeRedisBatchCommandHashGetExpiresIn("H", "A"), "HTTL", []string{"H", "FIELDS", "1", "A"}},
+			{MakeRedisBatchCommandHashGetExpiresInMillis("H", "A", "B"), "HPTTL", []string{"H", "FIELDS", "2", "A", "B"}},
+			{MakeRedisBatchCommandHashPersist("H", "A"), "HPERSIST", []string{"H", "FIELDS", "1", "A"}},
+		} {
+			c.Expect(test.value.GetCmd(), gospec.Equals, test.cmd)
+			c.Expect(test.value.GetArgs(), gospec.Equals, test.args)
+		}
+	})
+
+	c.Specify("[RedisBatchCommands][Hash] Namespaces only the key", func() {
+		client := &recordingRedisClient{}
+		cmds := RedisBatchCommands{MakeRedisBatchCommandHashExpireIn("H", time.Minute, "A")}
+		c.Expect(cmds.ExecuteBatch(MakeRedisNamespacedClient("ns:", client)), gospec.Equals, nil)
+		c.Expect(client.args[0], gospec.Equals, []string{"ns:H", "60", "FIELDS", "1", "A"})
+	})
+
+	c.Specify("[ReplyTo...Map] Rejects replies that aren't field/value pairs", func() {
+		_, err := ReplyToStringMap(&redis.Reply{Type: redis.ErrorReply, Err: ErrConnectionIsClosed})
+		c.Expect(err, gospec.Equals, ErrConnectionIsClosed)
+
+		_, err = ReplyToInt64Map(&redis.Reply{Type: redis.NilReply})
+		c.Expect(err, gospec.Satisfies, nil != err)
+
+		_, err = ReplyToFloat64Map(&redis.Reply{Type: redis.MultiReply, Elems: []*redis.Reply{{Type: redis.NilReply}}})
+		c.Expect(err.Error(), gospec.Equals, "Reply is not field/value pairs, 1 elements")
+
+		values, err := ReplyToStringMap(&redis.Reply{Type: redis.MultiReply})
+		c.Expect(err, gospec.Equals, nil)
+		c.Expect(len(values), gospec.Equals, 0)
+	})
+
+	c.Specify("[RedisBatchCommands][Hash] Runs the hash commands", func() {
+		logger := MakeLog4goLogger(log4go.NewDefaultLogger(log4go.CRITICAL))
+		server, err := StartRedisServer(logger)
+		if nil != err {
+			panic(err)
+		}
+		defer server.Close()
+
+		hset := MakeRedisBatchCommandHashSetMap("H", map[string][]byte{"A": []byte("1"), "B": []byte("2.5")})
+		hgetall := MakeRedisBatchCommandHashGetAll("H")
+		miss := MakeRedisBatchCommandHashGetAll("Miss")
+
+		err = RedisBatchCommands{hset, hgetall, miss}.ExecuteBatch(server.Connection())
+		c.Expect(err, gospec.Equals, nil)
+
+		value, _ := hset.ReplyToInt64Ptr()
+		c.Expect(*value, gospec.Equals, int64(2))
+
+		strs, err := hgetall.ReplyToStringMap()
+		c.Expect(err, gospec.Equals, nil)
+		c.Expect(strs, gospec.Equals, map[string]string{"A": "1", "B": "2.5"})
+		floats, err := hgetall.ReplyToFloat64Map()
+		c.Expect(err, gospec.Equals, nil)
+		c.Expect(floats, gospec.Equals, map[string]float64{"A": 1, "B": 2.5})
+		_, err = hgetall.ReplyToInt64Map()
+		c.Expect(err, gospec.Satisfies, nil != err)
+
+		ints, err := miss.ReplyToInt64Map()
+		c.Expect(err, gospec.Equals, nil)
+		c.Expect(len(ints), gospec.Equals, 0)
+	})
+}
@@ -0,0 +1,195 @@
+//
+// List & set factory methods
+//
+// Usage:
+//   MakeRedisBatchCommandListLeftPush("recent:bob", []byte("event:42"))
+//   MakeRedisBatchCommandListTrim("recent:bob", 0, 99)
+//   MakeRedisBatchCommandSetAdd("online", "bob", "alice")
+//   MakeRedisBatchCommandSetInter("online", "friends:bob")
+//
+
+package dog_pool
+
+var cmd_lpush = "LPUSH"
+var cmd_rpush = "RPUSH"
+var cmd_lrange = "LRANGE"
+var cmd_ltrim = "LTRIM"
+var cmd_lrem = "LREM"
+var cmd_llen = "LLEN"
+
+var cmd_sadd = "SADD"
+var cmd_srem = "SREM"
+var cmd_sismember = "SISMEMBER"
+var cmd_smismember = "SMISMEMBER"
+var cmd_smembers = "SMEMBERS"
+var cmd_scard = "SCARD"
+var cmd_sinter = "SINTER"
+var cmd_sinterstore = "SINTERSTORE"
+var cmd_sunion = "SUNION"
+var cmd_sunionstore = "SUNIONSTORE"
+var cmd_sdiff = "SDIFF"
+var cmd_sdiffstore = "SDIFFSTORE"
+
+//
+// List Factory Methods:
+//
+
+// LPUSH <KEY> <VALUE> <VALUE> ...
+func MakeRedisBatchCommandListLeftPush(key string, values ...[]byte) *RedisBatchCommand {
+	return makeRedisBatchCommandListPush(cmd_lpush, key, values)
+}
+
+// RPUSH <KEY> <VALUE> <VALUE> ...
+func MakeRedisBatchCommandListRightPush(key string, values ...[]byte) *RedisBatchCommand {
+	return makeRedisBatchCommandListPush(cmd_rpush, key, values)
+}
+
+func makeRedisBatchCommandListPush(cmd, key string, values [][]byte) *RedisBatchCommand {
+	output := &RedisBatchCommand{
+		cmd:   cmd,
+		args:  make([][]byte, 1+len(values))[0:0],
+		reply: nil,
+	}
+	output.WriteStringArg(key)
+	for _, value := range values {
+		output.WriteArg(value)
+	}
+	return output
+}
+
+// LRANGE <KEY> <START> <STOP>
+func MakeRedisBatchCommandListRange(key string, start, stop int64) *RedisBatchCommand {
+	output := &RedisBatchCommand{
+		cmd:   cmd_lrange,
+		args:  make([][]byte, 3)[0:0],
+		reply: nil,
+	}
+	output.WriteStringArg(key)
+	output.WriteIntArg(start)
+	output.WriteIntArg(stop)
+	return output
+}
+
+// LTRIM <KEY> <START> <STOP>
+func MakeRedisBatchCommandListTrim(key string, start, stop int64) *RedisBatchCommand {
+	output := &RedisBatchCommand{
+		cmd:   cmd_ltrim,
+		args:  make([][]byte, 3)[0:0],
+		reply: nil,
+	}
+	output.WriteStringArg(key)
+	output.WriteIntArg(start)
+	output.WriteIntArg(stop)
+	return output
+}
+
+// LREM <KEY> <COUNT> <VALUE>
+func MakeRedisBatchCommandListRemove(key string, count int64, value []byte) *RedisBatchCommand {
+	output := &RedisBatchCommand{
+		cmd:   cmd_lrem,
+		args:  make([][]byte, 3)[0:0],
+		reply: nil,
+	}
+	output.WriteStringArg(key)
+	output.WriteIntArg(count)
+	output.WriteArg(value)
+	return output
+}
+
+// LLEN <KEY>
+func MakeRedisBatchCommandListLen(key string) *RedisBatchCommand {
+	output := &RedisBatchCommand{
+		cmd:   cmd_llen,
+		args:  make([][]byte, 1)[0:0],
+		reply: nil,
+	}
+	output.WriteStringArg(key)
+	return output
+}
+
+//
+// Set Factory Methods:
+//
+
+// SADD <KEY> <MEMBER> <MEMBER> ...
+func MakeRedisBatchCommandSetAdd(key string, members ...string) *RedisBatchCommand {
+	return makeRedisBatchCommandKeyArgs(cmd_sadd, key, members)
+}
+
+// SREM <KEY> <MEMBER> <MEMBER> ...
+func MakeRedisBatchCommandSetRemove(key string, members ...string) *RedisBatchCommand {
+	return makeRedisBatchCommandKeyArgs(cmd_srem, key, members)
+}
+
+// SISMEMBER <KEY> <MEMBER>
+func MakeRedisBatchCommandSetIsMember(key, member string) *RedisBatchCommand {
+	return makeRedisBatchCommandKeyArgs(cmd_sismember, key, []string{member})
+}
+
+// SMISMEMBER <KEY> <MEMBER> <MEMBER> ...
+func MakeRedisBatchCommandSetMultiIsMember(key string, members ...string) *RedisBatchCommand {
+	return makeRedisBatchCommandKeyArgs(cmd_smismember, key, members)
+}
+
+// SMEMBERS <KEY>
+func MakeRedisBatchCommandSetMembers(key string) *RedisBatchCommand {
+	return makeRedisBatchCommandKeyArgs(cmd_smembers, key, nil)
+}
+
+// SCARD <KEY>
+func MakeRedisBatchCommandSetCard(key string) *RedisBatchCommand {
+	return makeRedisBatchCommandKeyArgs(cmd_scard, key, nil)
+}
+
+// SINTER <KEY> <KEY> ...
+func MakeRedisBatchCommandSetInter(keys ...string) *RedisBatchCommand {
+	return makeRedisBatchCommandKeys(cmd_sinter, keys)
+}
+
+// SINTERSTORE <DEST> <KEY> <KEY> ...
+func MakeRedisBatchCommandSetInterStore(dest string, keys ...string) *RedisBatchCommand {
+	return makeRedisBatchCommandKeyArgs(cmd_sinterstore, dest, keys)
+}
+
+// SUNION <KEY> <KEY> ...
+func MakeRedisBatchCommandSetUnion(keys ...string) *RedisBatchCommand {
+	return makeRedisBatchCommandKeys(cmd_sunion, keys)
+}
+
+// SUNIONSTORE <DEST> <KEY> <KEY> ...
+func MakeRedisBatchCommandSetUnionStore(dest string, keys ...string) *RedisBatchCommand {
+	return makeRedisBatchCommandKeyArgs(cmd_sunionstore, dest, keys)
+}
+
+// SDIFF <KEY> <KEY> ...
+func MakeRedisBatchCommandSetDiff(keys ...string) *RedisBatchCommand {
+	return makeRedisBatchCommandKeys(cmd_sdiff, keys)
+}
+
+// SDIFFSTORE <DEST> <KEY> <KEY> ...
+func MakeRedisBatchCommandSetDiffStore(dest string, keys ...string) *RedisBatchCommand {
+	return makeRedisBatchCommandKeyArgs(cmd_sdiffstore, dest, keys)
+}
+
+// <CMD> <KEY> <ARG> <ARG> ...
+func makeRedisBatchCommandKeyArgs(cmd, key string, args []string) *RedisBatchCommand {
+	output := &RedisBatchCommand{
+		cmd:   cmd,
+		args:  make([][]byte, 1+len(args))[0:0],
+		reply: nil,
+	}
+	output.WriteStringArg(key)
+	output.WriteStringArgs(args)
+	return output
+}
+
+// <CMD> <KEY> <KEY> ...
+func makeRedisBatchCommandKeys(cmd string, keys []string) *RedisBatchCommand {
+	output := &RedisBatchCommand{
+		cmd:   cmd,
+		args:  make([][]byte, len(keys))[0:0],
+		reply: nil,
+	}
+	output.WriteStringArgs(keys)
+	return output
+}
@@ -0,0 +1,151 @@
+package dog_pool
+
+import "github.com/alecthomas/log4go"
+import "github.com/RUNDSP/radix/redis"
+
+import "testing"
+import "github.com/orfjackal/gospec/src/gospec"
+
+func TestRedisBatchCommandListSetFactorySpecs(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping test in benchmark mode.")
+		return
+	}
+	r := gospec.NewRunner()
+	r.AddSpec(RedisBatchCommandListSetFactorySpecs)
+	gospec.MainGoTest(r, t)
+}
+
+func RedisBatchCommandListSetFactorySpecs(c gospec.Context) {
+
+	c.Specify("[MakeRedisBatchCommand][List...] Makes the list commands", func() {
+		for _, test := range []struct {
+			value *RedisBatchCommand
+			cmd   string
+			args  []string
+		}{
+			{MakeRedisBatchCommandListLeftPush("L", []byte("A"), []byte("B")), "LPUSH", []string{"L", "A", "B"}},
+			{MakeRedisBatchCommandListRightPush("L", []byte("A")), "RPUSH", []string{"L", "A"}},
+			{MakeRedisBatchCommandListRange("L", 0, -1), "LRANGE", []string{"L", "0", "-1"}},
+			{MakeRedisBatchCommandListTrim("L", 1, 99), "LTRIM", []string{"L", "1", "99"}},
+			{MakeRedisBatchCommandListRemove("L", -2, []byte("A")), "LREM", []string{"L", "-2", "A"}},
+			{MakeRedisBatchCommandListLen("L"), "LLEN", []string{"L"}},
+		} {
+			c.Expect(test.value.GetCmd(), gospec.Equals, test.cmd)
+			c.Expect(test.value.GetArgs(), gospec.Equals, test.args)
+		}
+	})
+
+	c.Specify("[MakeRedisBatchCommand][Set...] Makes the set commands", func() {
+		for _, test := range []struct {
+			value *RedisBatchCommand
+			cmd   string
+			args  []string
+		}{
+			{MakeRedisBatchCommandSetAdd("S", "A", "B"), "SADD", []string{"S", "A", "B"}},
+			{MakeRedisBatchCommandSetRemove("S", "A"), "SREM", []string{"S", "A"}},
+			{MakeRedisBatchCommandSetIsMember("S", "A"), "SISMEMBER", []string{"S", "A"}},
+			{MakeRedisBatchCommandSetMultiIsMember("S", "A", "B"), "SMISMEMBER", []string{"S", "A", "B"}},
+			{MakeRedisBatchCommandSetMembers("S"), "SMEMBERS", []string{"S"}},
+			{MakeRedisBatchCommandSetCard("S"), "SCARD", []string{"S"}},
+			{MakeRedisBatchCommandSetInter("S", "T"), "SINTER", []string{"S", "T"}},
+			{MakeRedisBatchCommandSetInterStore("D", "S", "T"), "SINTERSTORE", []string{"D", "S", "T"}},
+			{MakeRedisBatchCommandSetUnion("S", "T"), "SUNION", []string{"S", "T"}},
+			{MakeRedisBatchCommandSetUnionStore("D", "S", "T"), "SUNIONSTORE", []string{"D", "S", "T"}},
+			{MakeRedisBatchCommandSetDiff("S", "T"), "SDIFF", []string{"S", "T"}},
+			{MakeRedisBatchCommandSetDiffStore("D", "S", "T"), "SDIFFSTORE", []string{"D", "S", "T"}},
+		} {
+			c.Expect(test.value.GetCmd(), gospec.Equals, test.cmd)
+			c.Expect(test.value.GetArgs(), gospec.Equals, test.args)
+		}
+	})
+
+	c.Specify("[RedisBatchCommands][ListSet] Namespaces every key", func() {
+		client := &recordingRedisClient{}
+		cmds := RedisBatchCommands{MakeRedisBatchCommandSetInterStore("D", "S", "T"), MakeRedisBatchCommandListRemove("L", 0, []byte("L"))}
+		c.Expect(cmds.ExecuteBatch(MakeRedisNamespacedClient("ns:", client)), gospec.Equals, nil)
+		c.Expect(client.args[0], gospec.Equals, []string{"ns:D", "ns:S", "ns:T"})
+		c.Expect(client.args[1], gospec.Equals, []string{"ns:L", "0", "L"})
+	})
+
+	c.Specify("[ReplyToBools] Returns the bools or error", func() {
+		_, err := ReplyToBools(&redis.Reply{Type: redis.ErrorReply, Err: ErrConnectionIsClosed})
+		c.Expect(err, gospec.Equals, ErrConnectionIsClosed)
+
+		_, err = ReplyToBools(&redis.Reply{Type: redis.NilReply})
+		c.Expect(err, gospec.Satisfies, nil != err)
+
+		values, err := ReplyToBools(&redis.Reply{Type: redis.MultiReply, Elems: []*redis.Reply{{Type: redis.NilReply}}})
+		c.Expect(err, gospec.Equals, nil)
+		c.Expect(values, gospec.Equals, []bool{false})
+	})
+
+	c.Specify("[ReplyToStrings] Returns the strings or error", func() {
+		_, err := ReplyToStrings(&redis.Reply{Type: redis.ErrorReply, Err: ErrConnectionIsClosed})
+		c.Expect(err, gospec.Equals, ErrConnectionIsClosed)
+
+		_, err = ReplyToStrings(&redis.Reply{Type: redis.NilReply})
+		c.Expect(err, gospec.Satisfies, nil != err)
+
+		values, err := ReplyToStrings(&redis.Reply{Type: redis.MultiReply})
+		c.Expect(err, gospec.Equals, nil)
+		c.Expect(len(values), gospec.Equals, 0)
+	})
+
+	c.Specify("[RedisBatchCommands][ListSet] Runs the list & set commands", func() {
+		logger := MakeLog4goLogger(log4go.NewDefaultLogger(log4go.CRITICAL))
+		server, err := StartRedisServer(logger)
+		if nil != err {
+			panic(err)
+		}
+		defer server.Close()
+
+		lpush := MakeRedisBatchCommandListLeftPush("L", []byte("B"), []byte("A"))
+		rpush := MakeRedisBatchCommandListRightPush("L", []byte("C"), []byte("A"))
+		lrem := MakeRedisBatchCommandListRemove("L", -1, []byte("A"))
+		ltrim := MakeRedisBatchCommandListTrim("L", 0, 1)
+		lrange := MakeRedisBatchCommandListRange("L", 0, -1)
+		llen := MakeRedisBatchCommandListLen("L")
+
+		sadd := MakeRedisBatchCommandSetAdd("S", "A", "B", "C")
+		sadd_t := MakeRedisBatchCommandSetAdd("T", "B", "D")
+		srem := MakeRedisBatchCommandSetRemove("S", "C", "Miss")
+		ismember := MakeRedisBatchCommandSetIsMember("S", "A")
+		mismember := MakeRedisBatchCommandSetMultiIsMember("S", "A", "C")
+		inter := MakeRedisBatchCommandSetInter("S", "T")
+		diffstore := MakeRedisBatchCommandSetDiffStore("D", "S", "T")
+		members := MakeRedisBatchCommandSetMembers("D")
+		card := MakeRedisBatchCommandSetCard("S")
+
+		err = RedisBatchCommands{lpush, rpush, lrem, ltrim, lrange, llen, sadd, sadd_t, srem, ismember, mismember, inter, diffstore, members, card}.ExecuteBatch(server.Connection())
+		c.Expect(err, gospec.Equals, nil)
+
+		value, _ := rpush.ReplyToInt64Ptr()
+		c.Expect(*value, gospec.Equals, int64(4))
+		value, _ = lrem.ReplyToInt64Ptr()
+		c.Expect(*value, gospec.Equals, int64(1))
+
+		strs, err := lrange.ReplyToStrings()
+		c.Expect(err, gospec.Equals, nil)
+		c.Expect(strs, gospec.Equals, []string{"A", "B"})
+		value, _ = llen.ReplyToInt64Ptr()
+		c.Expect(*value, gospec.Equals, int64(2))
+
+		value, _ = srem.ReplyToInt64Ptr()
+		c.Expect(*value, gospec.Equals, int64(1))
+		b, _ := ismember.ReplyToBool()
+		c.Expect(b, gospec.Equals, true)
+		bools, err := mismember.ReplyToBools()
+		c.Expect(err, gospec.Equals, nil)
+		c.Expect(bools, gospec.Equals, []bool{true, false})
+
+		strs, _ = inter.ReplyToStrings()
+		c.Expect(strs, gospec.Equals, []string{"B"})
+		value, _ = diffstore.ReplyToInt64Ptr()
+		c.Expect(*value, gospec.Equals, int64(1))
+		strs, _ = members.ReplyToStrings()
+		c.Expect(strs, gospec.Equals, []string{"A"})
+		value, _ = card.ReplyToInt64Ptr()
+		c.Expect(*value, gospec.Equals, int64(2))
+	})
+}
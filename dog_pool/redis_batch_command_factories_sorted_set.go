@@ -0,0 +1,254 @@
+//
+// Sorted set factory methods
+//
+// Scores are formatted in full precision, the range bounds use the Redis syntax:
+//   ByScore --> "1.5", "(1.5" (exclusive), "-inf", "+inf"
+//   ByLex   --> "[a" (inclusive), "(a" (exclusive), "-", "+"
+//   By rank --> "0", "-1"
+//
+// Usage:
+//   MakeRedisBatchCommandSortedSetAdd("leaderboard", RedisSortedSetAddOptions{GT: true}, RedisSortedSetMember{"bob", 42})
+//   MakeRedisBatchCommandSortedSetRange("leaderboard", "0", "9", RedisSortedSetRangeOptions{Rev: true, WithScores: true})
+//   MakeRedisBatchCommandSortedSetRange("events", "(1700000000", "+inf", RedisSortedSetRangeOptions{ByScore: true, Count: 100})
+//
+
+package dog_pool
+
+import "fmt"
+import "strconv"
+
+var cmd_zadd = "ZADD"
+var cmd_zincrby = "ZINCRBY"
+var cmd_zrange = "ZRANGE"
+var cmd_zrank = "ZRANK"
+var cmd_zrevrank = "ZREVRANK"
+var cmd_zrem = "ZREM"
+var cmd_zremrangebyscore = "ZREMRANGEBYSCORE"
+var cmd_zcard = "ZCARD"
+var cmd_zcount = "ZCOUNT"
+var cmd_zunionstore = "ZUNIONSTORE"
+var cmd_zinterstore = "ZINTERSTORE"
+
+//
+// Member of a sorted set & its score
+//
+type RedisSortedSetMember struct {
+	Member string
+	Score  float64
+}
+
+func (p RedisSortedSetMember) String() string {
+	return fmt.Sprintf("RedisSortedSetMember { Member=%v, Score=%v }", p.Member, p.Score)
+}
+
+//
+// Flags of ZADD
+//
+type RedisSortedSetAddOptions struct {
+	NX   bool "Only add new members, never update"
+	XX   bool "Only update existing members, never add"
+	GT   bool "Only update when the new score is greater"
+	LT   bool "Only update when the new score is less"
+	CH   bool "Reply with the number of members added or changed, rather than only added"
+	INCR bool "Increment the score like ZINCRBY, takes a single member & replies with the new score"
+}
+
+//
+// Options of ZRANGE, Start & Stop are ranks unless ByScore or ByLex is set
+//
+type RedisSortedSetRangeOptions struct {
+	ByScore    bool  "Start & Stop are scores"
+	ByLex      bool  "Start & Stop are members, every member must have the same score"
+	Rev        bool  "Highest to lowest, Start is the highest bound"
+	Offset     int64 "Members to skip, with Count"
+	Count      int64 "LIMIT Offset Count, requires ByScore or ByLex; 0 is no limit, < 0 returns every member from the Offset"
+	WithScores bool  "Reply with the scores, see ReplyToSortedSetMembers"
+}
+
+//
+// Options of ZUNIONSTORE & ZINTERSTORE
+//
+type RedisSortedSetStoreOptions struct {
+	Weights   []float64 "(optional) Multiply the scores of each source key, one weight per key"
+	Aggregate string    "(optional) SUM, MIN or MAX; defaults to SUM"
+}
+
+//
+// Factory Methods:
+//
+
+// ZADD <KEY> [NX|XX] [GT|LT] [CH] [INCR] <SCORE> <MEMBER> <SCORE> <MEMBER> ...
+func MakeRedisBatchCommandSortedSetAdd(key string, options RedisSortedSetAddOptions, members ...RedisSortedSetMember) *RedisBatchCommand {
+	output := &RedisBatchCommand{
+		cmd:   cmd_zadd,
+		args:  make([][]byte, 5+2*len(members))[0:0],
+		reply: nil,
+	}
+	output.WriteStringArg(key)
+	for _, flag := range []struct {
+		set  bool
+		name string
+	}{{options.NX, "NX"}, {options.XX, "XX"}, {options.GT, "GT"}, {options.LT, "LT"}, {options.CH, "CH"}, {options.INCR, "INCR"}} {
+		if flag.set {
+			output.WriteStringArg(flag.name)
+		}
+	}
+	for _, member := range members {
+		output.writeScoreArg(member.Score)
+		output.WriteStringArg(member.Member)
+	}
+	return output
+}
+
+// ZINCRBY <KEY> <AMOUNT> <MEMBER>
+func MakeRedisBatchCommandSortedSetIncrementBy(key, member string, delta float64) *RedisBatchCommand {
+	output := &RedisBatchCommand{
+		cmd:   cmd_zincrby,
+		args:  make([][]byte, 3)[0:0],
+		reply: nil,
+	}
+	output.WriteStringArg(key)
+	output.writeScoreArg(delta)
+	output.WriteStringArg(member)
+	return output
+}
+
+// ZRANGE <KEY> <START> <STOP> [BYSCORE|BYLEX] [REV] [LIMIT <OFFSET> <COUNT>] [WITHSCORES]
+func MakeRedisBatchCommandSortedSetRange(key, start, stop string, options RedisSortedSetRangeOptions) *RedisBatchCommand {
+	output := &RedisBatchCommand{
+		cmd:   cmd_zrange,
+		args:  make([][]byte, 9)[0:0],
+		reply: nil,
+	}
+	output.WriteStringArg(key)
+	output.WriteStringArg(start)
+	output.WriteStringArg(stop)
+	switch {
+	case options.ByScore:
+		output.WriteStringArg("BYSCORE")
+	case options.ByLex:
+		output.WriteStringArg("BYLEX")
+	}
+	if options.Rev {
+		output.WriteStringArg("REV")
+	}
+	if 0 != options.Count {
+		output.WriteStringArg("LIMIT")
+		output.WriteIntArg(options.Offset)
+		output.WriteIntArg(options.Count)
+	}
+	if options.WithScores {
+		output.WriteStringArg("WITHSCORES")
+	}
+	return output
+}
+
+// ZRANK <KEY> <MEMBER>
+func MakeRedisBatchCommandSortedSetRank(key, member string) *RedisBatchCommand {
+	output := &RedisBatchCommand{
+		cmd:   cmd_zrank,
+		args:  make([][]byte, 2)[0:0],
+		reply: nil,
+	}
+	output.WriteStringArg(key)
+	output.WriteStringArg(member)
+	return output
+}
+
+// ZREVRANK <KEY> <MEMBER>
+func MakeRedisBatchCommandSortedSetRevRank(key, member string) *RedisBatchCommand {
+	output := &RedisBatchCommand{
+		cmd:   cmd_zrevrank,
+		args:  make([][]byte, 2)[0:0],
+		reply: nil,
+	}
+	output.WriteStringArg(key)
+	output.WriteStringArg(member)
+	return output
+}
+
+// ZREM <KEY> <MEMBER> <MEMBER> ...
+func MakeRedisBatchCommandSortedSetRemove(key string, members ...string) *RedisBatchCommand {
+	output := &RedisBatchCommand{
+		cmd:   cmd_zrem,
+		args:  make([][]byte, 1+len(members))[0:0],
+		reply: nil,
+	}
+	output.WriteStringArg(key)
+	output.WriteStringArgs(members)
+	return output
+}
+
+// ZREMRANGEBYSCORE <KEY> <MIN> <MAX>
+func MakeRedisBatchCommandSortedSetRemoveRangeByScore(key, min, max string) *RedisBatchCommand {
+	output := &RedisBatchCommand{
+		cmd:   cmd_zremrangebyscore,
+		args:  make([][]byte, 3)[0:0],
+		reply: nil,
+	}
+	output.WriteStringArg(key)
+	output.WriteStringArg(min)
+	output.WriteStringArg(max)
+	return output
+}
+
+// ZCARD <KEY>
+func MakeRedisBatchCommandSortedSetCard(key string) *RedisBatchCommand {
+	output := &RedisBatchCommand{
+		cmd:   cmd_zcard,
+		args:  make([][]byte, 1)[0:0],
+		reply: nil,
+	}
+	output.WriteStringArg(key)
+	return output
+}
+
+// ZCOUNT <KEY> <MIN> <MAX>
+func MakeRedisBatchCommandSortedSetCount(key, min, max string) *RedisBatchCommand {
+	output := &RedisBatchCommand{
+		cmd:   cmd_zcount,
+		args:  make([][]byte, 3)[0:0],
+		reply: nil,
+	}
+	output.WriteStringArg(key)
+	output.WriteStringArg(min)
+	output.WriteStringArg(max)
+	return output
+}
+
+// ZUNIONSTORE <DEST> <NUMKEYS> <KEY> <KEY> ... [WEIGHTS <WEIGHT> ...] [AGGREGATE SUM|MIN|MAX]
+func MakeRedisBatchCommandSortedSetUnionStore(dest string, options RedisSortedSetStoreOptions, keys ...string) *RedisBatchCommand {
+	return makeRedisBatchCommandSortedSetStore(cmd_zunionstore, dest, options, keys)
+}
+
+// ZINTERSTORE <DEST> <NUMKEYS> <KEY> <KEY> ... [WEIGHTS <WEIGHT> ...] [AGGREGATE SUM|MIN|MAX]
+func MakeRedisBatchCommandSortedSetInterStore(dest string, options RedisSortedSetStoreOptions, keys ...string) *RedisBatchCommand {
+	return makeRedisBatchCommandSortedSetStore(cmd_zinterstore, dest, options, keys)
+}
+
+func makeRedisBatchCommandSortedSetStore(cmd, dest string, options RedisSortedSetStoreOptions, keys []string) *RedisBatchCommand {
+	output := &RedisBatchCommand{
+		cmd:   cmd,
+		args:  make([][]byte, 5+len(keys)+len(options.Weights))[0:0],
+		reply: nil,
+	}
+	output.WriteStringArg(dest)
+	output.WriteIntArg(int64(len(keys)))
+	output.WriteStringArgs(keys)
+	if len(options.Weights) > 0 {
+		output.WriteStringArg("WEIGHTS")
+		for _, weight := range options.Weights {
+			output.writeScoreArg(weight)
+		}
+	}
+	if len(options.Aggregate) > 0 {
+		output.WriteStringArg("AGGREGATE")
+		output.WriteStringArg(options.Aggregate)
+	}
+	return output
+}
+
+// Scores & weights in full precision, unlike WriteFloatArg
+func (p *RedisBatchCommand) writeScoreArg(score float64) {
+	p.WriteArg([]byte(strconv.FormatFloat(score, 'f', -1, 64)))
+}
@@ -0,0 +1,136 @@
+package dog_pool
+
+import "math"
+import "github.com/alecthomas/log4go"
+import "github.com/RUNDSP/radix/redis"
+
+import "testing"
+import "github.com/orfjackal/gospec/src/gospec"
+
+func TestRedisBatchCommandSortedSetFactorySpecs(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping test in benchmark mode.")
+		return
+	}
+	r := gospec.NewRunner()
+	r.AddSpec(RedisBatchCommandSortedSetFactorySpecs)
+	gospec.MainGoTest(r, t)
+}
+
+func RedisBatchCommandSortedSetFactorySpecs(c gospec.Context) {
+
+	c.Specify("[MakeRedisBatchCommand][SortedSetAdd] Makes command", func() {
+		value := MakeRedisBatchCommandSortedSetAdd("Z", RedisSortedSetAddOptions{}, RedisSortedSetMember{"A", 1.5}, RedisSortedSetMember{"B", -2})
+		c.Expect(value.GetCmd(), gospec.Equals, "ZADD")
+		c.Expect(value.GetArgs(), gospec.Equals, []string{"Z", "1.5", "A", "-2", "B"})
+
+		value = MakeRedisBatchCommandSortedSetAdd("Z", RedisSortedSetAddOptions{XX: true, GT: true, CH: true}, RedisSortedSetMember{"A", 0.1})
+		c.Expect(value.GetArgs(), gospec.Equals, []string{"Z", "XX", "GT", "CH", "0.1", "A"})
+
+		value = MakeRedisBatchCommandSortedSetAdd("Z", RedisSortedSetAddOptions{NX: true, LT: true, INCR: true}, RedisSortedSetMember{"A", math.Inf(1)})
+		c.Expect(value.GetArgs(), gospec.Equals, []string{"Z", "NX", "LT", "INCR", "+Inf", "A"})
+	})
+
+	c.Specify("[MakeRedisBatchCommand][SortedSetIncrementBy] Makes command", func() {
+		value := MakeRedisBatchCommandSortedSetIncrementBy("Z", "A", 1700000000.25)
+		c.Expect(value.GetCmd(), gospec.Equals, "ZINCRBY")
+		c.Expect(value.GetArgs(), gospec.Equals, []string{"Z", "1700000000.25", "A"})
+	})
+
+	c.Specify("[MakeRedisBatchCommand][SortedSetRange] Makes command", func() {
+		value := MakeRedisBatchCommandSortedSetRange("Z", "0", "-1", RedisSortedSetRangeOptions{})
+		c.Expect(value.GetCmd(), gospec.Equals, "ZRANGE")
+		c.Expect(value.GetArgs(), gospec.Equals, []string{"Z", "0", "-1"})
+
+		value = MakeRedisBatchCommandSortedSetRange("Z", "+inf", "(5", RedisSortedSetRangeOptions{ByScore: true, Rev: true, Offset: 10, Count: 5, WithScores: true})
+		c.Expect(value.GetArgs(), gospec.Equals, []string{"Z", "+inf", "(5", "BYSCORE", "REV", "LIMIT", "10", "5", "WITHSCORES"})
+
+		value = MakeRedisBatchCommandSortedSetRange("Z", "[a", "-", RedisSortedSetRangeOptions{ByLex: true, Count: -1})
+		c.Expect(value.GetArgs(), gospec.Equals, []string{"Z", "[a", "-", "BYLEX", "LIMIT", "0", "-1"})
+	})
+
+	c.Specify("[MakeRedisBatchCommand][SortedSet...] Makes the single key commands", func() {
+		for _, test := range []struct {
+			value *RedisBatchCommand
+			cmd   string
+			args  []string
+		}{
+			{MakeRedisBatchCommandSortedSetRank("Z", "A"), "ZRANK", []string{"Z", "A"}},
+			{MakeRedisBatchCommandSortedSetRevRank("Z", "A"), "ZREVRANK", []string{"Z", "A"}},
+			{MakeRedisBatchCommandSortedSetRemove("Z", "A", "B"), "ZREM", []string{"Z", "A", "B"}},
+			{MakeRedisBatchCommandSortedSetRemoveRangeByScore("Z", "-inf", "(10"), "ZREMRANGEBYSCORE", []string{"Z", "-inf", "(10"}},
+			{MakeRedisBatchCommandSortedSetCard("Z"), "ZCARD", []string{"Z"}},
+			{MakeRedisBatchCommandSortedSetCount("Z", "1", "2"), "ZCOUNT", []string{"Z", "1", "2"}},
+		} {
+			c.Expect(test.value.GetCmd(), gospec.Equals, test.cmd)
+			c.Expect(test.value.GetArgs(), gospec.Equals, test.args)
+		}
+	})
+
+	c.Specify("[MakeRedisBatchCommand][SortedSetUnionStore] Makes command", func() {
+		value := MakeRedisBatchCommandSortedSetUnionStore("D", RedisSortedSetStoreOptions{}, "A", "B")
+		c.Expect(value.GetCmd(), gospec.Equals, "ZUNIONSTORE")
+		c.Expect(value.GetArgs(), gospec.Equals, []string{"D", "2", "A", "B"})
+
+		value = MakeRedisBatchCommandSortedSetInterStore("D", RedisSortedSetStoreOptions{Weights: []float64{1, 0.5}, Aggregate: "MAX"}, "A", "B")
+		c.Expect(value.GetCmd(), gospec.Equals, "ZINTERSTORE")
+		c.Expect(value.GetArgs(), gospec.Equals, []string{"D", "2", "A", "B", "WEIGHTS", "1", "0.5", "AGGREGATE", "MAX"})
+	})
+
+	c.Specify("[ReplyToSortedSetMembers] Rejects replies that aren't member/score pairs", func() {
+		_, err := ReplyToSortedSetMembers(&redis.Reply{Type: redis.ErrorReply, Err: ErrConnectionIsClosed})
+		c.Expect(err, gospec.Equals, ErrConnectionIsClosed)
+
+		_, err = ReplyToSortedSetMembers(&redis.Reply{Type: redis.NilReply})
+		c.Expect(err, gospec.Satisfies, nil != err)
+
+		_, err = ReplyToSortedSetMembers(&redis.Reply{Type: redis.MultiReply, Elems: []*redis.Reply{{Type: redis.NilReply}}})
+		c.Expect(err.Error(), gospec.Equals, "Reply is not member/score pairs, 1 elements")
+
+		members, err := ReplyToSortedSetMembers(&redis.Reply{Type: redis.MultiReply})
+		c.Expect(err, gospec.Equals, nil)
+		c.Expect(len(members), gospec.Equals, 0)
+	})
+
+	c.Specify("[RedisBatchCommands][SortedSet] Runs the sorted set commands", func() {
+		logger := MakeLog4goLogger(log4go.NewDefaultLogger(log4go.CRITICAL))
+		server, err := StartRedisServer(logger)
+		if nil != err {
+			panic(err)
+		}
+		defer server.Close()
+
+		add := MakeRedisBatchCommandSortedSetAdd("Z", RedisSortedSetAddOptions{}, RedisSortedSetMember{"A", 1}, RedisSortedSetMember{"B", 2}, RedisSortedSetMember{"C", 3})
+		incr := MakeRedisBatchCommandSortedSetIncrementBy("Z", "A", 2.5)
+		top := MakeRedisBatchCommandSortedSetRange("Z", "+inf", "(2", RedisSortedSetRangeOptions{ByScore: true, Rev: true, WithScores: true})
+		rank := MakeRedisBatchCommandSortedSetRank("Z", "B")
+		count := MakeRedisBatchCommandSortedSetCount("Z", "-inf", "3")
+		union := MakeRedisBatchCommandSortedSetUnionStore("U", RedisSortedSetStoreOptions{Weights: []float64{2}}, "Z")
+		trim := MakeRedisBatchCommandSortedSetRemoveRangeByScore("Z", "-inf", "(3")
+		card := MakeRedisBatchCommandSortedSetCard("Z")
+
+		err = RedisBatchCommands{add, incr, top, rank, count, union, trim, card}.ExecuteBatch(server.Connection())
+		c.Expect(err, gospec.Equals, nil)
+
+		added, _ := add.ReplyToInt64Ptr()
+		c.Expect(*added, gospec.Equals, int64(3))
+
+		score, _ := incr.ReplyToFloat64Ptr()
+		c.Expect(*score, gospec.Equals, 3.5)
+
+		members, err := top.ReplyToSortedSetMembers()
+		c.Expect(err, gospec.Equals, nil)
+		c.Expect(members, gospec.Equals, []RedisSortedSetMember{{"A", 3.5}, {"C", 3}})
+
+		value, _ := rank.ReplyToInt64Ptr()
+		c.Expect(*value, gospec.Equals, int64(0))
+		value, _ = count.ReplyToInt64Ptr()
+		c.Expect(*value, gospec.Equals, int64(2))
+		value, _ = union.ReplyToInt64Ptr()
+		c.Expect(*value, gospec.Equals, int64(3))
+		value, _ = trim.ReplyToInt64Ptr()
+		c.Expect(*value, gospec.Equals, int64(1))
+		value, _ = card.ReplyToInt64Ptr()
+		c.Expect(*value, gospec.Equals, int64(2))
+	})
+}
@@ -1,5 +1,7 @@
 package dog_pool
 
+import "context"
+
 //
 // Typedef for an array of RedisBatchCommand commands
 //
@@ -9,21 +11,90 @@ type RedisBatchCommands []*RedisBatchCommand
 // Execute the batch on a connection
 //
 func (commands RedisBatchCommands) ExecuteBatch(connection RedisClientInterface) (err error) {
+	return commands.ExecuteBatchContext(context.Background(), connection)
+}
+
+//
+// Execute the batch on a connection, in a child span of the context
+//
+// Returns:
+//   nil              --> Every command succeeded
+//   *RedisBatchError --> The commands that failed, every reply is filled in
+//
+func (commands RedisBatchCommands) ExecuteBatchContext(ctx context.Context, connection RedisClientInterface) (err error) {
+	return commands.executeBatch(ctx, connection, false)
+}
+
+//
+// Execute the batch on a connection, failing the remaining commands without reading their replies after a connection error
+//
+func (commands RedisBatchCommands) ExecuteBatchFailFast(connection RedisClientInterface) (err error) {
+	return commands.ExecuteBatchFailFastContext(context.Background(), connection)
+}
+
+//
+// Execute the batch on a connection in a child span of the context, see ExecuteBatchFailFast
+//
+func (commands RedisBatchCommands) ExecuteBatchFailFastContext(ctx context.Context, connection RedisClientInterface) (err error) {
+	return commands.executeBatch(ctx, connection, true)
+}
+
+func (commands RedisBatchCommands) executeBatch(ctx context.Context, connection RedisClientInterface, fail_fast bool) (err error) {
 	err = nil
 
+	// Trace the pipeline
+	tracer, url := redisTracerOf(connection)
+	_, span := startSpan(ctx, tracer, "redis", "PIPELINE", url, commands.keyCount)
+	if nil != tracer {
+		span.SetAttribute("db.command_count", len(commands))
+	}
+	defer func() { endSpan(span, "multi", err) }()
+
+	// Time the pipeline for the slow-log
+	if slow_log, url := redisSlowLogOf(connection); nil != slow_log {
+		stop_watch := MakeStopWatchTags(connection, nil, []string{url, "PIPELINE"}).Start()
+		defer func() {
+			slow_log.Observe(stop_watch.Stop(), url, func() (string, string) {
+				return "PIPELINE", commands.slowLogSummary()
+			})
+		}()
+	}
+
 	// Append the commands
 	for _, command := range commands {
 		command.RedisAppend(connection)
 	}
 
 	// Execute the commands
-	for _, command := range commands {
+	var batch_err *RedisBatchError
+	for i, command := range commands {
 		command_err := command.RedisGetReply(connection).Err
-		if nil != command_err {
-			err = command_err
+		if nil == command_err {
+			continue
+		}
+		batch_err = batch_err.add(len(commands), i, command, command_err)
+
+		// Don't wait on the replies of a broken connection
+		if fail_fast && IsRedisConnectionError(command_err) && i+1 < len(commands) {
+			batch_err.abort(connection, i+1, commands[i+1:], command_err)
+			break
 		}
 	}
 
-	// Return the error if any was found
+	// Return the failed commands if any were found
+	if nil != batch_err {
+		err = batch_err
+	}
 	return err
 }
+
+//
+// Number of keys the commands touch
+//
+func (commands RedisBatchCommands) keyCount() int {
+	count := 0
+	for _, command := range commands {
+		count += redisKeyCount(command.cmd, command.interfaceArgs())
+	}
+	return count
+}
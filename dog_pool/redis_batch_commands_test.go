@@ -22,8 +22,8 @@ func TestRedisBatchCommandsSpecs(t *testing.T) {
 func RedisBatchCommandsSpecs(c gospec.Context) {
 
 	c.Specify("[RedisBatchCommands] PING Test", func() {
-		logger := log4go.NewDefaultLogger(log4go.CRITICAL)
-		server, err := StartRedisServer(&logger)
+		logger := MakeLog4goLogger(log4go.NewDefaultLogger(log4go.CRITICAL))
+		server, err := StartRedisServer(logger)
 		if nil != err {
 			panic(err)
 		}
@@ -42,8 +42,8 @@ func RedisBatchCommandsSpecs(c gospec.Context) {
 	})
 
 	c.Specify("[RedisBatchCommands] Value Exists", func() {
-		logger := log4go.NewDefaultLogger(log4go.CRITICAL)
-		server, err := StartRedisServer(&logger)
+		logger := MakeLog4goLogger(log4go.NewDefaultLogger(log4go.CRITICAL))
+		server, err := StartRedisServer(logger)
 		if nil != err {
 			panic(err)
 		}
@@ -68,8 +68,8 @@ func RedisBatchCommandsSpecs(c gospec.Context) {
 	})
 
 	c.Specify("[RedisBatchCommands] Expire value", func() {
-		logger := log4go.NewDefaultLogger(log4go.CRITICAL)
-		server, err := StartRedisServer(&logger)
+		logger := MakeLog4goLogger(log4go.NewDefaultLogger(log4go.CRITICAL))
+		server, err := StartRedisServer(logger)
 		if nil != err {
 			panic(err)
 		}
@@ -133,8 +133,8 @@ func RedisBatchCommandsSpecs(c gospec.Context) {
 	})
 
 	c.Specify("[RedisBatchCommands] Delete values", func() {
-		logger := log4go.NewDefaultLogger(log4go.CRITICAL)
-		server, err := StartRedisServer(&logger)
+		logger := MakeLog4goLogger(log4go.NewDefaultLogger(log4go.CRITICAL))
+		server, err := StartRedisServer(logger)
 		if nil != err {
 			panic(err)
 		}
@@ -157,8 +157,8 @@ func RedisBatchCommandsSpecs(c gospec.Context) {
 	})
 
 	c.Specify("[RedisBatchCommands] Mget values", func() {
-		logger := log4go.NewDefaultLogger(log4go.CRITICAL)
-		server, err := StartRedisServer(&logger)
+		logger := MakeLog4goLogger(log4go.NewDefaultLogger(log4go.CRITICAL))
+		server, err := StartRedisServer(logger)
 		if nil != err {
 			panic(err)
 		}
@@ -184,8 +184,8 @@ func RedisBatchCommandsSpecs(c gospec.Context) {
 	})
 
 	c.Specify("[RedisBatchCommands] Get values", func() {
-		logger := log4go.NewDefaultLogger(log4go.CRITICAL)
-		server, err := StartRedisServer(&logger)
+		logger := MakeLog4goLogger(log4go.NewDefaultLogger(log4go.CRITICAL))
+		server, err := StartRedisServer(logger)
 		if nil != err {
 			panic(err)
 		}
@@ -216,8 +216,8 @@ func RedisBatchCommandsSpecs(c gospec.Context) {
 	})
 
 	c.Specify("[RedisBatchCommands] Set value", func() {
-		logger := log4go.NewDefaultLogger(log4go.CRITICAL)
-		server, err := StartRedisServer(&logger)
+		logger := MakeLog4goLogger(log4go.NewDefaultLogger(log4go.CRITICAL))
+		server, err := StartRedisServer(logger)
 		if nil != err {
 			panic(err)
 		}
@@ -236,8 +236,8 @@ func RedisBatchCommandsSpecs(c gospec.Context) {
 	})
 
 	c.Specify("[RedisBatchCommands] IncrementBy value", func() {
-		logger := log4go.NewDefaultLogger(log4go.CRITICAL)
-		server, err := StartRedisServer(&logger)
+		logger := MakeLog4goLogger(log4go.NewDefaultLogger(log4go.CRITICAL))
+		server, err := StartRedisServer(logger)
 		if nil != err {
 			panic(err)
 		}
@@ -257,8 +257,8 @@ func RedisBatchCommandsSpecs(c gospec.Context) {
 	})
 
 	c.Specify("[RedisBatchCommands] IncrementByFloat value", func() {
-		logger := log4go.NewDefaultLogger(log4go.CRITICAL)
-		server, err := StartRedisServer(&logger)
+		logger := MakeLog4goLogger(log4go.NewDefaultLogger(log4go.CRITICAL))
+		server, err := StartRedisServer(logger)
 		if nil != err {
 			panic(err)
 		}
@@ -278,8 +278,8 @@ func RedisBatchCommandsSpecs(c gospec.Context) {
 	})
 
 	c.Specify("[RedisBatchCommands] Hash Value Exists", func() {
-		logger := log4go.NewDefaultLogger(log4go.CRITICAL)
-		server, err := StartRedisServer(&logger)
+		logger := MakeLog4goLogger(log4go.NewDefaultLogger(log4go.CRITICAL))
+		server, err := StartRedisServer(logger)
 		if nil != err {
 			panic(err)
 		}
@@ -304,8 +304,8 @@ func RedisBatchCommandsSpecs(c gospec.Context) {
 	})
 
 	c.Specify("[RedisBatchCommands] Hash Delete values", func() {
-		logger := log4go.NewDefaultLogger(log4go.CRITICAL)
-		server, err := StartRedisServer(&logger)
+		logger := MakeLog4goLogger(log4go.NewDefaultLogger(log4go.CRITICAL))
+		server, err := StartRedisServer(logger)
 		if nil != err {
 			panic(err)
 		}
@@ -330,8 +330,8 @@ func RedisBatchCommandsSpecs(c gospec.Context) {
 	})
 
 	c.Specify("[RedisBatchCommands] Hash Mget values", func() {
-		logger := log4go.NewDefaultLogger(log4go.CRITICAL)
-		server, err := StartRedisServer(&logger)
+		logger := MakeLog4goLogger(log4go.NewDefaultLogger(log4go.CRITICAL))
+		server, err := StartRedisServer(logger)
 		if nil != err {
 			panic(err)
 		}
@@ -357,8 +357,8 @@ func RedisBatchCommandsSpecs(c gospec.Context) {
 	})
 
 	c.Specify("[RedisBatchCommands] Hash Get values", func() {
-		logger := log4go.NewDefaultLogger(log4go.CRITICAL)
-		server, err := StartRedisServer(&logger)
+		logger := MakeLog4goLogger(log4go.NewDefaultLogger(log4go.CRITICAL))
+		server, err := StartRedisServer(logger)
 		if nil != err {
 			panic(err)
 		}
@@ -389,8 +389,8 @@ func RedisBatchCommandsSpecs(c gospec.Context) {
 	})
 
 	c.Specify("[RedisBatchCommands] Hash Set value", func() {
-		logger := log4go.NewDefaultLogger(log4go.CRITICAL)
-		server, err := StartRedisServer(&logger)
+		logger := MakeLog4goLogger(log4go.NewDefaultLogger(log4go.CRITICAL))
+		server, err := StartRedisServer(logger)
 		if nil != err {
 			panic(err)
 		}
@@ -409,8 +409,8 @@ func RedisBatchCommandsSpecs(c gospec.Context) {
 	})
 
 	c.Specify("[RedisBatchCommands] Hash IncrementBy value", func() {
-		logger := log4go.NewDefaultLogger(log4go.CRITICAL)
-		server, err := StartRedisServer(&logger)
+		logger := MakeLog4goLogger(log4go.NewDefaultLogger(log4go.CRITICAL))
+		server, err := StartRedisServer(logger)
 		if nil != err {
 			panic(err)
 		}
@@ -430,8 +430,8 @@ func RedisBatchCommandsSpecs(c gospec.Context) {
 	})
 
 	c.Specify("[MakeRedisBatchCommand][Bitop][And] Makes command", func() {
-		logger := log4go.NewDefaultLogger(log4go.CRITICAL)
-		server, err := StartRedisServer(&logger)
+		logger := MakeLog4goLogger(log4go.NewDefaultLogger(log4go.CRITICAL))
+		server, err := StartRedisServer(logger)
 		if nil != err {
 			panic(err)
 		}
@@ -453,8 +453,8 @@ func RedisBatchCommandsSpecs(c gospec.Context) {
 	})
 
 	c.Specify("[MakeRedisBatchCommand][Bitop][Or] Makes command", func() {
-		logger := log4go.NewDefaultLogger(log4go.CRITICAL)
-		server, err := StartRedisServer(&logger)
+		logger := MakeLog4goLogger(log4go.NewDefaultLogger(log4go.CRITICAL))
+		server, err := StartRedisServer(logger)
 		if nil != err {
 			panic(err)
 		}
@@ -476,8 +476,8 @@ func RedisBatchCommandsSpecs(c gospec.Context) {
 	})
 
 	c.Specify("[MakeRedisBatchCommand][Bitop][Not] Makes command", func() {
-		logger := log4go.NewDefaultLogger(log4go.CRITICAL)
-		server, err := StartRedisServer(&logger)
+		logger := MakeLog4goLogger(log4go.NewDefaultLogger(log4go.CRITICAL))
+		server, err := StartRedisServer(logger)
 		if nil != err {
 			panic(err)
 		}
@@ -498,8 +498,8 @@ func RedisBatchCommandsSpecs(c gospec.Context) {
 	})
 
 	c.Specify("[MakeRedisBatchCommand][BitCount] Makes command", func() {
-		logger := log4go.NewDefaultLogger(log4go.CRITICAL)
-		server, err := StartRedisServer(&logger)
+		logger := MakeLog4goLogger(log4go.NewDefaultLogger(log4go.CRITICAL))
+		server, err := StartRedisServer(logger)
 		if nil != err {
 			panic(err)
 		}
@@ -521,8 +521,8 @@ func RedisBatchCommandsSpecs(c gospec.Context) {
 	})
 
 	c.Specify("[MakeRedisBatchCommand][BitCount] Makes command", func() {
-		logger := log4go.NewDefaultLogger(log4go.CRITICAL)
-		server, err := StartRedisServer(&logger)
+		logger := MakeLog4goLogger(log4go.NewDefaultLogger(log4go.CRITICAL))
+		server, err := StartRedisServer(logger)
 		if nil != err {
 			panic(err)
 		}
@@ -547,8 +547,8 @@ func RedisBatchCommandsSpecs(c gospec.Context) {
 	})
 
 	c.Specify("[MakeRedisBatchCommand][SetBit] Makes command", func() {
-		logger := log4go.NewDefaultLogger(log4go.CRITICAL)
-		server, err := StartRedisServer(&logger)
+		logger := MakeLog4goLogger(log4go.NewDefaultLogger(log4go.CRITICAL))
+		server, err := StartRedisServer(logger)
 		if nil != err {
 			panic(err)
 		}
@@ -583,8 +583,8 @@ func RedisBatchCommandsSpecs(c gospec.Context) {
 // Benchmark Bit Operation A & !B on 10x keys
 //
 func Benchmark_BitOp_ComplementSet_BatchCommands(b *testing.B) {
-	logger := log4go.NewDefaultLogger(log4go.CRITICAL)
-	server, err := StartRedisServer(&logger)
+	logger := MakeLog4goLogger(log4go.NewDefaultLogger(log4go.CRITICAL))
+	server, err := StartRedisServer(logger)
 	if nil != err {
 		panic(err)
 	}
@@ -0,0 +1,142 @@
+//
+// Errors returned by RedisBatchCommands.ExecuteBatch
+//
+// Every failed command is listed with its index & error, the replies of every command are still filled in.
+//
+// Usage:
+//   err := cmds.ExecuteBatch(connection)
+//
+//   var batch_err *RedisBatchError
+//   if errors.As(err, &batch_err) {
+//     for _, failed := range batch_err.Errors {
+//       log.Printf("[%v] %v --> %v", failed.Index, failed.Command, failed.Err)
+//     }
+//   }
+//
+//   if errors.Is(err, ErrConnectionIsClosed) {
+//     ...
+//   }
+//
+
+package dog_pool
+
+import "fmt"
+import "github.com/RUNDSP/radix/redis"
+
+//
+// A command of the batch that failed
+//
+type RedisBatchCommandError struct {
+	Index   int                "Index of the command in the batch"
+	Command *RedisBatchCommand "Command that failed"
+	Err     error              "Error in the command's reply"
+}
+
+func (p *RedisBatchCommandError) Error() string {
+	return fmt.Sprintf("[%v] %v: %v", p.Index, p.Command.GetCmd(), p.Err)
+}
+
+func (p *RedisBatchCommandError) Unwrap() error {
+	return p.Err
+}
+
+//
+// Every command of the batch that failed, in order
+//
+type RedisBatchError struct {
+	Size    int                       "Number of commands in the batch"
+	Errors  []*RedisBatchCommandError "Commands that failed"
+	Aborted int                       "Number of replies never read after a connection error, see ExecuteBatchFailFast"
+}
+
+func (p *RedisBatchError) Error() string {
+	if p.Aborted > 0 {
+		return fmt.Sprintf("[RedisBatchCommands][ExecuteBatch] %v of %v commands failed, %v aborted: %v", len(p.Errors), p.Size, p.Aborted, p.Errors[0])
+	}
+	return fmt.Sprintf("[RedisBatchCommands][ExecuteBatch] %v of %v commands failed: %v", len(p.Errors), p.Size, p.Errors[0])
+}
+
+//
+// Errors of the failed commands, for errors.Is & errors.As
+//
+func (p *RedisBatchError) Unwrap() []error {
+	output := make([]error, len(p.Errors))
+	for i, err := range p.Errors {
+		output[i] = err
+	}
+	return output
+}
+
+//
+// Commands that failed, in order
+//
+func (p *RedisBatchError) Failed() RedisBatchCommands {
+	output := make(RedisBatchCommands, len(p.Errors))
+	for i, err := range p.Errors {
+		output[i] = err.Command
+	}
+	return output
+}
+
+//
+// Record the failed command, making the error if needed
+//
+func (p *RedisBatchError) add(size, index int, command *RedisBatchCommand, err error) *RedisBatchError {
+	if nil == p {
+		p = &RedisBatchError{Size: size}
+	}
+	p.Errors = append(p.Errors, &RedisBatchCommandError{Index: index, Command: command, Err: err})
+	return p
+}
+
+//
+// Fail the commands whose replies will never be read with the connection error, & discard them from the pipeline
+//
+func (p *RedisBatchError) abort(connection RedisClientInterface, offset int, commands RedisBatchCommands, err error) {
+	discardRedisPipeline(connection, err)
+
+	for i, command := range commands {
+		command.reply = &redis.Reply{Type: redis.ErrorReply, Err: err}
+		p.Errors = append(p.Errors, &RedisBatchCommandError{Index: offset + i, Command: command, Err: err})
+	}
+	p.Aborted += len(commands)
+}
+
+//
+// Implemented by clients that can drop the replies queued in their pipeline, i.e. RedisConnection
+//
+type redisPipelineDiscarder interface {
+	discardPipeline(err error)
+}
+
+//
+// Drop the replies queued in the client's pipeline, closing the client if it can't
+//
+func discardRedisPipeline(client RedisClientInterface, err error) {
+	if discarder, ok := client.(redisPipelineDiscarder); ok {
+		discarder.discardPipeline(err)
+		return
+	}
+	client.Close()
+}
+
+//
+// Close the connection & fail the hooks of the commands still queued, their replies are never read
+//
+func (p *RedisConnection) discardPipeline(err error) {
+	p.Close()
+
+	for _, call := range p.hook_queue {
+		p.Hooks.afterReply(call, &redis.Reply{Type: redis.ErrorReply, Err: err})
+	}
+	p.hook_queue = nil
+	p.cmd_queue = nil
+}
+
+//
+// Discard the pipeline of the wrapped client, & the errors queued for its commands
+//
+func (p *RedisNamespacedClient) discardPipeline(err error) {
+	p.pending = nil
+	discardRedisPipeline(p.Client, err)
+}
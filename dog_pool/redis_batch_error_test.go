@@ -0,0 +1,124 @@
+package dog_pool
+
+import "errors"
+import "strings"
+import "github.com/RUNDSP/radix/redis"
+
+import "testing"
+import "github.com/orfjackal/gospec/src/gospec"
+
+func TestRedisBatchErrorSpecs(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping test in benchmark mode.")
+		return
+	}
+	r := gospec.NewRunner()
+	r.AddSpec(RedisBatchErrorSpecs)
+	gospec.MainGoTest(r, t)
+}
+
+var errTestWrongType = errors.New("WRONGTYPE Operation against a key holding the wrong kind of value")
+
+// Connection failing the key "A" (namespaced or not) with WRONGTYPE & every other key with ErrConnectionIsClosed, without reaching Redis
+func makeTestRedisBatchErrorConnection(replies *int) *RedisConnection {
+	connection := &RedisConnection{Url: "127.0.0.1:6991"}
+	connection.Hooks = RedisHooks{RedisHookFuncs{
+		Before: func(call *RedisHookCall) error {
+			if strings.HasSuffix(toArgString(flattenArgs(call.Args)[0]), "A") {
+				return errTestWrongType
+			}
+			return ErrConnectionIsClosed
+		},
+		After: func(call *RedisHookCall, reply *redis.Reply) *redis.Reply {
+			*replies++
+			return reply
+		},
+	}}
+	return connection
+}
+
+func RedisBatchErrorSpecs(c gospec.Context) {
+
+	c.Specify("[RedisBatchCommands][ExecuteBatch] Returns nil when every command succeeds", func() {
+		cmds := RedisBatchCommands{MakeRedisBatchCommandGet("A"), MakeRedisBatchCommandGet("B")}
+		c.Expect(cmds.ExecuteBatch(&recordingRedisClient{}), gospec.Equals, nil)
+		c.Expect(cmds.ExecuteBatchFailFast(&recordingRedisClient{}), gospec.Equals, nil)
+	})
+
+	c.Specify("[RedisBatchCommands][ExecuteBatch] Lists every failed command", func() {
+		replies := 0
+		connection := makeTestRedisBatchErrorConnection(&replies)
+		cmds := RedisBatchCommands{MakeRedisBatchCommandGet("A"), MakeRedisBatchCommandGet("B"), MakeRedisBatchCommandGet("C")}
+
+		err := cmds.ExecuteBatch(connection)
+		c.Expect(err.Error(), gospec.Equals, "[RedisBatchCommands][ExecuteBatch] 3 of 3 commands failed: [0] GET: "+errTestWrongType.Error())
+		c.Expect(errors.Is(err, errTestWrongType), gospec.Equals, true)
+		c.Expect(errors.Is(err, ErrConnectionIsClosed), gospec.Equals, true)
+		c.Expect(errors.Is(err, ErrQueueIsFull), gospec.Equals, false)
+		c.Expect(replies, gospec.Equals, 3)
+
+		var batch_err *RedisBatchError
+		c.Expect(errors.As(err, &batch_err), gospec.Equals, true)
+		c.Expect(batch_err.Size, gospec.Equals, 3)
+		c.Expect(batch_err.Aborted, gospec.Equals, 0)
+		c.Expect(batch_err.Failed(), gospec.Equals, cmds)
+		c.Expect(batch_err.Errors[1].Index, gospec.Equals, 1)
+		c.Expect(batch_err.Errors[1].Err, gospec.Equals, ErrConnectionIsClosed)
+
+		var cmd_err *RedisBatchCommandError
+		c.Expect(errors.As(err, &cmd_err), gospec.Equals, true)
+		c.Expect(cmd_err.Command, gospec.Equals, cmds[0])
+	})
+
+	c.Specify("[RedisBatchCommands][ExecuteBatchFailFast] Stops reading the replies after a connection error", func() {
+		replies := 0
+		connection := makeTestRedisBatchErrorConnection(&replies)
+		cmds := RedisBatchCommands{MakeRedisBatchCommandGet("A"), MakeRedisBatchCommandGet("B"), MakeRedisBatchCommandGet("C"), MakeRedisBatchCommandGet("D")}
+
+		err := cmds.ExecuteBatchFailFast(MakeRedisNamespacedClient("ns:", connection))
+		c.Expect(err.Error(), gospec.Equals, "[RedisBatchCommands][ExecuteBatch] 4 of 4 commands failed, 2 aborted: [0] GET: "+errTestWrongType.Error())
+
+		var batch_err *RedisBatchError
+		c.Expect(errors.As(err, &batch_err), gospec.Equals, true)
+		c.Expect(batch_err.Aborted, gospec.Equals, 2)
+		c.Expect(batch_err.Errors[3].Index, gospec.Equals, 3)
+		c.Expect(cmds[3].Reply().Err, gospec.Equals, ErrConnectionIsClosed)
+
+		// The hooks see every reply, the pipeline is empty for the next batch
+		c.Expect(replies, gospec.Equals, 4)
+		c.Expect(len(connection.hook_queue), gospec.Equals, 0)
+		c.Expect(connection.GetReply().Err, gospec.Equals, ErrConnectionIsClosed)
+	})
+
+	c.Specify("[RedisBatchCommands][ExecuteBatchFailFast] Leaves the namespaced client in sync for the next batch", func() {
+		replies := 0
+		connection := makeTestRedisBatchErrorConnection(&replies)
+		client := MakeRedisNamespacedClient("ns:", connection)
+
+		cmds := RedisBatchCommands{MakeRedisBatchCommandGet("A"), MakeRedisBatchCommandGet("B"), MakeRedisBatchCommandGet("C"), MakeRedisBatchCommandGet("D")}
+		err := cmds.ExecuteBatchFailFast(client)
+		c.Expect(err, gospec.Satisfies, nil != err)
+		c.Expect(len(client.pending), gospec.Equals, 0)
+
+		unknown := MakeRedisBatchCommand("NOTACOMMAND")
+		unknown.WriteStringArg("A")
+		cmds = RedisBatchCommands{unknown, MakeRedisBatchCommandGet("A")}
+		err = cmds.ExecuteBatch(client)
+		c.Expect(err, gospec.Satisfies, nil != err)
+		c.Expect(unknown.Reply().Err.Error(), gospec.Equals, "[RedisNamespacedClient] Unknown command 'NOTACOMMAND', unable to namespace its keys")
+		c.Expect(cmds[1].Reply().Err, gospec.Equals, errTestWrongType)
+		c.Expect(len(client.pending), gospec.Equals, 0)
+	})
+
+	c.Specify("[redisBatchQueueWorker][runCommands] Fails fast", func() {
+		replies := 0
+		ptr, err := makeRedisBatchQueueWorker(nil, makeTestRedisBatchErrorConnection(&replies), 10, make(chan *RedisBatchCommand))
+		c.Expect(err, gospec.Equals, nil)
+		ptr.FailFast = true
+
+		cmds := RedisBatchCommands{MakeRedisBatchCommandGet("B"), MakeRedisBatchCommandGet("C")}
+		ptr.runCommands(cmds)
+		c.Expect(cmds[1].Reply().Err, gospec.Equals, ErrConnectionIsClosed)
+		c.Expect(len(ptr.Connection.hook_queue), gospec.Equals, 0)
+	})
+}
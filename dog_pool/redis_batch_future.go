@@ -0,0 +1,214 @@
+//
+// Futures & completion callbacks for the RedisBatchQueue
+//
+// Usage:
+//   future, err := queue.RunAsyncFuture(cmds...)
+//   ...
+//   err = future.Wait(ctx)
+//
+//   err := queue.RunAsyncCallback(func(cmds RedisBatchCommands, err error) { ... }, cmds...)
+//
+//   err := queue.RunSync(ctx, cmds...)
+//
+
+package dog_pool
+
+import "context"
+import "fmt"
+import "sync"
+
+//
+// Callback invoked once every command in the batch has its Reply(),
+// it runs on the worker's go routine and must not block.
+//
+type RedisBatchCallback func(cmds RedisBatchCommands, err error)
+
+//
+// Resolves once the queue's workers have filled in the Reply() of every command
+//
+type RedisBatchFuture struct {
+	Commands RedisBatchCommands "Commands we are waiting on"
+
+	mutex    sync.Mutex
+	pending  int                "Number of commands without a reply"
+	err      error              "First error in the replies, in the order of the commands"
+	done     chan struct{}      "Closed once every command has a reply"
+	callback RedisBatchCallback "(optional) Called once every command has a reply"
+}
+
+//
+// Make a future for the commands, attaching it to each command
+//
+func makeRedisBatchFuture(cmds RedisBatchCommands, callback RedisBatchCallback) *RedisBatchFuture {
+	p := &RedisBatchFuture{
+		Commands: cmds,
+		pending:  len(cmds),
+		done:     make(chan struct{}),
+		callback: callback,
+	}
+
+	for _, cmd := range cmds {
+		cmd.future = p
+	}
+
+	// Nothing to wait on
+	if 0 == p.pending {
+		p.resolve()
+	}
+
+	return p
+}
+
+// Format as a string
+func (p *RedisBatchFuture) String() string {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	return fmt.Sprintf("RedisBatchFuture { Commands.Length=%v, Pending=%v, Err=%v }", len(p.Commands), p.pending, p.err)
+}
+
+//
+// Closed once every command has a reply
+//
+func (p *RedisBatchFuture) Done() <-chan struct{} {
+	return p.done
+}
+
+//
+// Has every command got a reply?
+//
+func (p *RedisBatchFuture) IsDone() bool {
+	select {
+	case <-p.done:
+		return true
+	default:
+		return false
+	}
+}
+
+//
+// First error in the replies, nil until the future is done
+//
+func (p *RedisBatchFuture) Err() error {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	return p.err
+}
+
+//
+// Block until every command has a reply, or the context is done.
+// The commands still run if the context is done first.
+//
+// Returns:
+//   nil         --> Every command succeeded
+//   err         --> First error in the replies
+//   ctx.Err()   --> The context was done first
+//
+func (p *RedisBatchFuture) Wait(ctx context.Context) error {
+	select {
+	case <-p.done:
+		return p.Err()
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+//
+// Called by the worker once the command has a reply
+//
+func (p *RedisBatchFuture) complete() {
+	p.mutex.Lock()
+	p.pending--
+	pending := p.pending
+	p.mutex.Unlock()
+
+	if 0 == pending {
+		p.resolve()
+	}
+}
+
+//
+// Find the first error, close the done channel and call the callback
+//
+func (p *RedisBatchFuture) resolve() {
+	var err error
+	for i, cmd := range p.Commands {
+		if reply := cmd.Reply(); nil == reply {
+			err = fmt.Errorf("[RedisBatchFuture][%v] No reply for %v!", i, cmd.GetCmd())
+		} else {
+			err = reply.Err
+		}
+
+		if nil != err {
+			break
+		}
+	}
+
+	p.mutex.Lock()
+	p.err = err
+	p.mutex.Unlock()
+
+	close(p.done)
+
+	if nil != p.callback {
+		p.callback(p.Commands, err)
+	}
+}
+
+//
+// ==================================================
+//
+// RedisBatchQueue integration:
+//
+// ==================================================
+//
+
+//
+// Push the command(s) onto the queue, returning a future that resolves once every command has a reply
+//
+func (p *RedisBatchQueue) RunAsyncFuture(cmds ...*RedisBatchCommand) (*RedisBatchFuture, error) {
+	return p.runAsyncFuture("RunAsyncFuture", nil, cmds)
+}
+
+//
+// Push the command(s) onto the queue, the callback is called once every command has a reply
+//
+func (p *RedisBatchQueue) RunAsyncCallback(callback RedisBatchCallback, cmds ...*RedisBatchCommand) error {
+	if nil == callback {
+		return fmt.Errorf("[RedisBatchQueue][RunAsyncCallback] Nil callback!")
+	}
+	_, err := p.runAsyncFuture("RunAsyncCallback", callback, cmds)
+	return err
+}
+
+//
+// Push the command(s) onto the queue and block until every command has a reply, or the context is done
+//
+func (p *RedisBatchQueue) RunSync(ctx context.Context, cmds ...*RedisBatchCommand) error {
+	future, err := p.runAsyncFuture("RunSync", nil, cmds)
+	if nil != err {
+		return err
+	}
+	return future.Wait(ctx)
+}
+
+func (p *RedisBatchQueue) runAsyncFuture(method string, callback RedisBatchCallback, cmds RedisBatchCommands) (*RedisBatchFuture, error) {
+	if p.Cap() < 0 {
+		return nil, fmt.Errorf("[RedisBatchQueue][%v] Queue is closed!", method)
+	}
+
+	// Check every command before queueing any, otherwise the future would never resolve
+	for i, cmd := range cmds {
+		if nil == cmd {
+			return nil, fmt.Errorf("[RedisBatchQueue][%v][%v] Nil RedisBatchCommand!", method, i)
+		}
+	}
+
+	// Dropped commands resolve with ErrQueueIsFull or ErrQueueIsClosed, the future still resolves
+	future := makeRedisBatchFuture(cmds, callback)
+	switch err := p.RunAsync(cmds...); err {
+	case nil, ErrQueueIsFull, ErrQueueIsClosed:
+		return future, nil
+	default:
+		return nil, err
+	}
+}
@@ -0,0 +1,140 @@
+package dog_pool
+
+import "context"
+import "errors"
+import "time"
+import "github.com/alecthomas/log4go"
+import "github.com/RUNDSP/radix/redis"
+
+import "testing"
+import "github.com/orfjackal/gospec/src/gospec"
+
+func TestRedisBatchFutureSpecs(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping test in benchmark mode.")
+		return
+	}
+	r := gospec.NewRunner()
+	r.AddSpec(RedisBatchFutureSpecs)
+	gospec.MainGoTest(r, t)
+}
+
+func RedisBatchFutureSpecs(c gospec.Context) {
+
+	c.Specify("[RedisBatchFuture] Resolves once every command completes", func() {
+		cmds := RedisBatchCommands{MakeRedisBatchCommandGet("A"), MakeRedisBatchCommandGet("B")}
+
+		called := 0
+		future := makeRedisBatchFuture(cmds, func(callback_cmds RedisBatchCommands, err error) {
+			called++
+			c.Expect(len(callback_cmds), gospec.Equals, 2)
+			c.Expect(err, gospec.Equals, nil)
+		})
+		c.Expect(future.IsDone(), gospec.Equals, false)
+
+		cmds[0].reply = &redis.Reply{Type: redis.NilReply}
+		cmds[0].complete()
+		c.Expect(future.IsDone(), gospec.Equals, false)
+		c.Expect(called, gospec.Equals, 0)
+
+		cmds[1].reply = &redis.Reply{Type: redis.NilReply}
+		cmds[1].complete()
+		c.Expect(future.IsDone(), gospec.Equals, true)
+		c.Expect(future.Wait(context.Background()), gospec.Equals, nil)
+		c.Expect(called, gospec.Equals, 1)
+
+		// Completing again is a no-op
+		cmds[1].complete()
+		c.Expect(called, gospec.Equals, 1)
+	})
+
+	c.Specify("[RedisBatchFuture] Returns the first error, in the order of the commands", func() {
+		cmds := RedisBatchCommands{MakeRedisBatchCommandGet("A"), MakeRedisBatchCommandGet("B"), MakeRedisBatchCommandGet("C")}
+		future := makeRedisBatchFuture(cmds, nil)
+
+		cmds[2].reply = &redis.Reply{Type: redis.ErrorReply, Err: errors.New("C")}
+		cmds[2].complete()
+		cmds[1].reply = &redis.Reply{Type: redis.ErrorReply, Err: errors.New("B")}
+		cmds[1].complete()
+		cmds[0].reply = &redis.Reply{Type: redis.NilReply}
+		cmds[0].complete()
+
+		err := future.Wait(context.Background())
+		c.Expect(err, gospec.Satisfies, nil != err)
+		c.Expect(err.Error(), gospec.Equals, "B")
+		c.Expect(future.Err(), gospec.Equals, err)
+	})
+
+	c.Specify("[RedisBatchFuture][Wait] Returns early when the context is done", func() {
+		future := makeRedisBatchFuture(RedisBatchCommands{MakeRedisBatchCommandGet("A")}, nil)
+
+		ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond)
+		defer cancel()
+		c.Expect(future.Wait(ctx), gospec.Equals, context.DeadlineExceeded)
+		c.Expect(future.IsDone(), gospec.Equals, false)
+	})
+
+	c.Specify("[RedisBatchFuture] Empty batches resolve immediately", func() {
+		future := makeRedisBatchFuture(RedisBatchCommands{}, nil)
+		c.Expect(future.IsDone(), gospec.Equals, true)
+		c.Expect(future.Err(), gospec.Equals, nil)
+	})
+
+	c.Specify("[RedisBatchQueue][RunSync] Blocks until the replies are filled in", func() {
+		logger := MakeLog4goLogger(log4go.NewDefaultLogger(log4go.CRITICAL))
+		server, err := StartRedisServer(logger)
+		if nil != err {
+			panic(err)
+		}
+		defer server.Close()
+
+		ptr := &RedisBatchQueue{
+			Logger:           logger,
+			Connection:       server.Connection(),
+			QueueSize:        10,
+			WorkersSize:      2,
+			WorkersBatchSize: 5,
+		}
+
+		_, err = ptr.RunAsyncFuture(MakeRedisBatchCommandGet("A"))
+		c.Expect(err.Error(), gospec.Equals, "[RedisBatchQueue][RunAsyncFuture] Queue is closed!")
+
+		err = ptr.Open()
+		c.Expect(err, gospec.Equals, nil)
+		defer ptr.Close(context.Background())
+
+		err = ptr.RunSync(context.Background(), MakeRedisBatchCommandGet("A"), nil)
+		c.Expect(err.Error(), gospec.Equals, "[RedisBatchQueue][RunSync][1] Nil RedisBatchCommand!")
+
+		err = ptr.RunAsyncCallback(nil, MakeRedisBatchCommandGet("A"))
+		c.Expect(err.Error(), gospec.Equals, "[RedisBatchQueue][RunAsyncCallback] Nil callback!")
+
+		incr := MakeRedisBatchCommandHashIncrementBy("Hash", "Field A", 5)
+		err = ptr.RunSync(context.Background(), incr, MakeRedisBatchCommandHashIncrementBy("Hash", "Field B", 10))
+		c.Expect(err, gospec.Equals, nil)
+		c.Expect(incr.Reply(), gospec.Satisfies, nil != incr.Reply())
+
+		value, err := incr.ReplyToInt64Ptr()
+		c.Expect(err, gospec.Equals, nil)
+		c.Expect(*value, gospec.Equals, int64(5))
+
+		future, err := ptr.RunAsyncFuture(MakeRedisBatchCommandHashIncrementBy("Hash", "Field A", 1))
+		c.Expect(err, gospec.Equals, nil)
+		c.Expect(future.Wait(context.Background()), gospec.Equals, nil)
+
+		value, err = future.Commands[0].ReplyToInt64Ptr()
+		c.Expect(err, gospec.Equals, nil)
+		c.Expect(*value, gospec.Equals, int64(6))
+
+		done := make(chan error, 1)
+		err = ptr.RunAsyncCallback(func(cmds RedisBatchCommands, err error) { done <- err }, MakeRedisBatchCommandHashDelete("Hash", "Field A", "Field B"))
+		c.Expect(err, gospec.Equals, nil)
+
+		select {
+		case err = <-done:
+			c.Expect(err, gospec.Equals, nil)
+		case <-time.After(time.Second):
+			c.Expect("Callback was never called", gospec.Equals, "")
+		}
+	})
+}
@@ -1,31 +1,80 @@
 package dog_pool
 
+import "context"
 import "fmt"
+import "sync"
 import "time"
-import "github.com/alecthomas/log4go"
 
 type RedisBatchQueue struct {
-	Logger     *log4go.Logger   "Logger for logging updates, errors, etc"
-	Connection *RedisConnection "Connection to Redis"
+	Logger     Logger           "(optional) Logger for logging updates, errors, etc, nil is the NopLogger"
+	Connection *RedisConnection "Connection to Redis, cloned for every worker"
 
-	QueueSize        uint "How big should the queue of pending commands be?"
-	WorkersSize      uint "How many workers should we have?"
-	WorkersBatchSize uint "How many RedisBatchCommand's should the worker try to process at once? 1, 5, 10, 100, ..."
+	Pool     *RedisConnectionPool "(optional) Borrow the connections from the pool instead of cloning the Connection"
+	PoolMode RedisBatchPoolMode   "(optional) Borrow a connection per worker or per batch, defaults to RedisBatchPoolPerWorker"
 
-	queue   chan *RedisBatchCommand  "Input only queue"
-	workers []*redisBatchQueueWorker "Workers we are running in the background"
+	QueueSize        uint                 "How big should the queue of pending commands be?"
+	WorkersSize      uint                 "How many workers should we have?"
+	WorkersBatchSize uint                 "How many RedisBatchCommand's should the worker try to process at once? 1, 5, 10, 100, ..."
+	MaxLinger        time.Duration        "(optional) How long a worker waits to fill a batch before flushing it, 0 flushes what is already queued"
+	Namespace        string               "(optional) Prefix added to every key, see RedisNamespacedClient"
+	Name             string               "(optional) Name of the queue, used to label metrics; required with Metrics"
+	Metrics          *PrometheusCollector "(optional) Export the queue depth & batch sizes"
+	Lanes            []RedisBatchLane     "(optional) Named priority lanes next to the default lane, see RunAsyncLane"
+
+	Overflow        RedisBatchOverflowPolicy     "(optional) What RunAsync does when the queue is full, defaults to RedisBatchOverflowBlock"
+	OverflowTimeout time.Duration                "(optional) How long RedisBatchOverflowBlock waits for room before dropping, 0 waits forever"
+	OverflowSpill   func(cmd *RedisBatchCommand) "Receives the commands that don't fit, required by RedisBatchOverflowSpill"
+
+	Retry      *RedisBatchRetryPolicy "(optional) Retry commands that failed with a connection error, or idempotent commands that timed out"
+	DeadLetter RedisDeadLetterSink    "(optional) Receives the commands that exhausted their retries, i.e. a RedisDeadLetterFile"
+	FailFast   bool                   "(optional) Stop reading a batch's replies after a connection error, see ExecuteBatchFailFast"
+
+	CoalesceWindow time.Duration "(optional) How long to hold INCRBY, HINCRBY, INCRBYFLOAT & EXPIRE commands to merge them, 0 disables coalescing"
+
+	SpillLog            *RedisSpillLog "(optional) Write-ahead log for the commands spilled by RedisBatchOverflowSpill & the connection failures (unless DeadLetter is set)"
+	SpillReplayInterval time.Duration  "(optional) How often the SpillLog is replayed, defaults to 1s"
+
+	RateLimit *RedisBatchRateLimiter "(optional) Limits the commands & batches per second, adjustable while the queue is open"
+
+	MinWorkers        uint          "(optional) Fewest workers while autoscaling, defaults to WorkersSize"
+	MaxWorkers        uint          "(optional) Most workers while autoscaling, 0 disables autoscaling"
+	ScaleInterval     time.Duration "(optional) How often the queue depth & batch latency are checked, defaults to 1s"
+	ScaleUpLatency    time.Duration "(optional) Add a worker while commands are waiting & batches take longer than this on average"
+	WorkerIdleTimeout time.Duration "(optional) How long a worker above MinWorkers waits for a command before retiring, defaults to 30s"
+
+	queue      chan *RedisBatchCommand "Input only queue, the default lane"
+	lanes      []*redisBatchLane       "(optional) Named priority lanes"
+	closing    chan struct{}           "Closed by Close, aborts any RunAsync blocked on a full queue"
+	coalescer  *redisBatchCoalescer    "(optional) Holds the commands being coalesced"
+	replay     *redisSpillReplay       "(optional) Replays the SpillLog in the background"
+	scaler     *redisBatchQueueScaler  "(optional) Adds workers while the queue backs up"
+	mutex      sync.RWMutex            "Guards the queue, senders hold the read lock so Close never closes it under them"
+	open_mutex sync.Mutex              "Serializes Open & Close"
+
+	workers_mutex sync.Mutex               "Guards the workers, the autoscaler adds & retires them while the queue is open"
+	workers       []*redisBatchQueueWorker "Workers we are running in the background"
+	workers_wg    *sync.WaitGroup          "Done once every worker has drained the queue"
+
+	stats_mutex sync.Mutex
+	dropped     uint64 "Number of commands dropped by the overflow policy"
+	spilled     uint64 "Number of commands handed to OverflowSpill"
+	coalesced   uint64 "Number of commands merged into another"
 }
 
-// Capacity of the queue
+// Capacity of the queue, the default lane
 func (p *RedisBatchQueue) Cap() int {
+	p.mutex.RLock()
+	defer p.mutex.RUnlock()
 	if nil != p.queue {
 		return cap(p.queue)
 	}
 	return -1
 }
 
-// Length of the queue
+// Length of the queue, the default lane
 func (p *RedisBatchQueue) Len() int {
+	p.mutex.RLock()
+	defer p.mutex.RUnlock()
 	if nil != p.queue {
 		return len(p.queue)
 	}
@@ -34,18 +83,28 @@ func (p *RedisBatchQueue) Len() int {
 
 // Format as a string
 func (p *RedisBatchQueue) String() string {
-	return fmt.Sprintf("RedisBatchQueue { Connection=%v, QueueSize=%v, WorkersSize=%v, WorkersBatchSize=%v, Queue.Cap=%v, Queue.Len=%v }", p.Connection, p.QueueSize, p.WorkersSize, p.WorkersBatchSize, p.Cap(), p.Len())
+	stats := p.Stats()
+	return fmt.Sprintf("RedisBatchQueue { Connection=%v, QueueSize=%v, WorkersSize=%v, WorkersBatchSize=%v, Queue.Cap=%v, Queue.Len=%v, Dropped=%v, Spilled=%v }", p.Connection, p.QueueSize, p.WorkersSize, p.WorkersBatchSize, stats.Cap, stats.Len, stats.Dropped, stats.Spilled)
 }
 
 // Open the queue
 func (p *RedisBatchQueue) Open() error {
+	p.open_mutex.Lock()
+	defer p.open_mutex.Unlock()
+
 	switch {
-	case nil == p.Logger:
-		return fmt.Errorf("[RedisBatchQueue][Open] Nil Logger!")
 	case nil != p.queue:
 		return fmt.Errorf("[RedisBatchQueue][Open] Queue is already open!")
-	case nil == p.Connection:
+	case nil == p.Connection && nil == p.Pool:
 		return fmt.Errorf("[RedisBatchQueue][Open] Nil redis connection!")
+	case nil != p.Metrics && "" == p.Name:
+		return fmt.Errorf("[RedisBatchQueue][Open] Name is required to export the Metrics!")
+	case nil != p.Pool && p.Pool.IsClosed():
+		return fmt.Errorf("[RedisBatchQueue][Open] Pool is closed!")
+	case nil != p.Pool && len(redisPoolShards(p.Pool)) > p.Pool.Size:
+		return fmt.Errorf("[RedisBatchQueue][Open] Pool Size[%v] must be >= its %v shards!", p.Pool.Size, len(redisPoolShards(p.Pool)))
+	case p.PoolMode < RedisBatchPoolPerWorker || p.PoolMode > RedisBatchPoolPerBatch:
+		return fmt.Errorf("[RedisBatchQueue][Open] Invalid PoolMode: %v!", p.PoolMode)
 	case 0 == p.QueueSize:
 		return fmt.Errorf("[RedisBatchQueue][Open] QueueSize[%v] must be > 0!", p.QueueSize)
 	case 0 == p.WorkersSize:
@@ -54,74 +113,343 @@ func (p *RedisBatchQueue) Open() error {
 		return fmt.Errorf("[RedisBatchQueue][Open] WorkersBatchSize[%v] must be > 0!", p.WorkersBatchSize)
 	case p.QueueSize < p.WorkersSize:
 		return fmt.Errorf("[RedisBatchQueue][Open] QueueSize[%v] must be > WorkersSize[%v]!", p.QueueSize, p.WorkersSize)
+	case p.MaxWorkers > 0 && p.MaxWorkers < p.WorkersSize:
+		return fmt.Errorf("[RedisBatchQueue][Open] MaxWorkers[%v] must be >= WorkersSize[%v]!", p.MaxWorkers, p.WorkersSize)
+	case p.MinWorkers > p.WorkersSize:
+		return fmt.Errorf("[RedisBatchQueue][Open] MinWorkers[%v] must be <= WorkersSize[%v]!", p.MinWorkers, p.WorkersSize)
+	case p.Overflow < RedisBatchOverflowBlock || p.Overflow > RedisBatchOverflowSpill:
+		return fmt.Errorf("[RedisBatchQueue][Open] Invalid Overflow policy: %v!", p.Overflow)
+	case RedisBatchOverflowSpill == p.Overflow && nil == p.OverflowSpill && nil == p.SpillLog:
+		return fmt.Errorf("[RedisBatchQueue][Open] OverflowSpill or SpillLog is required by the %v policy!", p.Overflow)
+	}
+
+	lanes, err := makeRedisBatchLanes(p.Lanes)
+	if nil != err {
+		return err
 	}
 
-	p.queue = make(chan *RedisBatchCommand, p.QueueSize)
+	queue := make(chan *RedisBatchCommand, p.QueueSize)
+	closing := make(chan struct{})
+	workers := make([]*redisBatchQueueWorker, p.WorkersSize)
+	workers_wg := &sync.WaitGroup{}
+
+	var scaler *redisBatchQueueScaler
+	if p.MaxWorkers > 0 {
+		scaler = makeRedisBatchQueueScaler()
+	}
 
-	p.workers = make([]*redisBatchQueueWorker, p.WorkersSize)
-	for i := range p.workers {
-		ptr, err := makeRedisBatchQueueWorker(p.Logger, p.Connection.Clone(), p.WorkersBatchSize, p.queue)
+	for i := range workers {
+		ptr, err := p.makeWorker(queue, lanes, scaler, closing)
 		if nil != err {
-			// Close the queue and cleanup the pointers
-			p.Close()
+			// Stop the workers we have already started
+			close(queue)
+			for _, lane := range lanes {
+				close(lane.queue)
+			}
+			workers_wg.Wait()
+			closeRedisBatchQueueWorkers(workers[:i])
 
 			// Return the error
 			return err
 		}
 
 		// Save the handle to the workers
-		p.workers[i] = ptr
+		workers[i] = ptr
 
 		// Kick off the go routine:
-		go ptr.Run()
+		p.startWorker(ptr, workers_wg)
+	}
+
+	// Merge the commands before they are queued
+	var coalescer *redisBatchCoalescer
+	if p.CoalesceWindow > 0 {
+		coalescer = makeRedisBatchCoalescer()
+		go p.runCoalescer(coalescer, queue, closing)
+
+		for _, lane := range lanes {
+			lane.coalescer = makeRedisBatchCoalescer()
+			go p.runCoalescer(lane.coalescer, lane.queue, closing)
+		}
+	}
+
+	// Replay the spilled commands once Redis recovers
+	var replay *redisSpillReplay
+	if nil != p.SpillLog {
+		replay = p.startSpillReplay()
+	}
+
+	p.workers_mutex.Lock()
+	p.workers = workers
+	p.workers_wg = workers_wg
+	p.workers_mutex.Unlock()
+
+	// Scale the workers with the queue depth & batch latency
+	if nil != scaler {
+		go p.runScaler(scaler, queue, lanes, workers_wg, closing)
+	}
+
+	p.mutex.Lock()
+	p.queue = queue
+	p.lanes = lanes
+	p.closing = closing
+	p.coalescer = coalescer
+	p.replay = replay
+	p.scaler = scaler
+	p.mutex.Unlock()
+
+	if nil != p.Metrics {
+		p.Metrics.AddRedisBatchQueue(p.Name, p)
 	}
 
 	return nil
 }
 
-// Close the queue
-func (p *RedisBatchQueue) Close() error {
-	// Close the queue
-	if nil != p.queue {
-		close(p.queue)
+// Close the queue, the commands being coalesced are queued & the workers run the commands still in the queue before closing their connections.
+// Once the context is done the remaining queued commands are dropped, their futures resolve with ErrQueueIsClosed;
+// batches already sent to Redis still complete in the background.
+//
+// Returns:
+//
+//	0, nil         --> Every queued command was run (or the queue was not open)
+//	n, ctx.Err()   --> The context was done first, n queued commands were dropped
+func (p *RedisBatchQueue) Close(ctx context.Context) (int, error) {
+	p.open_mutex.Lock()
+	defer p.open_mutex.Unlock()
+
+	p.mutex.RLock()
+	lanes, closing, replay, scaler := p.allLanes(), p.closing, p.replay, p.scaler
+	p.mutex.RUnlock()
+
+	// Not open, nothing to do
+	if 0 == len(lanes) {
+		return 0, nil
 	}
-	p.queue = nil
 
-	for _, worker := range p.workers {
-		if nil == worker {
-			continue
+	// Stop adding workers
+	if nil != scaler {
+		close(scaler.stop)
+		<-scaler.done
+	}
+
+	// Stop replaying the SpillLog, a batch already sent to Redis completes in the background
+	if nil != replay {
+		close(replay.stop)
+		select {
+		case <-replay.done:
+		case <-ctx.Done():
 		}
-		if nil == worker.Connection {
-			continue
+	}
+
+	// Queue the commands being coalesced, unless the context is done first
+	for _, lane := range lanes {
+		if nil != lane.coalescer {
+			close(lane.coalescer.stop)
+			select {
+			case <-lane.coalescer.done:
+			case <-ctx.Done():
+			}
 		}
-		if nil == worker.Connection.client {
-			continue
+	}
+
+	// Abort any RunAsync blocked on a full queue, they release the read lock
+	if nil != closing {
+		close(closing)
+	}
+	for _, lane := range lanes {
+		if nil != lane.coalescer {
+			<-lane.coalescer.done
 		}
+	}
 
-		// Let any pending tasks complete before closing the Redis Connection,
-		// This will prevent some unsavory crashes in background go routines.
-		time.Sleep(time.Millisecond * 15)
+	p.mutex.Lock()
+	p.queue = nil
+	p.lanes = nil
+	p.closing = nil
+	p.coalescer = nil
+	p.replay = nil
+	p.scaler = nil
+	p.mutex.Unlock()
 
-		worker.Connection.Close()
+	// Stop exporting the queue's gauges
+	if nil != p.Metrics {
+		p.Metrics.RemoveRedisBatchQueue(p.Name, p)
 	}
 
+	// The workers retire on their own from here on
+	p.workers_mutex.Lock()
+	workers, workers_wg := p.workers, p.workers_wg
 	p.workers = nil
+	p.workers_wg = nil
+	p.workers_mutex.Unlock()
 
-	return nil
+	// No-one can send on the lanes now, let the workers drain them
+	for _, lane := range lanes {
+		close(lane.queue)
+	}
+
+	drained := make(chan struct{})
+	go func() {
+		if nil != workers_wg {
+			workers_wg.Wait()
+		}
+		closeRedisBatchQueueWorkers(workers)
+		close(drained)
+	}()
+
+	select {
+	case <-drained:
+		return 0, nil
+	case <-ctx.Done():
+		// Compete with the workers for the remaining commands
+		dropped := 0
+		for _, lane := range lanes {
+			for cmd := range lane.queue {
+				p.drop(cmd, ErrQueueIsClosed)
+				dropped++
+			}
+		}
+		logAt(p.Logger, LogWarning, "[RedisBatchQueue][Close] Dropped %v queued commands: err=%v", dropped, ctx.Err())
+		return dropped, ctx.Err()
+	}
+}
+
+// Make a worker draining the queue & lanes, on a connection of its own or borrowed from the Pool
+func (p *RedisBatchQueue) makeWorker(queue chan *RedisBatchCommand, lanes []*redisBatchLane, scaler *redisBatchQueueScaler, closing <-chan struct{}) (*redisBatchQueueWorker, error) {
+	var shards []string
+	if nil != p.Pool {
+		shards = redisPoolShards(p.Pool)
+	}
+
+	var ptr *redisBatchQueueWorker
+	var err error
+	switch {
+	case nil == p.Pool:
+		ptr, err = makeRedisBatchQueueWorker(p.Logger, p.Connection.Clone(), p.WorkersBatchSize, queue)
+	case RedisBatchPoolPerBatch == p.PoolMode:
+		ptr, err = makeRedisBatchQueuePoolWorker(p.Logger, p.Pool, p.WorkersBatchSize, queue)
+	case len(shards) > 1:
+		ptr, err = p.makeShardedPoolWorker(queue, shards)
+	default:
+		ptr, err = p.makePoolWorker(queue)
+	}
+	if nil != err {
+		return nil, err
+	}
+
+	// Route the commands to the shards of the pool
+	if len(shards) > 1 {
+		ptr.Shards = shards
+	}
+
+	// Namespace the keys of every command
+	ptr.Namespace = p.Namespace
+	if len(p.Namespace) > 0 && nil != ptr.Connection {
+		ptr.Client = MakeRedisNamespacedClient(p.Namespace, ptr.Connection)
+	}
+
+	// Record the batch sizes
+	ptr.Metrics = p.Metrics
+	ptr.QueueName = p.Name
+
+	// Wait for bigger batches
+	ptr.MaxLinger = p.MaxLinger
+
+	// Drain the default & named lanes with weighted fairness
+	if len(lanes) > 0 {
+		default_lane := &redisBatchLane{name: RedisBatchDefaultLane, weight: redis_batch_default_lane_weight, queue: queue}
+		ptr.Lanes = makeRedisBatchLaneReader(append([]*redisBatchLane{default_lane}, lanes...))
+	}
+
+	// Throttle the batches
+	ptr.RateLimit = p.RateLimit
+
+	// Retry & dead-letter failed commands
+	ptr.FailFast = p.FailFast
+	ptr.Retry = p.Retry
+	ptr.Closing = closing
+	ptr.DeadLetter = p.DeadLetter
+	if nil == p.DeadLetter && nil != p.SpillLog {
+		ptr.DeadLetter = p.SpillLog
+	}
+
+	// Report the batch latency & retire when idle
+	if nil != scaler {
+		ptr.BatchTimer = scaler.observe
+		ptr.IdleTimeout = p.WorkerIdleTimeout
+		if ptr.IdleTimeout <= 0 {
+			ptr.IdleTimeout = redis_batch_worker_idle_timeout
+		}
+	}
+
+	return ptr, nil
+}
+
+// Run the worker in the background, a retired worker closes (or returns) its own connection
+func (p *RedisBatchQueue) startWorker(ptr *redisBatchQueueWorker, workers_wg *sync.WaitGroup) {
+	retired := false
+	if ptr.IdleTimeout > 0 {
+		ptr.Retire = func() bool {
+			retired = p.retireWorker(ptr)
+			return retired
+		}
+	}
+
+	workers_wg.Add(1)
+	go func() {
+		defer workers_wg.Done()
+		ptr.Run()
+
+		if retired {
+			ptr.close()
+		}
+	}()
 }
 
-// Push the command(s) onto the queue
+// Close the workers' connections (or return them to the Pool), once they have stopped running
+func closeRedisBatchQueueWorkers(workers []*redisBatchQueueWorker) {
+	for _, worker := range workers {
+		if nil != worker {
+			worker.close()
+		}
+	}
+}
+
+// Push the command(s) onto the queue, applying the Overflow policy when it is full.
+// Returns ErrQueueIsFull if any command was dropped, the remaining commands are still queued.
+// Coalesced commands are queued at the end of the CoalesceWindow, their futures report any drops.
 func (p *RedisBatchQueue) RunAsync(cmds ...*RedisBatchCommand) error {
-	if nil == p.queue {
-		return fmt.Errorf("[RedisBatchQueue][RunAsync] Queue is closed!")
+	return p.runAsync("RunAsync", RedisBatchDefaultLane, cmds)
+}
+
+// Push the command(s) onto the lane, see RunAsync
+func (p *RedisBatchQueue) runAsync(method, lane string, cmds []*RedisBatchCommand) error {
+	// Close waits for us to finish before closing the queue
+	p.mutex.RLock()
+	defer p.mutex.RUnlock()
+
+	queue, coalescer := p.lane(lane)
+	closing := p.closing
+	switch {
+	case nil == p.queue:
+		return fmt.Errorf("[RedisBatchQueue][%v] Queue is closed!", method)
+	case nil == queue:
+		return fmt.Errorf("[RedisBatchQueue][%v] Unknown lane: %q!", method, lane)
 	}
 
+	// The OverflowTimeout applies to the whole call, not to each command
+	deadline := time.Now().Add(p.OverflowTimeout)
+
+	var err error
 	for i, cmd := range cmds {
 		if nil != cmd {
-			p.queue <- cmd
+			if p.coalesce(coalescer, cmd) {
+				// Held until the end of the CoalesceWindow
+			} else if push_err := p.push(queue, closing, cmd, deadline); nil != push_err {
+				err = push_err
+			}
 		} else {
-			return fmt.Errorf("[RedisBatchQueue][RunAsync][%v] Nil RedisBatchCommand!", i)
+			return fmt.Errorf("[RedisBatchQueue][%v][%v] Nil RedisBatchCommand!", method, i)
 		}
 	}
-	return nil
+	return err
 }
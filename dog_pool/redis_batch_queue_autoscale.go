@@ -0,0 +1,173 @@
+//
+// Autoscaling the workers of the RedisBatchQueue
+//
+// Every ScaleInterval the queue starts a worker (on a connection of its own) for every full batch waiting in the lanes,
+// plus one more while batches take longer than ScaleUpLatency, up to MaxWorkers.
+// A worker above MinWorkers that waits WorkerIdleTimeout without a command retires & closes its connection.
+//
+// Usage:
+//   queue := &RedisBatchQueue{
+//     ...
+//     WorkersSize:       4,
+//     MaxWorkers:        64,
+//     ScaleUpLatency:    50 * time.Millisecond,
+//     WorkerIdleTimeout: time.Minute,
+//   }
+//
+
+package dog_pool
+
+import "sync"
+import "time"
+
+const redis_batch_scale_interval = time.Second
+const redis_batch_worker_idle_timeout = 30 * time.Second
+
+//
+// Batch latency since the last check
+//
+type redisBatchQueueScaler struct {
+	mutex   sync.Mutex
+	batches int
+	elapsed time.Duration
+
+	stop chan struct{} "Closed by Close to stop scaling"
+	done chan struct{} "Closed once scaling has stopped"
+}
+
+func makeRedisBatchQueueScaler() *redisBatchQueueScaler {
+	return &redisBatchQueueScaler{stop: make(chan struct{}), done: make(chan struct{})}
+}
+
+//
+// Record how long a batch took to run
+//
+func (p *redisBatchQueueScaler) observe(elapsed time.Duration) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	p.batches++
+	p.elapsed += elapsed
+}
+
+//
+// Average batch latency since the last call, 0 if no batch ran
+//
+func (p *redisBatchQueueScaler) takeAverage() time.Duration {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	average := time.Duration(0)
+	if p.batches > 0 {
+		average = p.elapsed / time.Duration(p.batches)
+	}
+	p.batches = 0
+	p.elapsed = 0
+	return average
+}
+
+//
+// Number of workers running
+//
+func (p *RedisBatchQueue) Workers() int {
+	p.workers_mutex.Lock()
+	defer p.workers_mutex.Unlock()
+	return len(p.workers)
+}
+
+//
+// Check the queue depth & batch latency every ScaleInterval, until Close stops it
+//
+func (p *RedisBatchQueue) runScaler(scaler *redisBatchQueueScaler, queue chan *RedisBatchCommand, lanes []*redisBatchLane, workers_wg *sync.WaitGroup, closing <-chan struct{}) {
+	defer close(scaler.done)
+
+	interval := p.ScaleInterval
+	if interval <= 0 {
+		interval = redis_batch_scale_interval
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-scaler.stop:
+			return
+		case <-ticker.C:
+			depth := len(queue)
+			for _, lane := range lanes {
+				depth += len(lane.queue)
+			}
+			p.scaleWorkers(scaler, queue, lanes, workers_wg, closing, depth)
+		}
+	}
+}
+
+//
+// Start the workers needed to keep up with the commands waiting
+//
+func (p *RedisBatchQueue) scaleWorkers(scaler *redisBatchQueueScaler, queue chan *RedisBatchCommand, lanes []*redisBatchLane, workers_wg *sync.WaitGroup, closing <-chan struct{}, depth int) {
+	latency := scaler.takeAverage()
+
+	p.workers_mutex.Lock()
+	defer p.workers_mutex.Unlock()
+
+	// Closed, the workers are draining the queue
+	if nil == p.workers {
+		return
+	}
+
+	// A worker for every full batch waiting, and one more while the batches are slow
+	running := len(p.workers)
+	batch_size := int(p.WorkersBatchSize)
+	target := (depth + batch_size - 1) / batch_size
+	if p.ScaleUpLatency > 0 && latency > p.ScaleUpLatency && depth > 0 && target <= running {
+		target = running + 1
+	}
+	if target > int(p.MaxWorkers) {
+		target = int(p.MaxWorkers)
+	}
+
+	if target > running {
+		logAt(p.Logger, LogInfo, "[RedisBatchQueue][Autoscale] Scaling from %v to %v workers: depth=%v, latency=%v", running, target, depth, latency)
+	}
+
+	for i := running; i < target; i++ {
+		ptr, err := p.makeWorker(queue, lanes, scaler, closing)
+		if nil != err {
+			logAt(p.Logger, LogError, "[RedisBatchQueue][Autoscale] Error starting a worker: err=%v", err)
+			return
+		}
+
+		p.workers = append(p.workers, ptr)
+		p.startWorker(ptr, workers_wg)
+	}
+}
+
+//
+// Retire the idle worker, unless we are down to MinWorkers or the queue is closing
+//
+// Returns:
+//   true  --> Retired, the worker exits & closes its connection
+//   false --> Keep running
+//
+func (p *RedisBatchQueue) retireWorker(ptr *redisBatchQueueWorker) bool {
+	p.workers_mutex.Lock()
+	defer p.workers_mutex.Unlock()
+
+	min_workers := p.MinWorkers
+	if 0 == min_workers {
+		min_workers = p.WorkersSize
+	}
+
+	if uint(len(p.workers)) <= min_workers {
+		return false
+	}
+
+	for i, worker := range p.workers {
+		if ptr == worker {
+			p.workers = append(p.workers[:i:i], p.workers[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,159 @@
+package dog_pool
+
+import "context"
+import "sync"
+import "time"
+import "github.com/alecthomas/log4go"
+
+import "testing"
+import "github.com/orfjackal/gospec/src/gospec"
+
+func TestRedisBatchQueueAutoscaleSpecs(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping test in benchmark mode.")
+		return
+	}
+	r := gospec.NewRunner()
+	r.AddSpec(RedisBatchQueueAutoscaleSpecs)
+	gospec.MainGoTest(r, t)
+}
+
+func RedisBatchQueueAutoscaleSpecs(c gospec.Context) {
+
+	c.Specify("[RedisBatchQueue][Open] Validates the Min/MaxWorkers", func() {
+		ptr := &RedisBatchQueue{
+			Connection:       &RedisConnection{},
+			QueueSize:        10,
+			WorkersSize:      4,
+			WorkersBatchSize: 1,
+			MaxWorkers:       2,
+		}
+		c.Expect(ptr.Open().Error(), gospec.Equals, "[RedisBatchQueue][Open] MaxWorkers[2] must be >= WorkersSize[4]!")
+
+		ptr.MaxWorkers = 8
+		ptr.MinWorkers = 5
+		c.Expect(ptr.Open().Error(), gospec.Equals, "[RedisBatchQueue][Open] MinWorkers[5] must be <= WorkersSize[4]!")
+	})
+
+	c.Specify("[redisBatchQueueScaler][takeAverage] Averages & resets the batch latency", func() {
+		scaler := makeRedisBatchQueueScaler()
+		c.Expect(scaler.takeAverage(), gospec.Equals, time.Duration(0))
+
+		scaler.observe(10 * time.Millisecond)
+		scaler.observe(30 * time.Millisecond)
+		c.Expect(scaler.takeAverage(), gospec.Equals, 20*time.Millisecond)
+		c.Expect(scaler.takeAverage(), gospec.Equals, time.Duration(0))
+	})
+
+	c.Specify("[RedisBatchQueue][scaleWorkers] Starts a worker per full batch waiting, up to MaxWorkers", func() {
+		queue := make(chan *RedisBatchCommand, 100)
+		workers_wg := &sync.WaitGroup{}
+		scaler := makeRedisBatchQueueScaler()
+		ptr := &RedisBatchQueue{
+			Connection:       &RedisConnection{Url: "127.0.0.1:6991"},
+			WorkersSize:      1,
+			WorkersBatchSize: 10,
+			MaxWorkers:       4,
+			ScaleUpLatency:   10 * time.Millisecond,
+			workers:          []*redisBatchQueueWorker{},
+		}
+
+		ptr.scaleWorkers(scaler, queue, nil, workers_wg, nil, 0)
+		c.Expect(ptr.Workers(), gospec.Equals, 0)
+
+		ptr.scaleWorkers(scaler, queue, nil, workers_wg, nil, 15)
+		c.Expect(ptr.Workers(), gospec.Equals, 2)
+
+		// Slow batches add one more
+		scaler.observe(time.Second)
+		ptr.scaleWorkers(scaler, queue, nil, workers_wg, nil, 15)
+		c.Expect(ptr.Workers(), gospec.Equals, 3)
+
+		// Capped at MaxWorkers
+		ptr.scaleWorkers(scaler, queue, nil, workers_wg, nil, 1000)
+		c.Expect(ptr.Workers(), gospec.Equals, 4)
+		c.Expect(ptr.Stats().Workers, gospec.Equals, 4)
+
+		close(queue)
+		workers_wg.Wait()
+	})
+
+	c.Specify("[RedisBatchQueue][retireWorker] Retires the workers above MinWorkers", func() {
+		a, b, c_ := &redisBatchQueueWorker{}, &redisBatchQueueWorker{}, &redisBatchQueueWorker{}
+		ptr := &RedisBatchQueue{WorkersSize: 2, MinWorkers: 1, workers: []*redisBatchQueueWorker{a, b, c_}}
+		c.Expect(ptr.retireWorker(b), gospec.Equals, true)
+		c.Expect(ptr.retireWorker(b), gospec.Equals, false)
+		c.Expect(ptr.retireWorker(a), gospec.Equals, true)
+		c.Expect(ptr.retireWorker(c_), gospec.Equals, false)
+		c.Expect(ptr.workers, gospec.Equals, []*redisBatchQueueWorker{c_})
+	})
+
+	c.Specify("[redisBatchQueueWorker][mustPopCommand] Retires once idle", func() {
+		queue := make(chan *RedisBatchCommand, 1)
+		ptr, err := makeRedisBatchQueueWorker(nil, &RedisConnection{}, 10, queue)
+		c.Expect(err, gospec.Equals, nil)
+
+		asked := 0
+		ptr.IdleTimeout = time.Millisecond
+		ptr.Retire = func() bool {
+			asked++
+			return asked > 1
+		}
+
+		cmd, ok := ptr.mustPopCommand()
+		c.Expect(cmd, gospec.Satisfies, nil == cmd)
+		c.Expect(ok, gospec.Equals, false)
+		c.Expect(asked, gospec.Equals, 2)
+
+		queue <- MakeRedisBatchCommandGet("A")
+		cmd, ok = ptr.mustPopCommand()
+		c.Expect(cmd, gospec.Satisfies, nil != cmd)
+		c.Expect(ok, gospec.Equals, true)
+	})
+
+	c.Specify("[RedisBatchQueue] Scales up with the queue depth, and back down when idle", func() {
+		logger := MakeLog4goLogger(log4go.NewDefaultLogger(log4go.CRITICAL))
+		server, err := StartRedisServer(logger)
+		if nil != err {
+			panic(err)
+		}
+		defer server.Close()
+
+		ptr := &RedisBatchQueue{
+			Logger:            logger,
+			Connection:        server.Connection(),
+			QueueSize:         1000,
+			WorkersSize:       1,
+			WorkersBatchSize:  10,
+			MaxWorkers:        4,
+			ScaleInterval:     time.Millisecond,
+			WorkerIdleTimeout: 10 * time.Millisecond,
+		}
+		c.Expect(ptr.Open(), gospec.Equals, nil)
+		defer ptr.Close(context.Background())
+
+		cmds := RedisBatchCommands{}
+		for i := 0; i < 1000; i++ {
+			cmds = append(cmds, MakeRedisBatchCommandHashIncrementBy("Hash", "Field", 1))
+		}
+
+		scaled := 0
+		future, err := ptr.RunAsyncFuture(cmds...)
+		c.Expect(err, gospec.Equals, nil)
+		for !future.IsDone() {
+			if workers := ptr.Workers(); workers > scaled {
+				scaled = workers
+			}
+			time.Sleep(time.Millisecond)
+		}
+		c.Expect(future.Err(), gospec.Equals, nil)
+		c.Expect(scaled > 1, gospec.Equals, true)
+
+		// Back down to the WorkersSize
+		deadline := time.Now().Add(time.Second)
+		for ptr.Workers() > 1 && time.Now().Before(deadline) {
+			time.Sleep(time.Millisecond)
+		}
+		c.Expect(ptr.Workers(), gospec.Equals, 1)
+	})
+}
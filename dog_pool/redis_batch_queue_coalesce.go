@@ -0,0 +1,365 @@
+//
+// Coalescing of counters & idempotent writes in the RedisBatchQueue
+//
+// Commands on the same target are held for the CoalesceWindow and merged before they are queued:
+//   INCRBY, HINCRBY & INCRBYFLOAT --> The deltas are summed into a single command
+//   EXPIRE                        --> The last command wins
+//
+// SETBIT isn't coalesced, it replies with the previous bit, which differs for every command on the bit.
+//
+// Every merged command resolves with the reply of the command that ran, i.e. the final counter value.
+// Held commands are queued at the end of the window. Any other command on a held key (i.e. GET, PERSIST, DEL) is held
+// behind them, so it still runs after them; commands on other keys are queued at once.
+//
+// Usage:
+//   queue := &RedisBatchQueue{
+//     ...
+//     CoalesceWindow: 100 * time.Millisecond,
+//   }
+//
+//   // 1x HINCRBY stats:clicks <url> 3
+//   queue.RunAsync(
+//     MakeRedisBatchCommandHashIncrementBy("stats:clicks", url, 1),
+//     MakeRedisBatchCommandHashIncrementBy("stats:clicks", url, 2),
+//   )
+//
+
+package dog_pool
+
+import "math"
+import "strconv"
+import "strings"
+import "sync"
+import "time"
+
+//
+// Holds the coalescable commands until the end of the window
+//
+type redisBatchCoalescer struct {
+	mutex   sync.Mutex
+	pending map[string]*RedisBatchCommand "Merged command still open to merges, by target"
+	open    map[string][]string           "Targets still open to merges, by key"
+	order   RedisBatchCommands            "Held commands in the order they were first seen"
+	stopped bool                          "Set by the final flush, later commands are queued as-is"
+
+	held     redisCoalescerKeys "Keys of the held commands"
+	inflight redisCoalescerKeys "Keys of the flushed commands not yet pushed onto the queue"
+
+	stop chan struct{} "Closed by Close to request the final flush"
+	done chan struct{} "Closed once the final flush is queued"
+}
+
+//
+// Number of commands touching each key; commands with unknown keys touch every key
+//
+type redisCoalescerKeys struct {
+	keys  map[string]int
+	all   int "Commands with unknown keys"
+	total int "Every command"
+}
+
+func makeRedisBatchCoalescer() *redisBatchCoalescer {
+	return &redisBatchCoalescer{
+		pending: map[string]*RedisBatchCommand{},
+		open:    map[string][]string{},
+		stop:    make(chan struct{}),
+		done:    make(chan struct{}),
+	}
+}
+
+//
+// Hold the command until the end of the window, merging it with the open command on the same target.
+//
+// Any other command touching a held key (or a key still being pushed from the last window) is held too, in order,
+// so it runs after them; the commands after it start new merges.
+//
+// Returns:
+//   false, false --> Not coalescable & touches no held key (or the coalescer is stopped), queue the command as-is
+//   true,  false --> Held
+//   true,  true  --> Merged into the open command on the target
+//
+func (p *redisBatchCoalescer) add(cmd *RedisBatchCommand) (bool, bool) {
+	target := redisCoalesceTarget(cmd)
+
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	if p.stopped {
+		return false, false
+	}
+
+	if "" == target {
+		keys, all := redisCommandKeys(cmd)
+		if !p.held.touches(keys, all) && !p.inflight.touches(keys, all) {
+			return false, false
+		}
+
+		// Run after the held commands, & close their merges so later commands run after this one
+		if all {
+			p.pending = map[string]*RedisBatchCommand{}
+			p.open = map[string][]string{}
+		}
+		for _, key := range keys {
+			for _, open := range p.open[key] {
+				delete(p.pending, open)
+			}
+			delete(p.open, key)
+		}
+		p.hold(cmd, keys, all)
+		return true, false
+	}
+
+	if merged, ok := p.pending[target]; ok && redisCoalesce(merged, cmd) {
+		return true, true
+	}
+
+	// First command on the target, or it can't be merged (the sum overflows) & starts a new merge
+	key := string(cmd.args[0])
+	merged := MakeRedisBatchCommand(cmd.cmd)
+	for _, arg := range cmd.args {
+		// Copy the arguments, the merged command rewrites the last one
+		merged.WriteArg(arg)
+	}
+	merged.coalesced = RedisBatchCommands{cmd}
+
+	if _, ok := p.pending[target]; !ok {
+		p.open[key] = append(p.open[key], target)
+	}
+	p.pending[target] = merged
+	p.hold(merged, []string{key}, false)
+	return true, false
+}
+
+// Append the command to the held commands, the caller holds the lock
+func (p *redisBatchCoalescer) hold(cmd *RedisBatchCommand, keys []string, all bool) {
+	p.order = append(p.order, cmd)
+	p.held.add(keys, all, 1)
+}
+
+//
+// Take the held commands, in the order they were first seen; their keys stay in flight until pushed, see pushed()
+//
+func (p *redisBatchCoalescer) flush(final bool) RedisBatchCommands {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	output := make(RedisBatchCommands, len(p.order))
+	for i, merged := range p.order {
+		// Nothing was merged, queue the original command
+		if 1 == len(merged.coalesced) {
+			merged = merged.coalesced[0]
+		}
+		output[i] = merged
+	}
+
+	p.inflight.merge(p.held)
+
+	p.pending = map[string]*RedisBatchCommand{}
+	p.open = map[string][]string{}
+	p.order = nil
+	p.held = redisCoalescerKeys{}
+	p.stopped = p.stopped || final
+	return output
+}
+
+//
+// The flushed command is on the queue, commands touching its keys can be queued as-is again
+//
+func (p *redisBatchCoalescer) pushed(cmd *RedisBatchCommand) {
+	keys, all := redisCommandKeys(cmd)
+
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	p.inflight.add(keys, all, -1)
+}
+
+//
+// Flush the pending commands onto the queue at the end of every window, until Close requests the final flush
+//
+func (p *RedisBatchQueue) runCoalescer(coalescer *redisBatchCoalescer, queue chan *RedisBatchCommand, closing <-chan struct{}) {
+	defer close(coalescer.done)
+
+	ticker := time.NewTicker(p.CoalesceWindow)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			p.pushCoalesced(coalescer, queue, closing, coalescer.flush(false))
+		case <-coalescer.stop:
+			p.pushCoalesced(coalescer, queue, closing, coalescer.flush(true))
+			return
+		}
+	}
+}
+
+//
+// Push the merged commands onto the queue, applying the Overflow policy; dropped commands resolve with the error
+//
+func (p *RedisBatchQueue) pushCoalesced(coalescer *redisBatchCoalescer, queue chan *RedisBatchCommand, closing <-chan struct{}, cmds RedisBatchCommands) {
+	deadline := time.Now().Add(p.OverflowTimeout)
+	for _, cmd := range cmds {
+		p.push(queue, closing, cmd, deadline)
+		coalescer.pushed(cmd)
+	}
+}
+
+//
+// Hold the command in the coalescer (if any) & count the commands merged into another
+//
+// Returns:
+//   true  --> Held until the end of the CoalesceWindow
+//   false --> Queue the command as-is
+//
+func (p *RedisBatchQueue) coalesce(coalescer *redisBatchCoalescer, cmd *RedisBatchCommand) bool {
+	if nil == coalescer {
+		return false
+	}
+
+	held, merged := coalescer.add(cmd)
+	if merged {
+		p.stats_mutex.Lock()
+		p.coalesced++
+		p.stats_mutex.Unlock()
+	}
+	return held
+}
+
+//
+// Target of a coalescable command, i.e. "HINCRBY\x00<KEY>\x00<FIELD>", or "" if the command can't be coalesced
+//
+func redisCoalesceTarget(cmd *RedisBatchCommand) string {
+	switch name := strings.ToUpper(cmd.cmd); {
+	case 2 == len(cmd.args) && (cmd_incrby == name || cmd_incrbyfloat == name || cmd_expire == name):
+		// <CMD> <KEY> <VALUE>
+		return name + "\x00" + string(cmd.args[0])
+	case 3 == len(cmd.args) && cmd_hincrby == name:
+		// <CMD> <KEY> <FIELD> <VALUE>
+		return name + "\x00" + string(cmd.args[0]) + "\x00" + string(cmd.args[1])
+	}
+	return ""
+}
+
+//
+// Merge the command's value into the last argument of the merged command
+//
+// Returns:
+//   true  --> Merged
+//   false --> The values can't be merged (not numbers, inf/nan, or the sum overflows), queue the command as-is
+//
+func redisCoalesce(merged, cmd *RedisBatchCommand) bool {
+	last := len(cmd.args) - 1
+
+	switch strings.ToUpper(cmd.cmd) {
+	case cmd_incrby, cmd_hincrby:
+		a, err_a := strconv.ParseInt(string(merged.args[last]), 10, 64)
+		b, err_b := strconv.ParseInt(string(cmd.args[last]), 10, 64)
+		switch {
+		case nil != err_a || nil != err_b:
+			return false
+		case b > 0 && a > math.MaxInt64-b:
+			return false
+		case b < 0 && a < math.MinInt64-b:
+			return false
+		}
+		merged.args[last] = []byte(strconv.FormatInt(a+b, 10))
+
+	case cmd_incrbyfloat:
+		a, err_a := strconv.ParseFloat(string(merged.args[last]), 64)
+		b, err_b := strconv.ParseFloat(string(cmd.args[last]), 64)
+		switch {
+		case nil != err_a || nil != err_b:
+			return false
+		case !redisFiniteFloat(a) || !redisFiniteFloat(b) || !redisFiniteFloat(a+b):
+			// Redis rejects inf & nan, keep the command as-is so it fails on its own
+			return false
+		}
+		merged.args[last] = []byte(strconv.FormatFloat(a+b, 'f', -1, 64))
+
+	default:
+		// EXPIRE, the last command wins
+		merged.args[last] = cmd.args[last]
+	}
+
+	merged.coalesced = append(merged.coalesced, cmd)
+	return true
+}
+
+//
+// Is the float neither inf nor nan?
+//
+func redisFiniteFloat(value float64) bool {
+	return !math.IsInf(value, 0) && !math.IsNaN(value)
+}
+
+//
+// Keys of the command, using the key positions of RedisNamespaceCommands
+//
+// Returns:
+//   keys, false --> The command's keys, empty if it has none (i.e. PING)
+//   nil,  true  --> Unknown command, it may touch any key
+//
+func redisCommandKeys(cmd *RedisBatchCommand) ([]string, bool) {
+	name := strings.ToUpper(cmd.cmd)
+	if "" != redisCoalesceTarget(cmd) {
+		return []string{string(cmd.args[0])}, false
+	}
+
+	positions, ok := RedisNamespaceCommands[name]
+	if !ok {
+		return nil, true
+	}
+
+	var keys []string
+	for _, i := range positions(cmd.interfaceArgs()) {
+		if i >= 0 && i < len(cmd.args) {
+			keys = append(keys, string(cmd.args[i]))
+		}
+	}
+	return keys, false
+}
+
+// Count (or uncount) a command touching the keys
+func (p *redisCoalescerKeys) add(keys []string, all bool, count int) {
+	if nil == p.keys {
+		p.keys = map[string]int{}
+	}
+	for _, key := range keys {
+		if p.keys[key] += count; p.keys[key] <= 0 {
+			delete(p.keys, key)
+		}
+	}
+	if all {
+		p.all += count
+	}
+	p.total += count
+}
+
+// Count the other commands too
+func (p *redisCoalescerKeys) merge(other redisCoalescerKeys) {
+	if nil == p.keys {
+		p.keys = map[string]int{}
+	}
+	for key, count := range other.keys {
+		p.keys[key] += count
+	}
+	p.all += other.all
+	p.total += other.total
+}
+
+// Does a command touching the keys have to run after the counted commands?
+func (p *redisCoalescerKeys) touches(keys []string, all bool) bool {
+	switch {
+	case 0 == p.total:
+		return false
+	case all || p.all > 0:
+		return true
+	}
+	for _, key := range keys {
+		if p.keys[key] > 0 {
+			return true
+		}
+	}
+	return false
+}
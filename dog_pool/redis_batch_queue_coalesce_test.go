@@ -0,0 +1,259 @@
+package dog_pool
+
+import "context"
+import "math"
+import "time"
+import "github.com/alecthomas/log4go"
+import "github.com/RUNDSP/radix/redis"
+
+import "testing"
+import "github.com/orfjackal/gospec/src/gospec"
+
+func TestRedisBatchQueueCoalesceSpecs(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping test in benchmark mode.")
+		return
+	}
+	r := gospec.NewRunner()
+	r.AddSpec(RedisBatchQueueCoalesceSpecs)
+	gospec.MainGoTest(r, t)
+}
+
+func RedisBatchQueueCoalesceSpecs(c gospec.Context) {
+
+	c.Specify("[redisCoalesceTarget] Only counters & EXPIRE are coalescable", func() {
+		c.Expect(redisCoalesceTarget(MakeRedisBatchCommandIncrementBy("Key", 1)), gospec.Equals, "INCRBY\x00Key")
+		c.Expect(redisCoalesceTarget(MakeRedisBatchCommandHashIncrementBy("Key", "Field", 1)), gospec.Equals, "HINCRBY\x00Key\x00Field")
+		c.Expect(redisCoalesceTarget(MakeRedisBatchCommandSetBit("Key", 7, true)), gospec.Equals, "")
+		c.Expect(redisCoalesceTarget(MakeRedisBatchCommandExpireIn("Key", time.Minute)), gospec.Equals, "EXPIRE\x00Key")
+		c.Expect(redisCoalesceTarget(MakeRedisBatchCommandSet("Key", []byte("Value"))), gospec.Equals, "")
+		c.Expect(redisCoalesceTarget(MakeRedisBatchCommandGet("Key")), gospec.Equals, "")
+	})
+
+	c.Specify("[RedisBatchQueue][RunAsync] Merges the commands on the same target", func() {
+		ptr := &RedisBatchQueue{
+			queue:     make(chan *RedisBatchCommand, 10),
+			coalescer: makeRedisBatchCoalescer(),
+		}
+		defer close(ptr.queue)
+
+		a1 := MakeRedisBatchCommandHashIncrementBy("Hash", "A", 1)
+		a2 := MakeRedisBatchCommandHashIncrementBy("Hash", "A", 2)
+		b := MakeRedisBatchCommandHashIncrementBy("Hash", "B", 5)
+		f1 := MakeRedisBatchCommandIncrementByFloat("Float", 0.5)
+		f2 := MakeRedisBatchCommandIncrementByFloat("Float", 0.25)
+		bit1 := MakeRedisBatchCommandSetBit("Bits", 7, true)
+		bit2 := MakeRedisBatchCommandSetBit("Bits", 7, false)
+		get := MakeRedisBatchCommandGet("Key")
+
+		c.Expect(ptr.RunAsync(a1, b, f1, bit1, get, a2, f2, bit2), gospec.Equals, nil)
+		c.Expect(ptr.Stats().Coalesced, gospec.Equals, uint64(2))
+
+		// Only the SETBITs (they reply with the previous bit) & the GET are queued until the end of the window
+		c.Expect(ptr.Len(), gospec.Equals, 3)
+		c.Expect(<-ptr.queue, gospec.Equals, bit1)
+		c.Expect(<-ptr.queue, gospec.Equals, get)
+		c.Expect(<-ptr.queue, gospec.Equals, bit2)
+
+		cmds := ptr.coalescer.flush(false)
+		c.Expect(len(cmds), gospec.Equals, 3)
+		c.Expect(cmds[0].GetCmd(), gospec.Equals, "HINCRBY")
+		c.Expect(cmds[0].GetArgs(), gospec.Equals, []string{"Hash", "A", "3"})
+		c.Expect(cmds[1], gospec.Equals, b)
+		c.Expect(cmds[2].GetArgs(), gospec.Equals, []string{"Float", "0.75"})
+
+		// The originals are left untouched
+		c.Expect(a1.GetArgs(), gospec.Equals, []string{"Hash", "A", "1"})
+		c.Expect(ptr.coalescer.flush(false), gospec.Equals, RedisBatchCommands{})
+	})
+
+	c.Specify("[RedisBatchQueue][RunAsync] Counters that would overflow are held separately", func() {
+		ptr := &RedisBatchQueue{
+			queue:     make(chan *RedisBatchCommand, 10),
+			coalescer: makeRedisBatchCoalescer(),
+		}
+		defer close(ptr.queue)
+
+		a1 := MakeRedisBatchCommandIncrementBy("Key", 1<<62)
+		a2 := MakeRedisBatchCommandIncrementBy("Key", 1<<62)
+		a3 := MakeRedisBatchCommandIncrementBy("Key", 1)
+		c.Expect(ptr.RunAsync(a1, a2, a3), gospec.Equals, nil)
+		c.Expect(ptr.Stats().Coalesced, gospec.Equals, uint64(1))
+		c.Expect(ptr.Len(), gospec.Equals, 0)
+
+		cmds := ptr.coalescer.flush(true)
+		c.Expect(len(cmds), gospec.Equals, 2)
+		c.Expect(cmds[0], gospec.Equals, a1)
+		c.Expect(cmds[1].GetArgs(), gospec.Equals, []string{"Key", "4611686018427387905"})
+
+		// Stopped, nothing is held anymore
+		c.Expect(ptr.RunAsync(a1), gospec.Equals, nil)
+		c.Expect(<-ptr.queue, gospec.Equals, a1)
+	})
+
+	c.Specify("[redisCoalesce] Doesn't merge floats that are, or sum to, inf or nan", func() {
+		float := func(value string) *RedisBatchCommand {
+			output := MakeRedisBatchCommand("INCRBYFLOAT")
+			output.WriteStringArg("Float")
+			output.WriteStringArg(value)
+			return output
+		}
+
+		merged := float("0.5")
+		c.Expect(redisCoalesce(merged, float("inf")), gospec.Equals, false)
+		c.Expect(redisCoalesce(merged, float("-Inf")), gospec.Equals, false)
+		c.Expect(redisCoalesce(merged, float("NaN")), gospec.Equals, false)
+		c.Expect(redisCoalesce(float("inf"), float("0.5")), gospec.Equals, false)
+		c.Expect(redisCoalesce(MakeRedisBatchCommandIncrementByFloat("Float", math.MaxFloat64), MakeRedisBatchCommandIncrementByFloat("Float", math.MaxFloat64)), gospec.Equals, false)
+		c.Expect(merged.GetArgs(), gospec.Equals, []string{"Float", "0.5"})
+
+		c.Expect(redisCoalesce(merged, float("0.25")), gospec.Equals, true)
+		c.Expect(merged.GetArgs(), gospec.Equals, []string{"Float", "0.75"})
+	})
+
+	c.Specify("[RedisBatchQueue][RunAsync] Commands on a held key run after it", func() {
+		ptr := &RedisBatchQueue{
+			queue:     make(chan *RedisBatchCommand, 10),
+			coalescer: makeRedisBatchCoalescer(),
+		}
+		defer close(ptr.queue)
+
+		expire := MakeRedisBatchCommandExpireIn("Key", time.Minute)
+		persist := MakeRedisBatchCommandPersist("Key")
+		other := MakeRedisBatchCommandGet("Other")
+		incr1 := MakeRedisBatchCommandIncrementBy("Counter", 1)
+		get := MakeRedisBatchCommandGet("Counter")
+		incr2 := MakeRedisBatchCommandIncrementBy("Counter", 2)
+		mget := MakeRedisBatchCommandMget("Other", "Counter")
+
+		c.Expect(ptr.RunAsync(expire, persist, other, incr1, get, incr2, mget), gospec.Equals, nil)
+		c.Expect(ptr.Stats().Coalesced, gospec.Equals, uint64(0))
+
+		// Only the GET on a key that isn't held is queued until the end of the window
+		c.Expect(ptr.Len(), gospec.Equals, 1)
+		c.Expect(<-ptr.queue, gospec.Equals, other)
+
+		// The INCRBY after the GET isn't merged into the one before it
+		c.Expect(ptr.coalescer.flush(false), gospec.Equals, RedisBatchCommands{expire, persist, incr1, get, incr2, mget})
+	})
+
+	c.Specify("[RedisBatchQueue][RunAsync] Commands with unknown keys run after every held command", func() {
+		coalescer := makeRedisBatchCoalescer()
+		unknown := MakeRedisBatchCommand("NOTACOMMAND")
+
+		held, _ := coalescer.add(unknown)
+		c.Expect(held, gospec.Equals, false)
+
+		coalescer.add(MakeRedisBatchCommandExpireIn("Key", time.Minute))
+		held, _ = coalescer.add(unknown)
+		c.Expect(held, gospec.Equals, true)
+		held, _ = coalescer.add(MakeRedisBatchCommandGet("Other"))
+		c.Expect(held, gospec.Equals, true)
+		c.Expect(len(coalescer.flush(false)), gospec.Equals, 3)
+	})
+
+	c.Specify("[redisBatchCoalescer][pushed] Commands on a key being pushed wait for the next window", func() {
+		coalescer := makeRedisBatchCoalescer()
+		expire := MakeRedisBatchCommandExpireIn("Key", time.Minute)
+		persist := MakeRedisBatchCommandPersist("Key")
+
+		coalescer.add(expire)
+		c.Expect(coalescer.flush(false), gospec.Equals, RedisBatchCommands{expire})
+
+		// The EXPIRE isn't on the queue yet
+		held, _ := coalescer.add(persist)
+		c.Expect(held, gospec.Equals, true)
+
+		coalescer.pushed(expire)
+		c.Expect(coalescer.flush(false), gospec.Equals, RedisBatchCommands{persist})
+		coalescer.pushed(persist)
+
+		held, _ = coalescer.add(MakeRedisBatchCommandGet("Key"))
+		c.Expect(held, gospec.Equals, false)
+		c.Expect(coalescer.inflight.total, gospec.Equals, 0)
+		c.Expect(len(coalescer.inflight.keys), gospec.Equals, 0)
+	})
+
+	c.Specify("[RedisBatchCommand][complete] The merged commands share the reply", func() {
+		ptr := &RedisBatchQueue{
+			queue:     make(chan *RedisBatchCommand, 10),
+			coalescer: makeRedisBatchCoalescer(),
+		}
+		defer close(ptr.queue)
+
+		future, err := ptr.RunAsyncFuture(MakeRedisBatchCommandIncrementBy("Key", 1), MakeRedisBatchCommandIncrementBy("Key", 2))
+		c.Expect(err, gospec.Equals, nil)
+		c.Expect(future.IsDone(), gospec.Equals, false)
+
+		cmds := ptr.coalescer.flush(false)
+		c.Expect(len(cmds), gospec.Equals, 1)
+
+		cmds[0].reply = &redis.Reply{Type: redis.NilReply}
+		cmds[0].complete()
+		c.Expect(future.IsDone(), gospec.Equals, true)
+		c.Expect(future.Commands[0].Reply(), gospec.Equals, cmds[0].Reply())
+		c.Expect(future.Commands[1].Reply(), gospec.Equals, cmds[0].Reply())
+	})
+
+	c.Specify("[RedisBatchQueue][runCoalescer] Queues the merged commands every window, and on Close", func() {
+		ptr := &RedisBatchQueue{CoalesceWindow: time.Millisecond}
+		queue := make(chan *RedisBatchCommand, 10)
+		coalescer := makeRedisBatchCoalescer()
+		go ptr.runCoalescer(coalescer, queue, make(chan struct{}))
+
+		c.Expect(ptr.coalesce(coalescer, MakeRedisBatchCommandExpireIn("Key", time.Minute)), gospec.Equals, true)
+		c.Expect(ptr.coalesce(coalescer, MakeRedisBatchCommandExpireIn("Key", time.Hour)), gospec.Equals, true)
+
+		select {
+		case cmd := <-queue:
+			c.Expect(cmd.GetArgs(), gospec.Equals, []string{"Key", "3600"})
+		case <-time.After(time.Second):
+			c.Expect("Coalesced command was never queued", gospec.Equals, "")
+		}
+
+		close(coalescer.stop)
+		<-coalescer.done
+		c.Expect(ptr.coalesce(coalescer, MakeRedisBatchCommandExpireIn("Key", time.Minute)), gospec.Equals, false)
+	})
+
+	c.Specify("[RedisBatchQueue] Coalesces counters against Redis", func() {
+		logger := MakeLog4goLogger(log4go.NewDefaultLogger(log4go.CRITICAL))
+		server, err := StartRedisServer(logger)
+		if nil != err {
+			panic(err)
+		}
+		defer server.Close()
+
+		ptr := &RedisBatchQueue{
+			Logger:           logger,
+			Connection:       server.Connection(),
+			QueueSize:        10,
+			WorkersSize:      2,
+			WorkersBatchSize: 5,
+			CoalesceWindow:   10 * time.Millisecond,
+		}
+		c.Expect(ptr.Open(), gospec.Equals, nil)
+
+		cmds := RedisBatchCommands{}
+		for i := 0; i < 100; i++ {
+			cmds = append(cmds, MakeRedisBatchCommandHashIncrementBy("Hash", "Field", 1))
+		}
+		c.Expect(ptr.RunSync(context.Background(), cmds...), gospec.Equals, nil)
+		c.Expect(ptr.Stats().Coalesced, gospec.Equals, uint64(99))
+
+		value, err := cmds[0].ReplyToInt64Ptr()
+		c.Expect(err, gospec.Equals, nil)
+		c.Expect(*value, gospec.Equals, int64(100))
+
+		// Close queues the commands still being coalesced
+		incr := MakeRedisBatchCommandIncrementBy("Counter", 5)
+		c.Expect(ptr.RunAsync(incr), gospec.Equals, nil)
+		dropped, err := ptr.Close(context.Background())
+		c.Expect(dropped, gospec.Equals, 0)
+		c.Expect(err, gospec.Equals, nil)
+
+		value, err = incr.ReplyToInt64Ptr()
+		c.Expect(err, gospec.Equals, nil)
+		c.Expect(*value, gospec.Equals, int64(5))
+	})
+}
@@ -0,0 +1,260 @@
+//
+// Priority lanes for the RedisBatchQueue
+//
+// Every lane is a queue of its own, the workers drain the lanes with weighted fairness:
+// while several lanes have commands waiting, a lane with Weight=10 gets 10 pops for every pop of a lane with Weight=1.
+// RunAsync queues onto the default lane, named RedisBatchDefaultLane, which holds QueueSize commands with a weight of 1.
+//
+// Usage:
+//   queue := &RedisBatchQueue{
+//     ...
+//     Lanes: []RedisBatchLane{
+//       {Name: "invalidations", Size: 100, Weight: 10},
+//     },
+//   }
+//
+//   queue.RunAsync(MakeRedisBatchCommandHashIncrementBy("stats:clicks", url, 1))
+//   queue.RunAsyncLane("invalidations", MakeRedisBatchCommandDelete("cache:user:1"))
+//
+
+package dog_pool
+
+import "fmt"
+import "reflect"
+import "time"
+
+//
+// Name of the lane RunAsync queues onto
+//
+const RedisBatchDefaultLane = ""
+
+const redis_batch_default_lane_weight = 1
+
+//
+// Configuration of a named lane
+//
+type RedisBatchLane struct {
+	Name   string "Name of the lane, passed to RunAsyncLane"
+	Size   uint   "How many commands the lane holds"
+	Weight uint   "Share of the pops while several lanes have commands waiting, the default lane has a weight of 1"
+}
+
+func (p RedisBatchLane) String() string {
+	return fmt.Sprintf("RedisBatchLane { Name=%v, Size=%v, Weight=%v }", p.Name, p.Size, p.Weight)
+}
+
+//
+// Open lane
+//
+type redisBatchLane struct {
+	name      string
+	weight    int
+	queue     chan *RedisBatchCommand
+	coalescer *redisBatchCoalescer "(optional) Holds the commands being coalesced"
+}
+
+//
+// Make the named lanes, or return an error
+//
+func makeRedisBatchLanes(configs []RedisBatchLane) ([]*redisBatchLane, error) {
+	lanes := make([]*redisBatchLane, len(configs))
+	names := map[string]bool{RedisBatchDefaultLane: true}
+	for i, config := range configs {
+		switch {
+		case names[config.Name]:
+			return nil, fmt.Errorf("[RedisBatchQueue][Open] Lane[%v] name %q is already taken!", i, config.Name)
+		case 0 == config.Size:
+			return nil, fmt.Errorf("[RedisBatchQueue][Open] Lane[%v] Size[%v] must be > 0!", i, config.Size)
+		case 0 == config.Weight:
+			return nil, fmt.Errorf("[RedisBatchQueue][Open] Lane[%v] Weight[%v] must be > 0!", i, config.Weight)
+		}
+		names[config.Name] = true
+		lanes[i] = &redisBatchLane{name: config.Name, weight: int(config.Weight), queue: make(chan *RedisBatchCommand, config.Size)}
+	}
+	return lanes, nil
+}
+
+//
+// Push the command(s) onto the named lane, applying the Overflow policy when it is full
+//
+func (p *RedisBatchQueue) RunAsyncLane(lane string, cmds ...*RedisBatchCommand) error {
+	return p.runAsync("RunAsyncLane", lane, cmds)
+}
+
+//
+// Number of commands waiting in the lane, -1 if the queue is closed or the lane is unknown
+//
+func (p *RedisBatchQueue) LaneLen(lane string) int {
+	p.mutex.RLock()
+	defer p.mutex.RUnlock()
+	if queue, _ := p.lane(lane); nil != queue {
+		return len(queue)
+	}
+	return -1
+}
+
+//
+// Capacity of the lane, -1 if the queue is closed or the lane is unknown
+//
+func (p *RedisBatchQueue) LaneCap(lane string) int {
+	p.mutex.RLock()
+	defer p.mutex.RUnlock()
+	if queue, _ := p.lane(lane); nil != queue {
+		return cap(queue)
+	}
+	return -1
+}
+
+//
+// Queue & coalescer of the lane, the caller holds the read lock
+//
+// Returns:
+//   queue, coalescer --> The lane is open, the coalescer may be nil
+//   nil,   nil       --> The queue is closed, or the lane is unknown
+//
+func (p *RedisBatchQueue) lane(name string) (chan *RedisBatchCommand, *redisBatchCoalescer) {
+	if RedisBatchDefaultLane == name || nil == p.queue {
+		return p.queue, p.coalescer
+	}
+	for _, lane := range p.lanes {
+		if name == lane.name {
+			return lane.queue, lane.coalescer
+		}
+	}
+	return nil, nil
+}
+
+//
+// Every lane, the default lane first; the caller holds the read lock
+//
+func (p *RedisBatchQueue) allLanes() []*redisBatchLane {
+	if nil == p.queue {
+		return nil
+	}
+
+	default_lane := &redisBatchLane{name: RedisBatchDefaultLane, weight: redis_batch_default_lane_weight, queue: p.queue, coalescer: p.coalescer}
+	return append([]*redisBatchLane{default_lane}, p.lanes...)
+}
+
+//
+// ==================================================
+//
+// Draining the lanes with weighted fairness:
+//
+// ==================================================
+//
+
+//
+// Worker's view of the lanes, smooth weighted round-robin over the lanes with commands waiting
+//
+type redisBatchLaneReader struct {
+	lanes   []*redisBatchLane
+	current []int  "Credit of each lane, the lane with the most credit is popped first"
+	closed  []bool "Lanes that are closed & drained"
+	open    int    "Number of lanes still open"
+}
+
+func makeRedisBatchLaneReader(lanes []*redisBatchLane) *redisBatchLaneReader {
+	return &redisBatchLaneReader{
+		lanes:   lanes,
+		current: make([]int, len(lanes)),
+		closed:  make([]bool, len(lanes)),
+		open:    len(lanes),
+	}
+}
+
+//
+// Pop a command from the lanes without blocking:
+//
+// Returns:
+//   ptr, true  --> Got a command, a lane is open
+//   nil, true  --> Every lane is empty, a lane is open
+//   nil, false --> Every lane is closed
+//
+func (p *redisBatchLaneReader) tryPop() (*RedisBatchCommand, bool) {
+	// Only the lanes with commands waiting earn credit, an idle lane doesn't build up a burst
+	total := 0
+	waiting := make([]bool, len(p.lanes))
+	for i, lane := range p.lanes {
+		if !p.closed[i] && len(lane.queue) > 0 {
+			waiting[i] = true
+			p.current[i] += lane.weight
+			total += lane.weight
+		}
+	}
+
+	for {
+		best := -1
+		for i := range p.lanes {
+			if waiting[i] && (best < 0 || p.current[i] > p.current[best]) {
+				best = i
+			}
+		}
+		if best < 0 {
+			break
+		}
+		waiting[best] = false
+
+		select {
+		case cmd, ok := <-p.lanes[best].queue:
+			if ok {
+				p.current[best] -= total
+				return cmd, true
+			}
+			p.close(best)
+		default:
+			// Another worker got there first, undo the credit
+			p.current[best] -= p.lanes[best].weight
+		}
+	}
+
+	return nil, p.open > 0
+}
+
+//
+// Pop a command from the lanes, blocks until a command is available, every lane is closed, or the timeout fires:
+//
+// Returns:
+//   ptr, true  --> Got a command, a lane is open
+//   nil, true  --> The timeout fired, a lane is open
+//   nil, false --> Every lane is closed
+//
+func (p *redisBatchLaneReader) pop(timeout <-chan time.Time) (*RedisBatchCommand, bool) {
+	for {
+		if cmd, ok := p.tryPop(); nil != cmd || !ok {
+			return cmd, ok
+		}
+
+		// Every lane is empty, wait on all of them
+		cases := make([]reflect.SelectCase, len(p.lanes)+1)[0:0]
+		indexes := make([]int, len(p.lanes))[0:0]
+		for i, lane := range p.lanes {
+			if !p.closed[i] {
+				cases = append(cases, reflect.SelectCase{Dir: reflect.SelectRecv, Chan: reflect.ValueOf(lane.queue)})
+				indexes = append(indexes, i)
+			}
+		}
+		if nil != timeout {
+			cases = append(cases, reflect.SelectCase{Dir: reflect.SelectRecv, Chan: reflect.ValueOf(timeout)})
+		}
+
+		chosen, value, ok := reflect.Select(cases)
+		switch {
+		case chosen == len(indexes):
+			// Done waiting
+			return nil, true
+		case !ok:
+			p.close(indexes[chosen])
+		default:
+			return value.Interface().(*RedisBatchCommand), true
+		}
+	}
+}
+
+func (p *redisBatchLaneReader) close(i int) {
+	if !p.closed[i] {
+		p.closed[i] = true
+		p.current[i] = 0
+		p.open--
+	}
+}
@@ -0,0 +1,186 @@
+package dog_pool
+
+import "context"
+import "time"
+import "github.com/alecthomas/log4go"
+
+import "testing"
+import "github.com/orfjackal/gospec/src/gospec"
+
+func TestRedisBatchQueueLanesSpecs(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping test in benchmark mode.")
+		return
+	}
+	r := gospec.NewRunner()
+	r.AddSpec(RedisBatchQueueLanesSpecs)
+	gospec.MainGoTest(r, t)
+}
+
+// Fill a lane with GETs on the lane's name
+func makeTestRedisBatchLane(name string, weight, size int) *redisBatchLane {
+	lane := &redisBatchLane{name: name, weight: weight, queue: make(chan *RedisBatchCommand, size)}
+	for i := 0; i < size; i++ {
+		lane.queue <- MakeRedisBatchCommandGet(name)
+	}
+	return lane
+}
+
+// Pop n commands, returning the name of the lane each came from
+func popTestRedisBatchLanes(reader *redisBatchLaneReader, n int) string {
+	output := ""
+	for i := 0; i < n; i++ {
+		cmd, ok := reader.tryPop()
+		if nil == cmd || !ok {
+			return output
+		}
+		output += cmd.GetArgs()[0]
+	}
+	return output
+}
+
+func RedisBatchQueueLanesSpecs(c gospec.Context) {
+
+	c.Specify("[RedisBatchQueue][Open] Validates the Lanes", func() {
+		ptr := &RedisBatchQueue{
+			Connection:       &RedisConnection{},
+			QueueSize:        10,
+			WorkersSize:      1,
+			WorkersBatchSize: 1,
+			Lanes:            []RedisBatchLane{{Name: "fast", Size: 10, Weight: 10}, {Name: "fast", Size: 10, Weight: 1}},
+		}
+		c.Expect(ptr.Open().Error(), gospec.Equals, `[RedisBatchQueue][Open] Lane[1] name "fast" is already taken!`)
+
+		ptr.Lanes = []RedisBatchLane{{Name: RedisBatchDefaultLane, Size: 10, Weight: 1}}
+		c.Expect(ptr.Open().Error(), gospec.Equals, `[RedisBatchQueue][Open] Lane[0] name "" is already taken!`)
+
+		ptr.Lanes = []RedisBatchLane{{Name: "fast", Size: 0, Weight: 1}}
+		c.Expect(ptr.Open().Error(), gospec.Equals, "[RedisBatchQueue][Open] Lane[0] Size[0] must be > 0!")
+
+		ptr.Lanes = []RedisBatchLane{{Name: "fast", Size: 10, Weight: 0}}
+		c.Expect(ptr.Open().Error(), gospec.Equals, "[RedisBatchQueue][Open] Lane[0] Weight[0] must be > 0!")
+	})
+
+	c.Specify("[RedisBatchQueue][RunAsyncLane] Queues onto the named lane", func() {
+		ptr := &RedisBatchQueue{
+			queue: make(chan *RedisBatchCommand, 1),
+			lanes: []*redisBatchLane{{name: "fast", weight: 10, queue: make(chan *RedisBatchCommand, 2)}},
+		}
+
+		c.Expect(ptr.RunAsyncLane("fast", MakeRedisBatchCommandGet("A")), gospec.Equals, nil)
+		c.Expect(ptr.Len(), gospec.Equals, 0)
+		c.Expect(ptr.LaneLen("fast"), gospec.Equals, 1)
+		c.Expect(ptr.LaneCap("fast"), gospec.Equals, 2)
+		c.Expect(ptr.LaneLen(RedisBatchDefaultLane), gospec.Equals, 0)
+		c.Expect(ptr.LaneCap(RedisBatchDefaultLane), gospec.Equals, 1)
+		c.Expect(ptr.LaneLen("slow"), gospec.Equals, -1)
+		c.Expect(ptr.Stats().Lanes, gospec.Equals, map[string]int{"fast": 1})
+
+		err := ptr.RunAsyncLane("slow", MakeRedisBatchCommandGet("A"))
+		c.Expect(err.Error(), gospec.Equals, `[RedisBatchQueue][RunAsyncLane] Unknown lane: "slow"!`)
+
+		err = ptr.RunAsyncLane("fast", nil)
+		c.Expect(err.Error(), gospec.Equals, "[RedisBatchQueue][RunAsyncLane][0] Nil RedisBatchCommand!")
+
+		dropped, err := ptr.Close(context.Background())
+		c.Expect(dropped, gospec.Equals, 0)
+		c.Expect(err, gospec.Equals, nil)
+		c.Expect(ptr.LaneLen("fast"), gospec.Equals, -1)
+
+		err = ptr.RunAsyncLane("fast", MakeRedisBatchCommandGet("A"))
+		c.Expect(err.Error(), gospec.Equals, "[RedisBatchQueue][RunAsyncLane] Queue is closed!")
+	})
+
+	c.Specify("[redisBatchLaneReader][tryPop] Drains the lanes with weighted fairness", func() {
+		reader := makeRedisBatchLaneReader([]*redisBatchLane{makeTestRedisBatchLane("A", 3, 8), makeTestRedisBatchLane("B", 1, 8)})
+		c.Expect(popTestRedisBatchLanes(reader, 8), gospec.Equals, "AABAAABA")
+
+		// Once lane A is empty, lane B gets every pop
+		c.Expect(popTestRedisBatchLanes(reader, 10), gospec.Equals, "AABBBBBB")
+
+		cmd, ok := reader.tryPop()
+		c.Expect(cmd, gospec.Satisfies, nil == cmd)
+		c.Expect(ok, gospec.Equals, true)
+	})
+
+	c.Specify("[redisBatchLaneReader][tryPop] An idle lane doesn't build up a burst", func() {
+		a := makeTestRedisBatchLane("A", 1, 10)
+		b := &redisBatchLane{name: "B", weight: 1, queue: make(chan *RedisBatchCommand, 10)}
+		reader := makeRedisBatchLaneReader([]*redisBatchLane{a, b})
+		c.Expect(popTestRedisBatchLanes(reader, 6), gospec.Equals, "AAAAAA")
+
+		for i := 0; i < 4; i++ {
+			b.queue <- MakeRedisBatchCommandGet("B")
+		}
+		c.Expect(popTestRedisBatchLanes(reader, 4), gospec.Equals, "ABAB")
+	})
+
+	c.Specify("[redisBatchLaneReader][pop] Waits on every lane until they are all closed", func() {
+		a := &redisBatchLane{name: "A", weight: 1, queue: make(chan *RedisBatchCommand, 1)}
+		b := &redisBatchLane{name: "B", weight: 1, queue: make(chan *RedisBatchCommand, 1)}
+		reader := makeRedisBatchLaneReader([]*redisBatchLane{a, b})
+
+		// Times out
+		cmd, ok := reader.pop(time.After(time.Millisecond))
+		c.Expect(cmd, gospec.Satisfies, nil == cmd)
+		c.Expect(ok, gospec.Equals, true)
+
+		go func() {
+			time.Sleep(time.Millisecond)
+			b.queue <- MakeRedisBatchCommandGet("B")
+		}()
+		cmd, ok = reader.pop(nil)
+		c.Expect(cmd.GetArgs(), gospec.Equals, []string{"B"})
+		c.Expect(ok, gospec.Equals, true)
+
+		a.queue <- MakeRedisBatchCommandGet("A")
+		close(a.queue)
+		close(b.queue)
+
+		// Drains the closed lanes first
+		cmd, ok = reader.pop(nil)
+		c.Expect(cmd.GetArgs(), gospec.Equals, []string{"A"})
+		c.Expect(ok, gospec.Equals, true)
+
+		cmd, ok = reader.pop(nil)
+		c.Expect(cmd, gospec.Satisfies, nil == cmd)
+		c.Expect(ok, gospec.Equals, false)
+	})
+
+	c.Specify("[RedisBatchQueue] Runs the commands of every lane", func() {
+		logger := MakeLog4goLogger(log4go.NewDefaultLogger(log4go.CRITICAL))
+		server, err := StartRedisServer(logger)
+		if nil != err {
+			panic(err)
+		}
+		defer server.Close()
+
+		ptr := &RedisBatchQueue{
+			Logger:           logger,
+			Connection:       server.Connection(),
+			QueueSize:        10,
+			WorkersSize:      2,
+			WorkersBatchSize: 5,
+			MaxLinger:        time.Millisecond,
+			Lanes:            []RedisBatchLane{{Name: "fast", Size: 10, Weight: 10}},
+		}
+		c.Expect(ptr.Open(), gospec.Equals, nil)
+
+		slow := MakeRedisBatchCommandHashIncrementBy("Hash", "Slow", 1)
+		fast := MakeRedisBatchCommandHashIncrementBy("Hash", "Fast", 2)
+		c.Expect(ptr.RunAsync(slow), gospec.Equals, nil)
+		c.Expect(ptr.RunAsyncLane("fast", fast), gospec.Equals, nil)
+
+		dropped, err := ptr.Close(context.Background())
+		c.Expect(dropped, gospec.Equals, 0)
+		c.Expect(err, gospec.Equals, nil)
+
+		value, err := slow.ReplyToInt64Ptr()
+		c.Expect(err, gospec.Equals, nil)
+		c.Expect(*value, gospec.Equals, int64(1))
+
+		value, err = fast.ReplyToInt64Ptr()
+		c.Expect(err, gospec.Equals, nil)
+		c.Expect(*value, gospec.Equals, int64(2))
+	})
+}
@@ -0,0 +1,240 @@
+//
+// Backpressure & overflow policies for the RedisBatchQueue
+//
+// Usage:
+//   queue := &RedisBatchQueue{
+//     ...
+//     Overflow:        RedisBatchOverflowBlock,
+//     OverflowTimeout: 10 * time.Millisecond,
+//   }
+//
+//   queue := &RedisBatchQueue{
+//     ...
+//     Overflow:      RedisBatchOverflowSpill,
+//     OverflowSpill: func(cmd *RedisBatchCommand) { ... },
+//   }
+//
+//   queue := &RedisBatchQueue{
+//     ...
+//     Overflow: RedisBatchOverflowSpill,
+//     SpillLog: spill_log, // see OpenRedisSpillLog
+//   }
+//
+
+package dog_pool
+
+import "fmt"
+import "time"
+import "github.com/RUNDSP/radix/redis"
+
+//
+// What should RunAsync do when the queue is full?
+//
+type RedisBatchOverflowPolicy int
+
+const (
+	RedisBatchOverflowBlock      RedisBatchOverflowPolicy = iota // Wait for room, up to the OverflowTimeout (if any)
+	RedisBatchOverflowDropNewest                                 // Drop the command being queued
+	RedisBatchOverflowDropOldest                                 // Drop the oldest command in the queue to make room
+	RedisBatchOverflowSpill                                      // Hand the command to the OverflowSpill callback, or the SpillLog
+)
+
+var redis_batch_overflow_names = []string{"Block", "DropNewest", "DropOldest", "Spill"}
+
+func (p RedisBatchOverflowPolicy) String() string {
+	if p < RedisBatchOverflowBlock || p > RedisBatchOverflowSpill {
+		return fmt.Sprintf("RedisBatchOverflowPolicy(%d)", int(p))
+	}
+	return redis_batch_overflow_names[p]
+}
+
+//
+// Snapshot of the queue's depth & overflow counters
+//
+type RedisBatchQueueStats struct {
+	Len     int    "Number of commands waiting in the queue"
+	Cap     int    "Capacity of the queue"
+	Dropped uint64 "Number of commands dropped by the overflow policy, or a blocking timeout"
+	Spilled uint64 "Number of commands handed to OverflowSpill"
+
+	Coalesced uint64 "Number of commands merged into another, see CoalesceWindow"
+
+	Lanes map[string]int "(optional) Number of commands waiting in each named lane"
+
+	Workers int "Number of workers running, see MaxWorkers"
+
+	Throttled time.Duration "Total time the workers waited on the RateLimit"
+}
+
+//
+// Snapshot of the queue's depth & overflow counters
+//
+func (p *RedisBatchQueue) Stats() RedisBatchQueueStats {
+	stats := RedisBatchQueueStats{Len: p.Len(), Cap: p.Cap(), Workers: p.Workers()}
+
+	p.mutex.RLock()
+	for _, lane := range p.lanes {
+		if nil == stats.Lanes {
+			stats.Lanes = map[string]int{}
+		}
+		stats.Lanes[lane.name] = len(lane.queue)
+	}
+	p.mutex.RUnlock()
+
+	p.stats_mutex.Lock()
+	defer p.stats_mutex.Unlock()
+	stats.Dropped = p.dropped
+	stats.Spilled = p.spilled
+	stats.Coalesced = p.coalesced
+	if nil != p.RateLimit {
+		stats.Throttled = p.RateLimit.Throttled()
+	}
+	return stats
+}
+
+//
+// Push the command(s) onto the queue without blocking, regardless of the Overflow policy.
+// Commands that don't fit are left to the caller, they are not dropped; coalesced commands count as queued.
+//
+// Returns:
+//   len(cmds), nil        --> Every command was queued
+//   n, ErrQueueIsFull     --> The first n commands were queued
+//   n, err                --> The queue is closed, or cmds[n] is nil
+//
+func (p *RedisBatchQueue) TryRunAsync(cmds ...*RedisBatchCommand) (int, error) {
+	p.mutex.RLock()
+	defer p.mutex.RUnlock()
+
+	queue, coalescer := p.queue, p.coalescer
+	if nil == queue {
+		return 0, fmt.Errorf("[RedisBatchQueue][TryRunAsync] Queue is closed!")
+	}
+
+	for i, cmd := range cmds {
+		if nil == cmd {
+			return i, fmt.Errorf("[RedisBatchQueue][TryRunAsync][%v] Nil RedisBatchCommand!", i)
+		}
+
+		// Held until the end of the CoalesceWindow
+		if p.coalesce(coalescer, cmd) {
+			continue
+		}
+
+		select {
+		case queue <- cmd:
+			// Queued, continue ...
+		default:
+			return i, ErrQueueIsFull
+		}
+	}
+	return len(cmds), nil
+}
+
+//
+// Push the command onto the queue, applying the Overflow policy if it is full
+//
+// Returns:
+//   nil              --> Queued or spilled
+//   ErrQueueIsFull   --> Dropped
+//   err              --> Dropped, the SpillLog failed
+//   ErrQueueIsClosed --> Dropped, the queue was closed while we were blocked
+//
+func (p *RedisBatchQueue) push(queue chan *RedisBatchCommand, closing <-chan struct{}, cmd *RedisBatchCommand, deadline time.Time) error {
+	// Fast path, there is room in the queue
+	select {
+	case queue <- cmd:
+		return nil
+	default:
+	}
+
+	switch p.Overflow {
+	case RedisBatchOverflowDropNewest:
+		p.drop(cmd, ErrQueueIsFull)
+		return ErrQueueIsFull
+
+	case RedisBatchOverflowDropOldest:
+		for {
+			// Make room, unless a worker already did
+			select {
+			case oldest := <-queue:
+				p.drop(oldest, ErrQueueIsFull)
+			default:
+			}
+
+			select {
+			case queue <- cmd:
+				return nil
+			default:
+			}
+		}
+
+	case RedisBatchOverflowSpill:
+		return p.spill(cmd)
+
+	default:
+		// RedisBatchOverflowBlock, a nil timeout channel waits forever
+		var timeout <-chan time.Time
+		if p.OverflowTimeout > 0 {
+			timer := time.NewTimer(time.Until(deadline))
+			defer timer.Stop()
+			timeout = timer.C
+		}
+
+		select {
+		case queue <- cmd:
+			return nil
+		case <-timeout:
+			p.drop(cmd, ErrQueueIsFull)
+			return ErrQueueIsFull
+		case <-closing:
+			p.drop(cmd, ErrQueueIsClosed)
+			return ErrQueueIsClosed
+		}
+	}
+}
+
+//
+// Count the dropped command and resolve its future (if any) with the error
+//
+func (p *RedisBatchQueue) drop(cmd *RedisBatchCommand, err error) {
+	p.stats_mutex.Lock()
+	p.dropped++
+	p.stats_mutex.Unlock()
+
+	logAt(p.Logger, LogDebug, "[RedisBatchQueue][%v] Dropped cmd=%v: err=%v", p.Overflow, cmd, err)
+
+	cmd.reply = &redis.Reply{Type: redis.ErrorReply, Err: err}
+	cmd.complete()
+}
+
+//
+// Count the spilled command and hand it to the OverflowSpill callback, or append it to the SpillLog.
+// The callback may run the command itself (i.e. RedisCmd), otherwise its future (if any) resolves with ErrQueueIsFull;
+// the futures of the commands in the SpillLog resolve with ErrQueueIsSpilled.
+//
+func (p *RedisBatchQueue) spill(cmd *RedisBatchCommand) error {
+	if nil == p.OverflowSpill {
+		if err := p.SpillLog.Append(cmd); nil != err {
+			logAt(p.Logger, LogCritical, "[RedisBatchQueue][RunAsync][%v] Error spilling Redis Command: err=%v, cmd=%v", p.Overflow, err, cmd)
+			p.drop(cmd, err)
+			return err
+		}
+		cmd.reply = &redis.Reply{Type: redis.ErrorReply, Err: ErrQueueIsSpilled}
+	}
+
+	p.stats_mutex.Lock()
+	p.spilled++
+	p.stats_mutex.Unlock()
+
+	logAt(p.Logger, LogDebug, "[RedisBatchQueue][RunAsync][%v] Queue is full, spilled cmd=%v", p.Overflow, cmd)
+
+	if nil != p.OverflowSpill {
+		p.OverflowSpill(cmd)
+	}
+
+	if nil == cmd.reply {
+		cmd.reply = &redis.Reply{Type: redis.ErrorReply, Err: ErrQueueIsFull}
+	}
+	cmd.complete()
+	return nil
+}
@@ -0,0 +1,129 @@
+package dog_pool
+
+import "context"
+import "time"
+
+import "testing"
+import "github.com/orfjackal/gospec/src/gospec"
+
+func TestRedisBatchQueueOverflowSpecs(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping test in benchmark mode.")
+		return
+	}
+	r := gospec.NewRunner()
+	r.AddSpec(RedisBatchQueueOverflowSpecs)
+	gospec.MainGoTest(r, t)
+}
+
+func RedisBatchQueueOverflowSpecs(c gospec.Context) {
+
+	c.Specify("[RedisBatchOverflowPolicy][String]", func() {
+		c.Expect(RedisBatchOverflowBlock.String(), gospec.Equals, "Block")
+		c.Expect(RedisBatchOverflowDropOldest.String(), gospec.Equals, "DropOldest")
+		c.Expect(RedisBatchOverflowPolicy(99).String(), gospec.Equals, "RedisBatchOverflowPolicy(99)")
+	})
+
+	c.Specify("[RedisBatchQueue][Open] Validates the Overflow policy", func() {
+		ptr := &RedisBatchQueue{
+			Connection:       &RedisConnection{},
+			QueueSize:        10,
+			WorkersSize:      1,
+			WorkersBatchSize: 1,
+			Overflow:         RedisBatchOverflowSpill,
+		}
+		err := ptr.Open()
+		c.Expect(err.Error(), gospec.Equals, "[RedisBatchQueue][Open] OverflowSpill or SpillLog is required by the Spill policy!")
+
+		ptr.Overflow = RedisBatchOverflowPolicy(99)
+		err = ptr.Open()
+		c.Expect(err.Error(), gospec.Equals, "[RedisBatchQueue][Open] Invalid Overflow policy: RedisBatchOverflowPolicy(99)!")
+	})
+
+	c.Specify("[RedisBatchQueue][RunAsync] Block times out after the OverflowTimeout", func() {
+		ptr := &RedisBatchQueue{
+			OverflowTimeout: 5 * time.Millisecond,
+			queue:           make(chan *RedisBatchCommand, 1),
+		}
+		defer close(ptr.queue)
+
+		c.Expect(ptr.RunAsync(MakeRedisBatchCommandGet("A")), gospec.Equals, nil)
+
+		started := time.Now()
+		c.Expect(ptr.RunAsync(MakeRedisBatchCommandGet("B"), MakeRedisBatchCommandGet("C")), gospec.Equals, ErrQueueIsFull)
+		c.Expect(time.Since(started) < 50*time.Millisecond, gospec.Equals, true)
+		c.Expect(ptr.Stats().Dropped, gospec.Equals, uint64(2))
+		c.Expect(ptr.Len(), gospec.Equals, 1)
+	})
+
+	c.Specify("[RedisBatchQueue][RunAsync] DropNewest drops the commands that don't fit", func() {
+		ptr := &RedisBatchQueue{
+			Overflow: RedisBatchOverflowDropNewest,
+			queue:    make(chan *RedisBatchCommand, 2),
+		}
+		defer close(ptr.queue)
+
+		a, b, c_cmd := MakeRedisBatchCommandGet("A"), MakeRedisBatchCommandGet("B"), MakeRedisBatchCommandGet("C")
+		c.Expect(ptr.RunAsync(a, b, c_cmd), gospec.Equals, ErrQueueIsFull)
+		c.Expect(<-ptr.queue, gospec.Equals, a)
+		c.Expect(<-ptr.queue, gospec.Equals, b)
+		c.Expect(c_cmd.Reply().Err, gospec.Equals, ErrQueueIsFull)
+		c.Expect(ptr.String(), gospec.Equals, "RedisBatchQueue { Connection=<nil>, QueueSize=0, WorkersSize=0, WorkersBatchSize=0, Queue.Cap=2, Queue.Len=0, Dropped=1, Spilled=0 }")
+	})
+
+	c.Specify("[RedisBatchQueue][RunAsync] DropOldest makes room for the newest commands", func() {
+		ptr := &RedisBatchQueue{
+			Overflow: RedisBatchOverflowDropOldest,
+			queue:    make(chan *RedisBatchCommand, 2),
+		}
+		defer close(ptr.queue)
+
+		a, b, c_cmd := MakeRedisBatchCommandGet("A"), MakeRedisBatchCommandGet("B"), MakeRedisBatchCommandGet("C")
+		future, err := ptr.RunAsyncFuture(a)
+		c.Expect(err, gospec.Equals, nil)
+
+		c.Expect(ptr.RunAsync(b, c_cmd), gospec.Equals, nil)
+		c.Expect(<-ptr.queue, gospec.Equals, b)
+		c.Expect(<-ptr.queue, gospec.Equals, c_cmd)
+		c.Expect(ptr.Stats().Dropped, gospec.Equals, uint64(1))
+
+		// The dropped command's future resolves with the error
+		c.Expect(future.Wait(context.Background()), gospec.Equals, ErrQueueIsFull)
+	})
+
+	c.Specify("[RedisBatchQueue][RunAsync] Spill hands the commands that don't fit to the callback", func() {
+		spilled := RedisBatchCommands{}
+		ptr := &RedisBatchQueue{
+			Overflow:      RedisBatchOverflowSpill,
+			OverflowSpill: func(cmd *RedisBatchCommand) { spilled = append(spilled, cmd) },
+			queue:         make(chan *RedisBatchCommand, 1),
+		}
+		defer close(ptr.queue)
+
+		a, b := MakeRedisBatchCommandGet("A"), MakeRedisBatchCommandGet("B")
+		c.Expect(ptr.RunAsync(a, b), gospec.Equals, nil)
+		c.Expect(len(spilled), gospec.Equals, 1)
+		c.Expect(spilled[0], gospec.Equals, b)
+		c.Expect(ptr.Stats(), gospec.Equals, RedisBatchQueueStats{Len: 1, Cap: 1, Dropped: 0, Spilled: 1})
+	})
+
+	c.Specify("[RedisBatchQueue][TryRunAsync] Never blocks", func() {
+		ptr := &RedisBatchQueue{}
+		_, err := ptr.TryRunAsync(MakeRedisBatchCommandGet("A"))
+		c.Expect(err.Error(), gospec.Equals, "[RedisBatchQueue][TryRunAsync] Queue is closed!")
+
+		ptr.queue = make(chan *RedisBatchCommand, 2)
+		defer close(ptr.queue)
+
+		n, err := ptr.TryRunAsync(MakeRedisBatchCommandGet("A"), nil)
+		c.Expect(n, gospec.Equals, 1)
+		c.Expect(err.Error(), gospec.Equals, "[RedisBatchQueue][TryRunAsync][1] Nil RedisBatchCommand!")
+
+		n, err = ptr.TryRunAsync(MakeRedisBatchCommandGet("B"), MakeRedisBatchCommandGet("C"))
+		c.Expect(n, gospec.Equals, 1)
+		c.Expect(err, gospec.Equals, ErrQueueIsFull)
+
+		// Commands left to the caller are not counted as dropped
+		c.Expect(ptr.Stats().Dropped, gospec.Equals, uint64(0))
+	})
+}
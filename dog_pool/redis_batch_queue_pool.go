@@ -0,0 +1,166 @@
+//
+// Feeding the RedisBatchQueue from a RedisConnectionPool
+//
+// Rather than cloning the Connection for every worker, the queue borrows the connections from the Pool:
+// RedisBatchPoolPerWorker borrows a connection for the lifetime of every worker (Open fails if the pool runs out),
+// RedisBatchPoolPerBatch borrows a connection for every batch & returns it right after, so a few connections serve many workers.
+// A batch that finds the pool empty fails with ErrNoConnectionsAvailable, a connection error that Retry backs off on.
+//
+// A pool with more than one distinct Url is sharded, the commands are routed to the shard of their keys,
+// see redis_batch_queue_shards.go.
+//
+// Usage:
+//   pool := &RedisConnectionPool{Mode: LAZY, Size: 8, Urls: []string{"127.0.0.1:6379"}}
+//   pool.Open()
+//
+//   queue := &RedisBatchQueue{
+//     ...
+//     Pool:     pool,
+//     PoolMode: RedisBatchPoolPerBatch,
+//     Retry:    &RedisBatchRetryPolicy{MaxRetries: 5, Backoff: 10 * time.Millisecond},
+//   }
+//
+
+package dog_pool
+
+import "fmt"
+import "github.com/RUNDSP/radix/redis"
+
+//
+// How long does the queue borrow the connections of the Pool?
+//
+type RedisBatchPoolMode int
+
+const (
+	RedisBatchPoolPerWorker RedisBatchPoolMode = iota // Every worker borrows a connection until it stops
+	RedisBatchPoolPerBatch                            // Every batch borrows a connection until it completes
+)
+
+var redis_batch_pool_mode_names = []string{"PerWorker", "PerBatch"}
+
+func (p RedisBatchPoolMode) String() string {
+	if p < RedisBatchPoolPerWorker || p > RedisBatchPoolPerBatch {
+		return fmt.Sprintf("RedisBatchPoolMode(%d)", int(p))
+	}
+	return redis_batch_pool_mode_names[p]
+}
+
+//
+// Make a new instance of redisBatchQueueWorker borrowing a connection from the pool for every batch, or return an error
+//
+func makeRedisBatchQueuePoolWorker(logger Logger, pool *RedisConnectionPool, batch_size uint, queue <-chan *RedisBatchCommand) (*redisBatchQueueWorker, error) {
+	p := &redisBatchQueueWorker{
+		Logger:       logger,
+		Pool:         pool,
+		CommandQueue: queue,
+		BatchSize:    batch_size,
+	}
+
+	switch {
+	case nil == p.Pool:
+		return nil, fmt.Errorf("[redisBatchQueueWorker][Make] Nil redis connection pool!")
+	case nil == p.CommandQueue:
+		return nil, fmt.Errorf("[redisBatchQueueWorker][Make] Nil queue!")
+	case 0 == p.BatchSize:
+		return nil, fmt.Errorf("[redisBatchQueueWorker][Make] BatchSize must be greater than 0!")
+	default:
+		return p, nil
+	}
+}
+
+//
+// Run the batch on the worker's client, or on connections borrowed from the pool
+//
+func (p *redisBatchQueueWorker) executeBatch(cmds RedisBatchCommands) error {
+	if len(p.Shards) > 1 {
+		return p.executeShardedBatch(cmds)
+	}
+	return p.executeBatchOn(p.Client, "", cmds)
+}
+
+//
+// Run the batch on the client, or on a connection to the url (any url if empty) borrowed from the pool
+//
+func (p *redisBatchQueueWorker) executeBatchOn(client RedisClientInterface, url string, cmds RedisBatchCommands) error {
+	if nil == client {
+		borrowed_client, connection, err := borrowRedisBatchClient(p.Pool, url, p.Namespace)
+		if nil != err {
+			// Fail every command, as if the connection was down
+			for _, cmd := range cmds {
+				cmd.reply = &redis.Reply{Type: redis.ErrorReply, Err: err}
+			}
+			return err
+		}
+		defer p.Pool.Push(connection)
+		client = borrowed_client
+	}
+
+	if p.FailFast {
+		return cmds.ExecuteBatchFailFast(client)
+	}
+	return cmds.ExecuteBatch(client)
+}
+
+//
+// Return the worker's connection(s) to the pool they were borrowed from, or close them
+//
+func (p *redisBatchQueueWorker) close() {
+	for _, connection := range p.ShardConnections {
+		p.Pool.Push(connection)
+	}
+	p.ShardClients = nil
+	p.ShardConnections = nil
+
+	switch {
+	case nil == p.Connection:
+		// Borrows a connection for every batch, nothing to do
+	case nil != p.Pool && p.Pool.IsOpen():
+		p.Pool.Push(p.Connection)
+	default:
+		p.Connection.Close()
+	}
+}
+
+//
+// Borrow a connection to the url (any url if empty) from the pool, namespacing the keys if needed; the caller pushes the connection back
+//
+func borrowRedisBatchClient(pool *RedisConnectionPool, url, namespace string) (RedisClientInterface, *RedisConnection, error) {
+	if pool.IsClosed() {
+		return nil, nil, ErrNoConnectionsAvailable
+	}
+
+	var connection *RedisConnection
+	var err error
+	if "" == url {
+		connection, err = pool.Pop()
+	} else {
+		connection, err = pool.PopUrl(url)
+	}
+	if nil != err {
+		return nil, nil, err
+	}
+
+	if len(namespace) > 0 {
+		return MakeRedisNamespacedClient(namespace, connection), connection, nil
+	}
+	return connection, connection, nil
+}
+
+//
+// Make a worker on a connection borrowed from the Pool until it stops, or return an error
+//
+func (p *RedisBatchQueue) makePoolWorker(queue chan *RedisBatchCommand) (*redisBatchQueueWorker, error) {
+	connection, err := p.Pool.Pop()
+	if nil != err {
+		return nil, err
+	}
+
+	ptr, err := makeRedisBatchQueueWorker(p.Logger, connection, p.WorkersBatchSize, queue)
+	if nil != err {
+		p.Pool.Push(connection)
+		return nil, err
+	}
+
+	ptr.Pool = p.Pool
+	return ptr, nil
+}
@@ -0,0 +1,213 @@
+package dog_pool
+
+import "context"
+import "fmt"
+import "time"
+import "github.com/alecthomas/log4go"
+
+import "testing"
+import "github.com/orfjackal/gospec/src/gospec"
+
+func TestRedisBatchQueuePoolSpecs(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping test in benchmark mode.")
+		return
+	}
+	r := gospec.NewRunner()
+	r.AddSpec(RedisBatchQueuePoolSpecs)
+	gospec.MainGoTest(r, t)
+}
+
+// Lazy pool of connections failing every command with ErrConnectionIsClosed
+func makeTestRedisBatchPool(size int, attempts *int) *RedisConnectionPool {
+	pool := &RedisConnectionPool{Mode: LAZY, Size: size, Urls: []string{"127.0.0.1:6991"}}
+	pool.Hooks = RedisHooks{RedisHookFuncs{Before: func(call *RedisHookCall) error {
+		*attempts++
+		return ErrConnectionIsClosed
+	}}}
+	if err := pool.Open(); nil != err {
+		panic(err)
+	}
+	return pool
+}
+
+func RedisBatchQueuePoolSpecs(c gospec.Context) {
+
+	c.Specify("[RedisBatchPoolMode][String] Names the modes", func() {
+		c.Expect(RedisBatchPoolPerWorker.String(), gospec.Equals, "PerWorker")
+		c.Expect(RedisBatchPoolPerBatch.String(), gospec.Equals, "PerBatch")
+		c.Expect(RedisBatchPoolMode(7).String(), gospec.Equals, "RedisBatchPoolMode(7)")
+	})
+
+	c.Specify("[RedisBatchQueue][Open] Validates the Pool", func() {
+		ptr := &RedisBatchQueue{
+			Pool:             &RedisConnectionPool{},
+			QueueSize:        10,
+			WorkersSize:      1,
+			WorkersBatchSize: 1,
+		}
+		c.Expect(ptr.Open().Error(), gospec.Equals, "[RedisBatchQueue][Open] Pool is closed!")
+
+		attempts := 0
+		ptr.Pool = makeTestRedisBatchPool(1, &attempts)
+		defer ptr.Pool.Close()
+		ptr.PoolMode = RedisBatchPoolMode(7)
+		c.Expect(ptr.Open().Error(), gospec.Equals, "[RedisBatchQueue][Open] Invalid PoolMode: RedisBatchPoolMode(7)!")
+	})
+
+	c.Specify("[RedisBatchQueue][Open] Borrows a connection per worker, and returns them on Close", func() {
+		attempts := 0
+		pool := makeTestRedisBatchPool(2, &attempts)
+		defer pool.Close()
+
+		// Not enough connections for every worker
+		ptr := &RedisBatchQueue{
+			Pool:             pool,
+			QueueSize:        10,
+			WorkersSize:      3,
+			WorkersBatchSize: 1,
+		}
+		c.Expect(ptr.Open(), gospec.Equals, ErrNoConnectionsAvailable)
+		c.Expect(pool.Len(), gospec.Equals, 2)
+
+		ptr.WorkersSize = 2
+		c.Expect(ptr.Open(), gospec.Equals, nil)
+		c.Expect(pool.Len(), gospec.Equals, 0)
+
+		dropped, err := ptr.Close(context.Background())
+		c.Expect(dropped, gospec.Equals, 0)
+		c.Expect(err, gospec.Equals, nil)
+		c.Expect(pool.Len(), gospec.Equals, 2)
+	})
+
+	c.Specify("[redisBatchQueueWorker][runCommands] Borrows a connection per batch", func() {
+		attempts := 0
+		pool := makeTestRedisBatchPool(1, &attempts)
+		defer pool.Close()
+
+		ptr, err := makeRedisBatchQueuePoolWorker(nil, pool, 10, make(chan *RedisBatchCommand))
+		c.Expect(err, gospec.Equals, nil)
+		ptr.Retry = &RedisBatchRetryPolicy{MaxRetries: 1, Backoff: time.Millisecond}
+
+		cmds := RedisBatchCommands{MakeRedisBatchCommandGet("A"), MakeRedisBatchCommandGet("B")}
+		ptr.runCommands(cmds)
+		c.Expect(attempts, gospec.Equals, 4)
+		c.Expect(cmds[0].Reply().Err, gospec.Equals, ErrConnectionIsClosed)
+		c.Expect(pool.Len(), gospec.Equals, 1)
+
+		// The pool is empty, the batch fails without reaching Redis
+		connection, err := pool.Pop()
+		c.Expect(err, gospec.Equals, nil)
+		defer pool.Push(connection)
+
+		cmds = RedisBatchCommands{MakeRedisBatchCommandGet("A")}
+		ptr.runCommands(cmds)
+		c.Expect(attempts, gospec.Equals, 4)
+		c.Expect(cmds[0].Reply().Err, gospec.Equals, ErrNoConnectionsAvailable)
+	})
+
+	c.Specify("[redisBatchQueueWorker][Make] Validates the pool worker", func() {
+		_, err := makeRedisBatchQueuePoolWorker(nil, nil, 10, make(chan *RedisBatchCommand))
+		c.Expect(err.Error(), gospec.Equals, "[redisBatchQueueWorker][Make] Nil redis connection pool!")
+	})
+
+	c.Specify("[RedisBatchQueue] Runs the batches on connections borrowed from the pool", func() {
+		logger := MakeLog4goLogger(log4go.NewDefaultLogger(log4go.CRITICAL))
+		server, err := StartRedisServer(logger)
+		if nil != err {
+			panic(err)
+		}
+		defer server.Close()
+
+		pool := &RedisConnectionPool{Mode: LAZY, Size: 1, Urls: []string{server.Connection().Url}, Logger: logger}
+		c.Expect(pool.Open(), gospec.Equals, nil)
+		defer pool.Close()
+
+		ptr := &RedisBatchQueue{
+			Logger:           logger,
+			Pool:             pool,
+			PoolMode:         RedisBatchPoolPerBatch,
+			QueueSize:        10,
+			WorkersSize:      4,
+			WorkersBatchSize: 5,
+			Namespace:        "ns:",
+			Retry:            &RedisBatchRetryPolicy{MaxRetries: 100, Backoff: time.Millisecond},
+		}
+		c.Expect(ptr.Open(), gospec.Equals, nil)
+
+		cmds := RedisBatchCommands{}
+		for i := 0; i < 20; i++ {
+			cmds = append(cmds, MakeRedisBatchCommandHashIncrementBy("Hash", "Field", 1))
+		}
+		future, err := ptr.RunAsyncFuture(cmds...)
+		c.Expect(err, gospec.Equals, nil)
+		c.Expect(future.Wait(context.Background()), gospec.Equals, nil)
+
+		dropped, err := ptr.Close(context.Background())
+		c.Expect(dropped, gospec.Equals, 0)
+		c.Expect(err, gospec.Equals, nil)
+		c.Expect(pool.Len(), gospec.Equals, 1)
+
+		value, err := server.Connection().Cmd("HGET", "ns:Hash", "Field").Int64()
+		c.Expect(err, gospec.Equals, nil)
+		c.Expect(value, gospec.Equals, int64(20))
+	})
+
+	c.Specify("[RedisBatchQueue] Routes the batches to the shards of a two server pool", func() {
+		logger := MakeLog4goLogger(log4go.NewDefaultLogger(log4go.CRITICAL))
+		servers := make([]*RedisServerProcess, 2)
+		urls := make([]string, 2)
+		for i := range servers {
+			server, err := StartRedisServer(logger)
+			if nil != err {
+				panic(err)
+			}
+			defer server.Close()
+			servers[i] = server
+			urls[i] = server.Connection().Url
+		}
+
+		for _, mode := range []RedisBatchPoolMode{RedisBatchPoolPerBatch, RedisBatchPoolPerWorker} {
+			pool := &RedisConnectionPool{Mode: LAZY, Size: 4, Urls: urls, Logger: logger}
+			c.Expect(pool.Open(), gospec.Equals, nil)
+
+			ptr := &RedisBatchQueue{
+				Logger:           logger,
+				Pool:             pool,
+				PoolMode:         mode,
+				QueueSize:        100,
+				WorkersSize:      2,
+				WorkersBatchSize: 10,
+				Namespace:        "ns:",
+			}
+			c.Expect(ptr.Open(), gospec.Equals, nil)
+
+			cmds := RedisBatchCommands{}
+			for i := 0; i < 20; i++ {
+				cmds = append(cmds, MakeRedisBatchCommandIncrementBy(fmt.Sprintf("Key%v", i), 1))
+			}
+			future, err := ptr.RunAsyncFuture(cmds...)
+			c.Expect(err, gospec.Equals, nil)
+			c.Expect(future.Wait(context.Background()), gospec.Equals, nil)
+
+			dropped, err := ptr.Close(context.Background())
+			c.Expect(dropped, gospec.Equals, 0)
+			c.Expect(err, gospec.Equals, nil)
+			c.Expect(pool.Len(), gospec.Equals, 4)
+			pool.Close()
+		}
+
+		// Every key is only on its shard, once per mode
+		for i := 0; i < 20; i++ {
+			key := fmt.Sprintf("Key%v", i)
+			shard := redisShardOf(key, 2)
+
+			value, err := servers[shard].Connection().Cmd("GET", "ns:"+key).Int64()
+			c.Expect(err, gospec.Equals, nil)
+			c.Expect(value, gospec.Equals, int64(2))
+
+			exists, _ := servers[1-shard].Connection().Cmd("EXISTS", "ns:"+key).Int64()
+			c.Expect(exists, gospec.Equals, int64(0))
+		}
+	})
+}
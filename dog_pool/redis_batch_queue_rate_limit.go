@@ -0,0 +1,215 @@
+//
+// Rate limiting the RedisBatchQueue
+//
+// Token buckets, one for the commands & one for the batches per second, that the workers wait on before running every batch.
+// Each bucket holds up to one second of tokens, a rate of 0 is unlimited; the rates can be changed while the queue is open.
+//
+// Usage:
+//   limit := MakeRedisBatchRateLimiter(5000, 100)
+//
+//   queue := &RedisBatchQueue{
+//     ...
+//     RateLimit: limit,
+//   }
+//
+//   // Backfill at full speed overnight
+//   limit.SetCommandsPerSecond(50000)
+//
+//   // How long the workers were throttled for
+//   queue.Stats().Throttled
+//
+
+package dog_pool
+
+import "fmt"
+import "sync"
+import "time"
+
+//
+// Token bucket limiting the commands & batches per second
+//
+type RedisBatchRateLimiter struct {
+	mutex     sync.Mutex
+	commands  redisTokenBucket
+	batches   redisTokenBucket
+	throttled time.Duration "Total time spent waiting on the limit"
+}
+
+//
+// Make a new instance of RedisBatchRateLimiter, a rate of 0 is unlimited
+//
+func MakeRedisBatchRateLimiter(commands_per_second, batches_per_second float64) *RedisBatchRateLimiter {
+	now := time.Now()
+	p := &RedisBatchRateLimiter{}
+	p.commands.setRate(now, commands_per_second)
+	p.batches.setRate(now, batches_per_second)
+	return p
+}
+
+func (p *RedisBatchRateLimiter) String() string {
+	return fmt.Sprintf("RedisBatchRateLimiter { CommandsPerSecond=%v, BatchesPerSecond=%v, Throttled=%v }", p.CommandsPerSecond(), p.BatchesPerSecond(), p.Throttled())
+}
+
+//
+// Limit of commands per second, 0 is unlimited
+//
+func (p *RedisBatchRateLimiter) CommandsPerSecond() float64 {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	return p.commands.rate
+}
+
+//
+// Limit of batches per second, 0 is unlimited
+//
+func (p *RedisBatchRateLimiter) BatchesPerSecond() float64 {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	return p.batches.rate
+}
+
+//
+// Change the limit of commands per second, 0 is unlimited
+//
+func (p *RedisBatchRateLimiter) SetCommandsPerSecond(rate float64) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	p.commands.setRate(time.Now(), rate)
+}
+
+//
+// Change the limit of batches per second, 0 is unlimited
+//
+func (p *RedisBatchRateLimiter) SetBatchesPerSecond(rate float64) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	p.batches.setRate(time.Now(), rate)
+}
+
+//
+// Total time spent waiting on the limit
+//
+func (p *RedisBatchRateLimiter) Throttled() time.Duration {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	return p.throttled
+}
+
+//
+// Block until a batch of n commands fits in the limit, or cancel is closed (i.e. the queue is closing)
+//
+// Returns:
+//   How long we actually waited, which is added to Throttled
+//
+func (p *RedisBatchRateLimiter) Wait(commands int, cancel <-chan struct{}) time.Duration {
+	started := time.Now()
+	delay := p.reserve(started, commands)
+	if delay <= 0 {
+		return 0
+	}
+
+	timer := time.NewTimer(delay)
+	select {
+	case <-timer.C:
+	case <-cancel:
+		timer.Stop()
+	}
+
+	waited := time.Since(started)
+	p.mutex.Lock()
+	p.throttled += waited
+	p.mutex.Unlock()
+	return waited
+}
+
+//
+// Take the tokens for a batch of n commands, returns how long to wait before running it
+//
+func (p *RedisBatchRateLimiter) reserve(now time.Time, commands int) time.Duration {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	delay := p.commands.reserve(now, float64(commands))
+	if batch_delay := p.batches.reserve(now, 1); batch_delay > delay {
+		delay = batch_delay
+	}
+	return delay
+}
+
+//
+// Tokens refilled at a steady rate, up to one second's worth
+//
+type redisTokenBucket struct {
+	rate   float64   "Tokens added every second, 0 is unlimited"
+	tokens float64   "Tokens available, negative once reserved ahead of the refill"
+	last   time.Time "When the tokens were last refilled"
+}
+
+//
+// Most tokens the bucket holds
+//
+func (p *redisTokenBucket) burst() float64 {
+	if p.rate < 1 {
+		return 1
+	}
+	return p.rate
+}
+
+func (p *redisTokenBucket) refill(now time.Time) {
+	if p.rate > 0 && now.After(p.last) {
+		p.tokens += now.Sub(p.last).Seconds() * p.rate
+		if burst := p.burst(); p.tokens > burst {
+			p.tokens = burst
+		}
+	}
+	p.last = now
+}
+
+//
+// Change the rate, a new bucket starts full
+//
+func (p *redisTokenBucket) setRate(now time.Time, rate float64) {
+	if rate < 0 {
+		rate = 0
+	}
+
+	p.refill(now)
+	was_unlimited := 0 == p.rate
+	p.rate = rate
+
+	switch burst := p.burst(); {
+	case 0 == rate:
+		p.tokens = 0
+	case was_unlimited || p.tokens > burst:
+		p.tokens = burst
+	}
+}
+
+//
+// Take n tokens, returns how long until the bucket is back to 0
+//
+func (p *redisTokenBucket) reserve(now time.Time, n float64) time.Duration {
+	p.refill(now)
+	if p.rate <= 0 {
+		return 0
+	}
+
+	p.tokens -= n
+	if p.tokens >= 0 {
+		return 0
+	}
+	return time.Duration(-p.tokens / p.rate * float64(time.Second))
+}
+
+//
+// Wait on the RateLimit (if any) before running a batch of n commands
+//
+func (p *redisBatchQueueWorker) throttle(commands int) {
+	if nil == p.RateLimit {
+		return
+	}
+
+	if delay := p.RateLimit.Wait(commands, p.Closing); delay > 0 {
+		logAt(p.Logger, LogDebug, "[redisBatchQueueWorker][Throttle] Waited %v to run %v commands", delay, commands)
+	}
+}
@@ -0,0 +1,104 @@
+package dog_pool
+
+import "time"
+
+import "testing"
+import "github.com/orfjackal/gospec/src/gospec"
+
+func TestRedisBatchRateLimiterSpecs(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping test in benchmark mode.")
+		return
+	}
+	r := gospec.NewRunner()
+	r.AddSpec(RedisBatchRateLimiterSpecs)
+	gospec.MainGoTest(r, t)
+}
+
+func RedisBatchRateLimiterSpecs(c gospec.Context) {
+
+	c.Specify("[RedisBatchRateLimiter][reserve] Limits the commands per second", func() {
+		ptr := MakeRedisBatchRateLimiter(10, 0)
+		now := ptr.commands.last
+
+		// A full second's worth of tokens to start with
+		c.Expect(ptr.reserve(now, 10), gospec.Equals, time.Duration(0))
+		c.Expect(ptr.reserve(now, 5), gospec.Equals, 500*time.Millisecond)
+		c.Expect(ptr.reserve(now, 5), gospec.Equals, time.Second)
+
+		// Only the time actually spent waiting is throttled
+		c.Expect(ptr.Throttled(), gospec.Equals, time.Duration(0))
+
+		// Refilled, up to a second's worth
+		now = now.Add(time.Minute)
+		c.Expect(ptr.reserve(now, 10), gospec.Equals, time.Duration(0))
+		c.Expect(ptr.reserve(now, 1), gospec.Equals, 100*time.Millisecond)
+	})
+
+	c.Specify("[RedisBatchRateLimiter][reserve] Limits the batches per second", func() {
+		ptr := MakeRedisBatchRateLimiter(0, 2)
+		now := ptr.batches.last
+
+		c.Expect(ptr.reserve(now, 1000), gospec.Equals, time.Duration(0))
+		c.Expect(ptr.reserve(now, 1000), gospec.Equals, time.Duration(0))
+		c.Expect(ptr.reserve(now, 1), gospec.Equals, 500*time.Millisecond)
+	})
+
+	c.Specify("[RedisBatchRateLimiter] Changes the limits at runtime", func() {
+		ptr := &RedisBatchRateLimiter{}
+		c.Expect(ptr.CommandsPerSecond(), gospec.Equals, float64(0))
+		c.Expect(ptr.Wait(1000000, nil), gospec.Equals, time.Duration(0))
+
+		ptr.SetCommandsPerSecond(100)
+		ptr.SetBatchesPerSecond(0.5)
+		c.Expect(ptr.CommandsPerSecond(), gospec.Equals, float64(100))
+		c.Expect(ptr.BatchesPerSecond(), gospec.Equals, 0.5)
+
+		now := ptr.commands.last
+		c.Expect(ptr.reserve(now, 100), gospec.Equals, time.Duration(0))
+		c.Expect(ptr.reserve(now, 100), gospec.Equals, 2*time.Second)
+
+		// Unlimited again
+		ptr.SetCommandsPerSecond(0)
+		ptr.SetBatchesPerSecond(0)
+		c.Expect(ptr.reserve(now, 100), gospec.Equals, time.Duration(0))
+		c.Expect(ptr.String(), gospec.Equals, "RedisBatchRateLimiter { CommandsPerSecond=0, BatchesPerSecond=0, Throttled=0s }")
+	})
+
+	c.Specify("[RedisBatchRateLimiter][Wait] Cancelled waits only count the time waited", func() {
+		ptr := MakeRedisBatchRateLimiter(1, 0)
+		c.Expect(ptr.Wait(1, nil), gospec.Equals, time.Duration(0))
+
+		cancel := make(chan struct{})
+		go func() {
+			time.Sleep(10 * time.Millisecond)
+			close(cancel)
+		}()
+
+		// An hour's worth of commands over the limit
+		waited := ptr.Wait(3600, cancel)
+		c.Expect(waited >= 10*time.Millisecond, gospec.Equals, true)
+		c.Expect(waited < time.Minute, gospec.Equals, true)
+		c.Expect(ptr.Throttled(), gospec.Equals, waited)
+	})
+
+	c.Specify("[redisBatchQueueWorker][runCommands] Waits on the RateLimit", func() {
+		client := &recordingRedisClient{}
+		ptr, err := makeRedisBatchQueueWorker(nil, &RedisConnection{}, 10, make(chan *RedisBatchCommand))
+		c.Expect(err, gospec.Equals, nil)
+		ptr.Client = client
+		ptr.RateLimit = MakeRedisBatchRateLimiter(20, 0)
+
+		// 2 commands over the burst
+		started := time.Now()
+		for i := 0; i < 11; i++ {
+			ptr.runCommands(RedisBatchCommands{MakeRedisBatchCommandGet("A"), MakeRedisBatchCommandGet("B")})
+		}
+		c.Expect(len(client.cmds), gospec.Equals, 22)
+
+		queue := &RedisBatchQueue{RateLimit: ptr.RateLimit}
+		throttled := queue.Stats().Throttled
+		c.Expect(throttled > 0, gospec.Equals, true)
+		c.Expect(time.Since(started) >= throttled, gospec.Equals, true)
+	})
+}
@@ -0,0 +1,267 @@
+//
+// Retries & dead-letters for the RedisBatchQueue
+//
+// Commands that fail with a connection error (see IsRedisConnectionError) are retried with backoff,
+// commands that exhaust their retries are handed to the DeadLetter sink.
+// Retries are at-least-once: a command may have reached Redis before its connection failed.
+// A timed out command most likely ran, so only the idempotent ones are retried; counters (INCRBY, HINCRBY, ...)
+// & pushes (LPUSH, RPUSH, ...) that timed out are dead-lettered instead.
+// Close cuts the backoff short, the commands still failing are dead-lettered.
+//
+// Usage:
+//   dead_letters, err := OpenRedisDeadLetterFile("/var/spool/app/redis.dead")
+//   queue := &RedisBatchQueue{
+//     ...
+//     Retry:      &RedisBatchRetryPolicy{MaxRetries: 5, Backoff: 50 * time.Millisecond, MaxBackoff: 2 * time.Second},
+//     DeadLetter: dead_letters,
+//   }
+//
+//   // Later, once Redis is back:
+//   cmds, err := ReadRedisDeadLetterFile("/var/spool/app/redis.dead")
+//   err = queue.RunAsync(cmds...)
+//
+
+package dog_pool
+
+import "bufio"
+import "encoding/json"
+import "fmt"
+import "math"
+import "os"
+import "strings"
+import "sync"
+import "time"
+
+//
+// How often & how long to wait before retrying a command that failed with a connection error
+//
+type RedisBatchRetryPolicy struct {
+	MaxRetries int           "How many times a command is retried"
+	Backoff    time.Duration "Delay before the first retry, doubled for every retry after that"
+	MaxBackoff time.Duration "(optional) Upper bound on the delay"
+}
+
+func (p *RedisBatchRetryPolicy) String() string {
+	return fmt.Sprintf("RedisBatchRetryPolicy { MaxRetries=%v, Backoff=%v, MaxBackoff=%v }", p.MaxRetries, p.Backoff, p.MaxBackoff)
+}
+
+//
+// Delay before the retry, attempt starts at 1
+//
+func (p *RedisBatchRetryPolicy) Delay(attempt int) time.Duration {
+	delay := p.Backoff
+	for i := 1; i < attempt && delay < math.MaxInt64/2; i++ {
+		delay *= 2
+	}
+
+	if p.MaxBackoff > 0 && delay > p.MaxBackoff {
+		return p.MaxBackoff
+	}
+	return delay
+}
+
+//
+// Commands that change Redis every time they run, they are not retried after a timeout
+//
+var redisNonIdempotentCommands = map[string]bool{
+	"APPEND":       true,
+	"BLMOVE":       true,
+	"BRPOPLPUSH":   true,
+	"DECR":         true,
+	"DECRBY":       true,
+	"EVAL":         true,
+	"EVALSHA":      true,
+	"GETDEL":       true,
+	"HINCRBY":      true,
+	"HINCRBYFLOAT": true,
+	"INCR":         true,
+	"INCRBY":       true,
+	"INCRBYFLOAT":  true,
+	"LMOVE":        true,
+	"LPOP":         true,
+	"LPUSH":        true,
+	"LPUSHX":       true,
+	"RPOP":         true,
+	"RPOPLPUSH":    true,
+	"RPUSH":        true,
+	"RPUSHX":       true,
+	"SPOP":         true,
+	"XADD":         true,
+	"ZINCRBY":      true,
+	"ZPOPMAX":      true,
+	"ZPOPMIN":      true,
+}
+
+//
+// Can the failed command be retried?
+//
+// Returns:
+//   true  --> Failed with a connection error, or an idempotent command timed out
+//   false --> Succeeded, failed with a command error, or a non-idempotent command timed out (it may have run)
+//
+func isRedisRetryable(cmd *RedisBatchCommand) bool {
+	reply := cmd.Reply()
+	if nil == reply {
+		return false
+	}
+
+	switch RedisErrorClass(reply.Err) {
+	case RedisErrorClassConnection:
+		return true
+	case RedisErrorClassTimeout:
+		return !redisNonIdempotentCommands[strings.ToUpper(cmd.cmd)]
+	default:
+		return false
+	}
+}
+
+//
+// Receives the commands that exhausted their retries
+//
+type RedisDeadLetterSink interface {
+	DeadLetter(cmd *RedisBatchCommand, err error) error
+}
+
+//
+// Adapter to use a function as a RedisDeadLetterSink
+//
+type RedisDeadLetterFunc func(cmd *RedisBatchCommand, err error) error
+
+func (f RedisDeadLetterFunc) DeadLetter(cmd *RedisBatchCommand, err error) error {
+	return f(cmd, err)
+}
+
+//
+// ==================================================
+//
+// Append-only dead-letter file:
+//
+// ==================================================
+//
+
+//
+// Dead-letters every command as a line of JSON in an append-only file, see ReadRedisDeadLetterFile
+//
+type RedisDeadLetterFile struct {
+	Path string "Path of the file"
+
+	mutex sync.Mutex
+	file  *os.File
+}
+
+//
+// A single line in the dead-letter file
+//
+type redisDeadLetterRecord struct {
+	At    time.Time `json:"at"`
+	Cmd   string    `json:"cmd"`
+	Args  [][]byte  `json:"args"`
+	Error string    `json:"error"`
+}
+
+//
+// Serialize the command as a single line of JSON, including the trailing newline
+//
+func marshalRedisDeadLetterRecord(cmd *RedisBatchCommand, err error) ([]byte, error) {
+	record := redisDeadLetterRecord{At: time.Now(), Cmd: cmd.cmd, Args: cmd.args}
+	if nil != err {
+		record.Error = err.Error()
+	}
+
+	line, json_err := json.Marshal(record)
+	if nil != json_err {
+		return nil, json_err
+	}
+	return append(line, '\n'), nil
+}
+
+//
+// Rebuild the command from a line of JSON
+//
+func unmarshalRedisDeadLetterRecord(line []byte) (*RedisBatchCommand, error) {
+	var record redisDeadLetterRecord
+	if err := json.Unmarshal(line, &record); nil != err {
+		return nil, err
+	}
+
+	cmd := MakeRedisBatchCommand(record.Cmd)
+	for _, arg := range record.Args {
+		cmd.WriteArg(arg)
+	}
+	return cmd, nil
+}
+
+//
+// Open (or create) the dead-letter file for appending
+//
+func OpenRedisDeadLetterFile(path string) (*RedisDeadLetterFile, error) {
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0644)
+	if nil != err {
+		return nil, err
+	}
+	return &RedisDeadLetterFile{Path: path, file: file}, nil
+}
+
+func (p *RedisDeadLetterFile) String() string {
+	return fmt.Sprintf("RedisDeadLetterFile { Path=%v }", p.Path)
+}
+
+//
+// Append the command to the file
+//
+func (p *RedisDeadLetterFile) DeadLetter(cmd *RedisBatchCommand, err error) error {
+	line, json_err := marshalRedisDeadLetterRecord(cmd, err)
+	if nil != json_err {
+		return json_err
+	}
+
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	if nil == p.file {
+		return fmt.Errorf("[RedisDeadLetterFile][DeadLetter] File is closed!")
+	}
+
+	_, write_err := p.file.Write(line)
+	return write_err
+}
+
+//
+// Close the file
+//
+func (p *RedisDeadLetterFile) Close() error {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	if nil == p.file {
+		return nil
+	}
+
+	err := p.file.Close()
+	p.file = nil
+	return err
+}
+
+//
+// Read the commands back from a dead-letter file, i.e. to replay them once Redis is back
+//
+func ReadRedisDeadLetterFile(path string) (RedisBatchCommands, error) {
+	file, err := os.Open(path)
+	if nil != err {
+		return nil, err
+	}
+	defer file.Close()
+
+	output := RedisBatchCommands{}
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 64*1024), 64*1024*1024)
+	for line := 1; scanner.Scan(); line++ {
+		cmd, err := unmarshalRedisDeadLetterRecord(scanner.Bytes())
+		if nil != err {
+			return output, fmt.Errorf("[ReadRedisDeadLetterFile][%v:%v] %v", path, line, err)
+		}
+		output = append(output, cmd)
+	}
+
+	return output, scanner.Err()
+}
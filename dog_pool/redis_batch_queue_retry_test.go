@@ -0,0 +1,163 @@
+package dog_pool
+
+import "errors"
+import "io/ioutil"
+import "net"
+import "os"
+import "path/filepath"
+import "time"
+
+import "testing"
+import "github.com/orfjackal/gospec/src/gospec"
+
+func TestRedisBatchQueueRetrySpecs(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping test in benchmark mode.")
+		return
+	}
+	r := gospec.NewRunner()
+	r.AddSpec(RedisBatchQueueRetrySpecs)
+	gospec.MainGoTest(r, t)
+}
+
+func RedisBatchQueueRetrySpecs(c gospec.Context) {
+
+	c.Specify("[RedisBatchRetryPolicy][Delay] Doubles up to the MaxBackoff", func() {
+		policy := &RedisBatchRetryPolicy{MaxRetries: 5, Backoff: 10 * time.Millisecond, MaxBackoff: 50 * time.Millisecond}
+		c.Expect(policy.Delay(1), gospec.Equals, 10*time.Millisecond)
+		c.Expect(policy.Delay(2), gospec.Equals, 20*time.Millisecond)
+		c.Expect(policy.Delay(3), gospec.Equals, 40*time.Millisecond)
+		c.Expect(policy.Delay(4), gospec.Equals, 50*time.Millisecond)
+		c.Expect(policy.Delay(100), gospec.Equals, 50*time.Millisecond)
+
+		// Never overflows
+		policy.MaxBackoff = 0
+		c.Expect(policy.Delay(100) > 0, gospec.Equals, true)
+	})
+
+	c.Specify("[RedisDeadLetterFile] Round trips the commands", func() {
+		dir, err := ioutil.TempDir("", "dog_pool")
+		c.Expect(err, gospec.Equals, nil)
+		defer os.RemoveAll(dir)
+
+		path := filepath.Join(dir, "redis.dead")
+		file, err := OpenRedisDeadLetterFile(path)
+		c.Expect(err, gospec.Equals, nil)
+
+		c.Expect(file.DeadLetter(MakeRedisBatchCommandHashIncrementBy("Hash", "Field A", 10), ErrConnectionIsClosed), gospec.Equals, nil)
+		c.Expect(file.DeadLetter(MakeRedisBatchCommandSet("Key", []byte{0, 1, 2, 255}), nil), gospec.Equals, nil)
+		c.Expect(file.Close(), gospec.Equals, nil)
+
+		err = file.DeadLetter(MakeRedisBatchCommandGet("Key"), nil)
+		c.Expect(err.Error(), gospec.Equals, "[RedisDeadLetterFile][DeadLetter] File is closed!")
+
+		cmds, err := ReadRedisDeadLetterFile(path)
+		c.Expect(err, gospec.Equals, nil)
+		c.Expect(len(cmds), gospec.Equals, 2)
+		c.Expect(cmds[0].GetCmd(), gospec.Equals, "HINCRBY")
+		c.Expect(cmds[0].GetArgs(), gospec.Equals, []string{"Hash", "Field A", "10"})
+		c.Expect(cmds[1].GetCmd(), gospec.Equals, "SET")
+		c.Expect(cmds[1].args[1], gospec.Equals, []byte{0, 1, 2, 255})
+	})
+
+	c.Specify("[redisBatchQueueWorker][runCommands] Retries connection errors, then dead-letters them", func() {
+		attempts := 0
+		connection := &RedisConnection{Url: "127.0.0.1:6991"}
+		connection.Hooks = RedisHooks{RedisHookFuncs{Before: func(call *RedisHookCall) error {
+			attempts++
+			return ErrConnectionIsClosed
+		}}}
+
+		ptr, err := makeRedisBatchQueueWorker(nil, connection, 10, make(chan *RedisBatchCommand))
+		c.Expect(err, gospec.Equals, nil)
+		ptr.Retry = &RedisBatchRetryPolicy{MaxRetries: 2, Backoff: time.Millisecond}
+
+		dead_letters := RedisBatchCommands{}
+		ptr.DeadLetter = RedisDeadLetterFunc(func(cmd *RedisBatchCommand, err error) error {
+			c.Expect(err, gospec.Equals, ErrConnectionIsClosed)
+			dead_letters = append(dead_letters, cmd)
+			return nil
+		})
+
+		cmds := RedisBatchCommands{MakeRedisBatchCommandGet("A"), MakeRedisBatchCommandGet("B")}
+		ptr.runCommands(cmds)
+		c.Expect(attempts, gospec.Equals, 6)
+		c.Expect(dead_letters, gospec.Equals, cmds)
+	})
+
+	c.Specify("[redisBatchQueueWorker][runCommands] Command errors are neither retried nor dead-lettered", func() {
+		attempts := 0
+		connection := &RedisConnection{Url: "127.0.0.1:6991"}
+		connection.Hooks = RedisHooks{RedisHookFuncs{Before: func(call *RedisHookCall) error {
+			attempts++
+			return errors.New("WRONGTYPE Operation against a key holding the wrong kind of value")
+		}}}
+
+		ptr, err := makeRedisBatchQueueWorker(nil, connection, 10, make(chan *RedisBatchCommand))
+		c.Expect(err, gospec.Equals, nil)
+		ptr.Retry = &RedisBatchRetryPolicy{MaxRetries: 2, Backoff: time.Millisecond}
+		ptr.DeadLetter = RedisDeadLetterFunc(func(cmd *RedisBatchCommand, err error) error {
+			c.Expect("Dead-lettered a command error", gospec.Equals, "")
+			return nil
+		})
+
+		ptr.runCommands(RedisBatchCommands{MakeRedisBatchCommandGet("A")})
+		c.Expect(attempts, gospec.Equals, 1)
+	})
+
+	c.Specify("[redisBatchQueueWorker][runCommands] Only the idempotent commands are retried after a timeout", func() {
+		attempts := map[string]int{}
+		connection := &RedisConnection{Url: "127.0.0.1:6991"}
+		connection.Hooks = RedisHooks{RedisHookFuncs{Before: func(call *RedisHookCall) error {
+			attempts[call.Cmd]++
+			return &net.DNSError{IsTimeout: true}
+		}}}
+
+		ptr, err := makeRedisBatchQueueWorker(nil, connection, 10, make(chan *RedisBatchCommand))
+		c.Expect(err, gospec.Equals, nil)
+		ptr.Retry = &RedisBatchRetryPolicy{MaxRetries: 2, Backoff: time.Millisecond}
+
+		dead_letters := RedisBatchCommands{}
+		ptr.DeadLetter = RedisDeadLetterFunc(func(cmd *RedisBatchCommand, err error) error {
+			dead_letters = append(dead_letters, cmd)
+			return nil
+		})
+
+		get, incr := MakeRedisBatchCommandGet("A"), MakeRedisBatchCommandIncrementBy("B", 1)
+		ptr.runCommands(RedisBatchCommands{get, incr})
+		c.Expect(attempts["GET"], gospec.Equals, 3)
+		c.Expect(attempts["INCRBY"], gospec.Equals, 1)
+		c.Expect(dead_letters, gospec.Equals, RedisBatchCommands{get})
+		c.Expect(isRedisRetryable(incr), gospec.Equals, false)
+	})
+
+	c.Specify("[redisBatchQueueWorker][runCommands] Closing cuts the retry backoff short", func() {
+		attempts := 0
+		connection := &RedisConnection{Url: "127.0.0.1:6991"}
+		connection.Hooks = RedisHooks{RedisHookFuncs{Before: func(call *RedisHookCall) error {
+			attempts++
+			return ErrConnectionIsClosed
+		}}}
+
+		closing := make(chan struct{})
+		close(closing)
+
+		ptr, err := makeRedisBatchQueueWorker(nil, connection, 10, make(chan *RedisBatchCommand))
+		c.Expect(err, gospec.Equals, nil)
+		ptr.Retry = &RedisBatchRetryPolicy{MaxRetries: 5, Backoff: time.Hour}
+		ptr.Closing = closing
+
+		dead_letters := RedisBatchCommands{}
+		ptr.DeadLetter = RedisDeadLetterFunc(func(cmd *RedisBatchCommand, err error) error {
+			dead_letters = append(dead_letters, cmd)
+			return nil
+		})
+
+		cmds := RedisBatchCommands{MakeRedisBatchCommandGet("A")}
+		started := time.Now()
+		ptr.runCommands(cmds)
+		c.Expect(time.Since(started) < time.Second, gospec.Equals, true)
+		c.Expect(attempts, gospec.Equals, 1)
+		c.Expect(dead_letters, gospec.Equals, cmds)
+	})
+}
@@ -0,0 +1,136 @@
+//
+// Routing the RedisBatchQueue's commands to the shards of a RedisConnectionPool
+//
+// A pool with more than one distinct Url is sharded, every distinct Url is a shard (in the order of Urls).
+// Each command runs on the shard of its keys, every batch is split by shard & runs on one connection per shard:
+//   CRC32(key) % shards --> Index of the shard
+//   {user:1}:name        --> Only the hash tag "user:1" is hashed, so related keys share a shard
+//
+// Keys are hashed before the Namespace is added. Commands without keys (PING) & unknown commands run on the first shard,
+// commands whose keys span shards fail with ErrCrossShardCommand without reaching Redis.
+//
+// Usage:
+//   pool := &RedisConnectionPool{Mode: LAZY, Size: 8, Urls: []string{"10.0.0.1:6379", "10.0.0.2:6379"}}
+//   pool.Open()
+//
+//   queue := &RedisBatchQueue{
+//     ...
+//     Pool:     pool,
+//     PoolMode: RedisBatchPoolPerBatch,
+//   }
+//
+
+package dog_pool
+
+import "hash/crc32"
+import "strings"
+import "github.com/RUNDSP/radix/redis"
+
+//
+// Distinct Urls of the pool, in order; more than one means the pool is sharded
+//
+func redisPoolShards(pool *RedisConnectionPool) []string {
+	var shards []string
+	seen := make(map[string]bool, len(pool.Urls))
+	for _, url := range pool.Urls {
+		if !seen[url] {
+			seen[url] = true
+			shards = append(shards, url)
+		}
+	}
+	return shards
+}
+
+//
+// Index of the shard the key belongs to, hashing only the {hash tag} if the key has one
+//
+func redisShardOf(key string, shards int) int {
+	if start := strings.Index(key, "{"); start >= 0 {
+		if end := strings.Index(key[start+1:], "}"); end > 0 {
+			key = key[start+1 : start+1+end]
+		}
+	}
+	return int(crc32.ChecksumIEEE([]byte(key)) % uint32(shards))
+}
+
+//
+// Index of the shard the command runs on
+//
+// Returns:
+//   i, nil                  --> Shard of the command's keys, 0 for commands without keys & unknown commands
+//   0, ErrCrossShardCommand --> The keys belong to different shards
+//
+func redisCommandShard(cmd *RedisBatchCommand, shards int) (int, error) {
+	keys, _ := redisCommandKeys(cmd)
+	if 0 == len(keys) {
+		return 0, nil
+	}
+
+	shard := redisShardOf(keys[0], shards)
+	for _, key := range keys[1:] {
+		if redisShardOf(key, shards) != shard {
+			return 0, ErrCrossShardCommand
+		}
+	}
+	return shard, nil
+}
+
+//
+// Split the commands by shard, keeping their order;
+// commands whose keys span shards are failed with ErrCrossShardCommand & left out
+//
+func (commands RedisBatchCommands) splitByShard(shards int) []RedisBatchCommands {
+	output := make([]RedisBatchCommands, shards)
+	for _, cmd := range commands {
+		shard, err := redisCommandShard(cmd, shards)
+		if nil != err {
+			cmd.reply = &redis.Reply{Type: redis.ErrorReply, Err: err}
+			continue
+		}
+		output[shard] = append(output[shard], cmd)
+	}
+	return output
+}
+
+//
+// Run each shard's commands on the worker's connection to the shard, or on a connection borrowed from the pool
+//
+func (p *redisBatchQueueWorker) executeShardedBatch(cmds RedisBatchCommands) error {
+	var err error
+	for shard, shard_cmds := range cmds.splitByShard(len(p.Shards)) {
+		if 0 == len(shard_cmds) {
+			continue
+		}
+
+		var client RedisClientInterface
+		if len(p.ShardClients) > 0 {
+			client = p.ShardClients[shard]
+		}
+
+		if shard_err := p.executeBatchOn(client, p.Shards[shard], shard_cmds); nil != shard_err && nil == err {
+			err = shard_err
+		}
+	}
+	return err
+}
+
+//
+// Borrow a connection to every shard from the pool, for the lifetime of the worker
+//
+func (p *RedisBatchQueue) makeShardedPoolWorker(queue chan *RedisBatchCommand, shards []string) (*redisBatchQueueWorker, error) {
+	ptr, err := makeRedisBatchQueuePoolWorker(p.Logger, p.Pool, p.WorkersBatchSize, queue)
+	if nil != err {
+		return nil, err
+	}
+
+	for _, url := range shards {
+		client, connection, err := borrowRedisBatchClient(p.Pool, url, p.Namespace)
+		if nil != err {
+			ptr.close()
+			return nil, err
+		}
+		ptr.ShardClients = append(ptr.ShardClients, client)
+		ptr.ShardConnections = append(ptr.ShardConnections, connection)
+	}
+	return ptr, nil
+}
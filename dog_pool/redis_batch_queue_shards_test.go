@@ -0,0 +1,156 @@
+package dog_pool
+
+import "context"
+import "fmt"
+import "hash/crc32"
+import "sync"
+
+import "testing"
+import "github.com/orfjackal/gospec/src/gospec"
+
+func TestRedisBatchQueueShardsSpecs(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping test in benchmark mode.")
+		return
+	}
+	r := gospec.NewRunner()
+	r.AddSpec(RedisBatchQueueShardsSpecs)
+	gospec.MainGoTest(r, t)
+}
+
+// Lazy pool over the urls failing every command with ErrConnectionIsClosed, recording the url every key was sent to
+func makeTestRedisShardedPool(size int, urls []string, sent_to map[string]string, mutex *sync.Mutex) *RedisConnectionPool {
+	pool := &RedisConnectionPool{Mode: LAZY, Size: size, Urls: urls}
+	pool.Hooks = RedisHooks{RedisHookFuncs{Before: func(call *RedisHookCall) error {
+		mutex.Lock()
+		defer mutex.Unlock()
+		sent_to[fmt.Sprintf("%s", call.Args[0])] = call.Connection.Url
+		return ErrConnectionIsClosed
+	}}}
+	if err := pool.Open(); nil != err {
+		panic(err)
+	}
+	return pool
+}
+
+func RedisBatchQueueShardsSpecs(c gospec.Context) {
+	urls := []string{"127.0.0.1:6991", "127.0.0.1:6992"}
+
+	c.Specify("[redisPoolShards] Distinct Urls in order", func() {
+		pool := &RedisConnectionPool{Urls: []string{"B", "A", "B", "C", "A"}}
+		c.Expect(redisPoolShards(pool), gospec.Equals, []string{"B", "A", "C"})
+	})
+
+	c.Specify("[redisShardOf] Hashes the hash tag of the key", func() {
+		c.Expect(redisShardOf("{user:1}:name", 16), gospec.Equals, redisShardOf("user:1", 16))
+		c.Expect(redisShardOf("{user:1}:visits", 16), gospec.Equals, redisShardOf("user:1", 16))
+
+		// Empty & unclosed tags hash the whole key
+		c.Expect(redisShardOf("{}:name", 16), gospec.Equals, int(crc32.ChecksumIEEE([]byte("{}:name"))%16))
+		c.Expect(redisShardOf("{user:1", 16), gospec.Equals, int(crc32.ChecksumIEEE([]byte("{user:1"))%16))
+
+		shards := map[int]bool{}
+		for i := 0; i < 100; i++ {
+			shard := redisShardOf(fmt.Sprintf("Key%v", i), 4)
+			c.Expect(shard, gospec.Satisfies, shard >= 0 && shard < 4)
+			shards[shard] = true
+		}
+		c.Expect(len(shards), gospec.Equals, 4)
+	})
+
+	c.Specify("[RedisBatchCommands][splitByShard] Keeps the order & fails the commands spanning shards", func() {
+		// Keys on different shards
+		var a, b string
+		for i := 0; "" == a || "" == b; i++ {
+			key := fmt.Sprintf("Key%v", i)
+			if 0 == redisShardOf(key, 2) && "" == a {
+				a = key
+			} else if 1 == redisShardOf(key, 2) && "" == b {
+				b = key
+			}
+		}
+
+		cmds := RedisBatchCommands{
+			MakeRedisBatchCommandGet(b),
+			MakeRedisBatchCommandIncrementBy(a, 1),
+			MakeRedisBatchCommandMget(a, b),
+			MakeRedisBatchCommandGet(a),
+			MakeRedisBatchCommandMget("{"+a+"}:1", "{"+a+"}:2"),
+			&RedisBatchCommand{cmd: "PING"},
+		}
+		split := cmds.splitByShard(2)
+		c.Expect(len(split), gospec.Equals, 2)
+		c.Expect(split[0], gospec.Equals, RedisBatchCommands{cmds[1], cmds[3], cmds[4], cmds[5]})
+		c.Expect(split[1], gospec.Equals, RedisBatchCommands{cmds[0]})
+		c.Expect(cmds[2].Reply().Err, gospec.Equals, ErrCrossShardCommand)
+	})
+
+	c.Specify("[RedisConnectionPool][PopUrl] Pops a connection to the url, returning the others", func() {
+		pool := &RedisConnectionPool{Mode: LAZY, Size: 4, Urls: urls}
+		c.Expect(pool.Open(), gospec.Equals, nil)
+		defer pool.Close()
+
+		first, err := pool.PopUrl(urls[1])
+		c.Expect(err, gospec.Equals, nil)
+		c.Expect(first.Url, gospec.Equals, urls[1])
+		c.Expect(pool.Len(), gospec.Equals, 3)
+
+		second, err := pool.PopUrl(urls[1])
+		c.Expect(err, gospec.Equals, nil)
+		c.Expect(second.Url, gospec.Equals, urls[1])
+
+		_, err = pool.PopUrl(urls[1])
+		c.Expect(err, gospec.Equals, ErrNoConnectionsAvailable)
+		c.Expect(pool.Len(), gospec.Equals, 2)
+
+		pool.Push(first)
+		pool.Push(second)
+	})
+
+	c.Specify("[RedisBatchQueue][Open] Needs a connection per shard", func() {
+		sent_to := map[string]string{}
+		pool := makeTestRedisShardedPool(1, urls, sent_to, &sync.Mutex{})
+		defer pool.Close()
+
+		ptr := &RedisBatchQueue{Pool: pool, PoolMode: RedisBatchPoolPerBatch, QueueSize: 10, WorkersSize: 1, WorkersBatchSize: 1}
+		c.Expect(ptr.Open().Error(), gospec.Equals, "[RedisBatchQueue][Open] Pool Size[1] must be >= its 2 shards!")
+	})
+
+	c.Specify("[RedisBatchQueue] Routes the commands to their shard", func() {
+		for _, mode := range []RedisBatchPoolMode{RedisBatchPoolPerBatch, RedisBatchPoolPerWorker} {
+			sent_to := map[string]string{}
+			mutex := &sync.Mutex{}
+			pool := makeTestRedisShardedPool(4, urls, sent_to, mutex)
+
+			ptr := &RedisBatchQueue{
+				Pool:             pool,
+				PoolMode:         mode,
+				QueueSize:        100,
+				WorkersSize:      2,
+				WorkersBatchSize: 10,
+				Namespace:        "ns:",
+			}
+			c.Expect(ptr.Open(), gospec.Equals, nil)
+
+			cmds := RedisBatchCommands{}
+			for i := 0; i < 20; i++ {
+				cmds = append(cmds, MakeRedisBatchCommandIncrementBy(fmt.Sprintf("Key%v", i), 1))
+			}
+			c.Expect(ptr.RunAsync(cmds...), gospec.Equals, nil)
+
+			dropped, err := ptr.Close(context.Background())
+			c.Expect(dropped, gospec.Equals, 0)
+			c.Expect(err, gospec.Equals, nil)
+			c.Expect(pool.Len(), gospec.Equals, 4)
+
+			mutex.Lock()
+			c.Expect(len(sent_to), gospec.Equals, 20)
+			for i := 0; i < 20; i++ {
+				key := fmt.Sprintf("Key%v", i)
+				c.Expect(sent_to["ns:"+key], gospec.Equals, urls[redisShardOf(key, 2)])
+			}
+			mutex.Unlock()
+			pool.Close()
+		}
+	})
+}
@@ -1,5 +1,7 @@
 package dog_pool
 
+import "context"
+import "sync"
 import "time"
 import "runtime"
 import "github.com/alecthomas/log4go"
@@ -52,20 +54,20 @@ func RedisBatchQueueSpecs(c gospec.Context) {
 			queue:            make(chan *RedisBatchCommand, 10),
 		}
 		defer close(ptr.queue)
-		c.Expect(ptr.String(), gospec.Equals, "RedisBatchQueue { Connection=<nil>, QueueSize=10, WorkersSize=1, WorkersBatchSize=5, Queue.Cap=10, Queue.Len=0 }")
+		c.Expect(ptr.String(), gospec.Equals, "RedisBatchQueue { Connection=<nil>, QueueSize=10, WorkersSize=1, WorkersBatchSize=5, Queue.Cap=10, Queue.Len=0, Dropped=0, Spilled=0 }")
 	})
 
 	c.Specify("[RedisBatchQueue][Open]", func() {
 		ptr := &RedisBatchQueue{}
-		defer ptr.Close()
+		defer ptr.Close(context.Background())
 
 		err := ptr.Open()
 		c.Expect(err, gospec.Satisfies, nil != err)
 		c.Expect(ptr.queue, gospec.Satisfies, nil == ptr.queue)
 		c.Expect(ptr.workers, gospec.Satisfies, 0 == len(ptr.workers))
-		c.Expect(err.Error(), gospec.Equals, "[RedisBatchQueue][Open] Nil Logger!")
+		c.Expect(err.Error(), gospec.Equals, "[RedisBatchQueue][Open] Nil redis connection!")
 
-		ptr.Logger = &log4go.Logger{}
+		// Nil Logger is the NopLogger
 		ptr.queue = make(chan *RedisBatchCommand, 10)
 
 		err = ptr.Open()
@@ -120,14 +122,14 @@ func RedisBatchQueueSpecs(c gospec.Context) {
 	c.Specify("[RedisBatchQueue][Close]", func() {
 		ptr := &RedisBatchQueue{}
 
-		err := ptr.Close()
+		_, err := ptr.Close(context.Background())
 		c.Expect(err, gospec.Satisfies, nil == err)
 		c.Expect(ptr.queue, gospec.Satisfies, nil == ptr.queue)
 		c.Expect(ptr.workers, gospec.Satisfies, 0 == len(ptr.workers))
 
 		ptr.queue = make(chan *RedisBatchCommand, 10)
 		ptr.workers = []*redisBatchQueueWorker{nil, nil, nil, nil, nil}
-		err = ptr.Close()
+		_, err = ptr.Close(context.Background())
 		c.Expect(err, gospec.Satisfies, nil == err)
 		c.Expect(ptr.queue, gospec.Satisfies, nil == ptr.queue)
 		c.Expect(ptr.workers, gospec.Satisfies, 0 == len(ptr.workers))
@@ -137,15 +139,15 @@ func RedisBatchQueueSpecs(c gospec.Context) {
 		prev := runtime.GOMAXPROCS(2)
 		defer runtime.GOMAXPROCS(prev)
 
-		logger := log4go.NewDefaultLogger(log4go.CRITICAL)
-		server, err := StartRedisServer(&logger)
+		logger := MakeLog4goLogger(log4go.NewDefaultLogger(log4go.CRITICAL))
+		server, err := StartRedisServer(logger)
 		if nil != err {
 			panic(err)
 		}
 		defer server.Close()
 
 		ptr := &RedisBatchQueue{
-			Logger:           &logger,
+			Logger:           logger,
 			Connection:       server.Connection(),
 			QueueSize:        10,
 			WorkersSize:      5,
@@ -185,7 +187,7 @@ func RedisBatchQueueSpecs(c gospec.Context) {
 		c.Expect(err, gospec.Satisfies, nil == err)
 
 		time.Sleep(time.Millisecond)
-		ptr.Close()
+		ptr.Close(context.Background())
 
 		time.Sleep(50 * time.Millisecond)
 
@@ -197,4 +199,89 @@ func RedisBatchQueueSpecs(c gospec.Context) {
 		c.Expect(ints[2], gospec.Satisfies, nil == ints[2])
 	})
 
+	c.Specify("[RedisBatchQueue][Close] Drops the queued commands once the context is done", func() {
+		// A worker that never finishes
+		workers_wg := &sync.WaitGroup{}
+		workers_wg.Add(1)
+		defer workers_wg.Done()
+
+		ptr := &RedisBatchQueue{
+			queue:      make(chan *RedisBatchCommand, 10),
+			closing:    make(chan struct{}),
+			workers_wg: workers_wg,
+		}
+
+		future, err := ptr.RunAsyncFuture(MakeRedisBatchCommandGet("A"), MakeRedisBatchCommandGet("B"), MakeRedisBatchCommandGet("C"))
+		c.Expect(err, gospec.Equals, nil)
+
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Millisecond)
+		defer cancel()
+
+		dropped, err := ptr.Close(ctx)
+		c.Expect(dropped, gospec.Equals, 3)
+		c.Expect(err, gospec.Equals, context.DeadlineExceeded)
+		c.Expect(ptr.Stats().Dropped, gospec.Equals, uint64(3))
+		c.Expect(future.Wait(context.Background()), gospec.Equals, ErrQueueIsClosed)
+
+		// Closed queues reject commands, rather than panic
+		err = ptr.RunAsync(MakeRedisBatchCommandGet("D"))
+		c.Expect(err.Error(), gospec.Equals, "[RedisBatchQueue][RunAsync] Queue is closed!")
+	})
+
+	c.Specify("[RedisBatchQueue][Close] Releases RunAsync calls blocked on a full queue", func() {
+		workers_wg := &sync.WaitGroup{}
+		workers_wg.Add(1)
+		defer workers_wg.Done()
+
+		ptr := &RedisBatchQueue{
+			queue:      make(chan *RedisBatchCommand, 1),
+			closing:    make(chan struct{}),
+			workers_wg: workers_wg,
+		}
+		c.Expect(ptr.RunAsync(MakeRedisBatchCommandGet("A")), gospec.Equals, nil)
+
+		blocked := make(chan error, 1)
+		go func() { blocked <- ptr.RunAsync(MakeRedisBatchCommandGet("B")) }()
+		time.Sleep(time.Millisecond)
+
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Millisecond)
+		defer cancel()
+
+		dropped, err := ptr.Close(ctx)
+		c.Expect(dropped, gospec.Equals, 1)
+		c.Expect(err, gospec.Equals, context.DeadlineExceeded)
+		c.Expect(<-blocked, gospec.Equals, ErrQueueIsClosed)
+		c.Expect(ptr.Stats().Dropped, gospec.Equals, uint64(2))
+	})
+
+	c.Specify("[RedisBatchQueue][Close] Waits for the workers to drain the queue", func() {
+		logger := MakeLog4goLogger(log4go.NewDefaultLogger(log4go.CRITICAL))
+		server, err := StartRedisServer(logger)
+		if nil != err {
+			panic(err)
+		}
+		defer server.Close()
+
+		ptr := &RedisBatchQueue{
+			Logger:           logger,
+			Connection:       server.Connection(),
+			QueueSize:        100,
+			WorkersSize:      2,
+			WorkersBatchSize: 5,
+		}
+		c.Expect(ptr.Open(), gospec.Equals, nil)
+
+		for i := 0; i < 50; i++ {
+			c.Expect(ptr.RunAsync(MakeRedisBatchCommandHashIncrementBy("Hash", "Field A", 1)), gospec.Equals, nil)
+		}
+
+		dropped, err := ptr.Close(context.Background())
+		c.Expect(dropped, gospec.Equals, 0)
+		c.Expect(err, gospec.Equals, nil)
+
+		ints, ints_err := RedisDsl{server.Connection()}.HASH_MGET_INT64S("Hash", "Field A")
+		c.Expect(ints_err, gospec.Equals, nil)
+		c.Expect(*ints[0], gospec.Equals, int64(50))
+	})
+
 }
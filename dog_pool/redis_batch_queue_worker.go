@@ -1,18 +1,36 @@
 package dog_pool
 
 import "fmt"
-import "github.com/alecthomas/log4go"
+import "time"
 
 // Worker for running Redis Commands serially in a go routine
 type redisBatchQueueWorker struct {
-	Logger       *log4go.Logger            "Logger for logging updates, errors, etc"
-	Connection   *RedisConnection          "Connection to Redis"
-	BatchSize    uint                      "Number of RedisBatchCommand's to process at once"
-	CommandQueue <-chan *RedisBatchCommand "Output only queue"
+	Logger           Logger                    "(optional) Logger for logging updates, errors, etc"
+	Connection       *RedisConnection          "Connection to Redis"
+	Client           RedisClientInterface      "Client the batches are executed on, defaults to Connection"
+	Pool             *RedisConnectionPool      "(optional) Pool the Connection was borrowed from, or the connection of every batch is borrowed from if Client is nil"
+	Namespace        string                    "(optional) Prefix added to every key on the connections borrowed for each batch"
+	Shards           []string                  "(optional) Distinct Urls of a sharded Pool, every command runs on the shard of its keys"
+	ShardClients     []RedisClientInterface    "(optional) Client of every shard, borrowed until the worker stops; borrowed for every batch if empty"
+	ShardConnections []*RedisConnection        "(optional) Connections of the ShardClients, returned to the Pool on close"
+	BatchSize        uint                      "Number of RedisBatchCommand's to process at once"
+	CommandQueue     <-chan *RedisBatchCommand "Output only queue"
+	Metrics          *PrometheusCollector      "(optional) Records the size of every batch"
+	QueueName        string                    "Name of the queue, used to label metrics"
+	MaxLinger        time.Duration             "(optional) How long to wait for a batch to fill up before flushing it"
+	Retry            *RedisBatchRetryPolicy    "(optional) Retry commands that failed with a connection error"
+	DeadLetter       RedisDeadLetterSink       "(optional) Receives the commands that exhausted their retries"
+	Lanes            *redisBatchLaneReader     "(optional) Priority lanes drained instead of the CommandQueue, see RedisBatchLane"
+	BatchTimer       func(time.Duration)       "(optional) Observes how long every batch took to run"
+	IdleTimeout      time.Duration             "(optional) How long to wait for a command before asking to Retire"
+	Retire           func() bool               "(optional) Asked once idle for IdleTimeout, the worker exits if it returns true"
+	RateLimit        *RedisBatchRateLimiter    "(optional) Waited on before running every batch"
+	FailFast         bool                      "(optional) Stop reading a batch's replies after a connection error, see ExecuteBatchFailFast"
+	Closing          <-chan struct{}           "(optional) Closed by RedisBatchQueue.Close, cuts the retry backoff short"
 }
 
 // Make a new instance of redisBatchQueueWorker, or return an error
-func makeRedisBatchQueueWorker(logger *log4go.Logger, connection *RedisConnection, batch_size uint, queue <-chan *RedisBatchCommand) (*redisBatchQueueWorker, error) {
+func makeRedisBatchQueueWorker(logger Logger, connection *RedisConnection, batch_size uint, queue <-chan *RedisBatchCommand) (*redisBatchQueueWorker, error) {
 	p := &redisBatchQueueWorker{
 		Logger:       logger,
 		Connection:   connection,
@@ -20,9 +38,11 @@ func makeRedisBatchQueueWorker(logger *log4go.Logger, connection *RedisConnectio
 		BatchSize:    batch_size,
 	}
 
+	if nil != connection {
+		p.Client = connection
+	}
+
 	switch {
-	case nil == p.Logger:
-		return nil, fmt.Errorf("[redisBatchQueueWorker][Make] Nil logger!")
 	case nil == p.Connection:
 		return nil, fmt.Errorf("[redisBatchQueueWorker][Make] Nil redis connection!")
 	case nil == p.CommandQueue:
@@ -59,8 +79,16 @@ func (p *redisBatchQueueWorker) Run() {
 //
 // Returns:
 //   ptr, true  --> Got a command, the queue is open
-//   nil, false --> The queue is closed
+//   nil, false --> The queue is closed, or the worker retired
 func (p *redisBatchQueueWorker) mustPopCommand() (*RedisBatchCommand, bool) {
+	if p.IdleTimeout > 0 && nil != p.Retire {
+		return p.idlePopCommand()
+	}
+
+	if nil != p.Lanes {
+		return p.Lanes.pop(nil)
+	}
+
 	select {
 	// Will only execute once there is a command or the queue is closed:
 	case cmd, queue_is_open := <-p.CommandQueue:
@@ -79,6 +107,10 @@ func (p *redisBatchQueueWorker) mustPopCommand() (*RedisBatchCommand, bool) {
 //   nil, true  --> No commands left in the queue, the queue is open
 //   nil, false --> The queue is closed
 func (p *redisBatchQueueWorker) mayPopCommand() (*RedisBatchCommand, bool) {
+	if nil != p.Lanes {
+		return p.Lanes.tryPop()
+	}
+
 	select {
 	case cmd, queue_is_open := <-p.CommandQueue:
 		// Will only execute once there is a command or the queue is closed:
@@ -90,7 +122,51 @@ func (p *redisBatchQueueWorker) mayPopCommand() (*RedisBatchCommand, bool) {
 }
 
 //
-// Pop a collection of commands from the queue
+// Pop a RedisBatchCommand from the queue, waits until the linger timer fires:
+//
+// Returns:
+//   ptr, true  --> Got a command, the queue is open
+//   nil, true  --> Done lingering, the queue is open
+//   nil, false --> The queue is closed
+func (p *redisBatchQueueWorker) lingerPopCommand(linger <-chan time.Time) (*RedisBatchCommand, bool) {
+	if nil != p.Lanes {
+		return p.Lanes.pop(linger)
+	}
+
+	select {
+	case cmd, queue_is_open := <-p.CommandQueue:
+		// Will only execute once there is a command or the queue is closed:
+		return cmd, queue_is_open
+	case <-linger:
+		// Waited long enough, continue ...
+		return nil, true
+	}
+}
+
+//
+// Pop a RedisBatchCommand from the queue, blocks until a command is available, the queue is closed, or the worker retires:
+//
+// Returns:
+//   ptr, true  --> Got a command, the queue is open
+//   nil, false --> The queue is closed, or the worker was idle for IdleTimeout & retired
+func (p *redisBatchQueueWorker) idlePopCommand() (*RedisBatchCommand, bool) {
+	for {
+		timer := time.NewTimer(p.IdleTimeout)
+		cmd, queue_is_open := p.lingerPopCommand(timer.C)
+		timer.Stop()
+
+		switch {
+		case nil != cmd || !queue_is_open:
+			return cmd, queue_is_open
+		case p.Retire():
+			logAt(p.Logger, LogInfo, "[redisBatchQueueWorker][Run] Idle for %v, retiring", p.IdleTimeout)
+			return nil, false
+		}
+	}
+}
+
+//
+// Pop a collection of commands from the queue, lingering up to MaxLinger to fill the batch
 //
 // Returns:
 //   ptrs, true  --> We recieve commands and the queue is open
@@ -117,8 +193,20 @@ func (p *redisBatchQueueWorker) popCommands() (RedisBatchCommands, bool) {
 		return nil, false
 	}
 
+	// Linger for more commands, rather than flushing what is already queued?
+	var linger <-chan time.Time
+	if p.MaxLinger > 0 && p.BatchSize > 1 {
+		timer := time.NewTimer(p.MaxLinger)
+		defer timer.Stop()
+		linger = timer.C
+	}
+
 	for i := uint(1); i < p.BatchSize; i++ {
-		cmd, ok = p.mayPopCommand()
+		if nil != linger {
+			cmd, ok = p.lingerPopCommand(linger)
+		} else {
+			cmd, ok = p.mayPopCommand()
+		}
 		switch {
 		case !ok:
 			// The queue is closed, return what we have and exit
@@ -131,7 +219,7 @@ func (p *redisBatchQueueWorker) popCommands() (RedisBatchCommands, bool) {
 		default:
 			// nil == cmd
 
-			// The queue is empty & open, return what we have
+			// The queue is empty & open (or we are done lingering), return what we have
 			return commands, true
 		}
 	}
@@ -144,18 +232,105 @@ func (p *redisBatchQueueWorker) popCommands() (RedisBatchCommands, bool) {
 // Execute a batch of commands and log the results
 //
 func (p *redisBatchQueueWorker) runCommands(cmds RedisBatchCommands) {
+	if nil != p.Metrics {
+		p.Metrics.ObserveBatchSize(p.QueueName, len(cmds))
+	}
+
+	// Throttled time doesn't count towards the batch latency
+	p.throttle(len(cmds))
+
+	if nil != p.BatchTimer {
+		started := time.Now()
+		defer func() { p.BatchTimer(time.Since(started)) }()
+	}
+
 	//  Execute the batch and log any high-level errors:
-	if err := cmds.ExecuteBatch(p.Connection); nil != err {
-		p.Logger.Critical("[redisBatchQueueWorker][Run] Error processing Redis Batch: err=%v", err)
+	if err := p.executeBatch(cmds); nil != err {
+		logAt(p.Logger, LogCritical, "[redisBatchQueueWorker][Run] Error processing Redis Batch: err=%v", err)
+	}
+
+	// Retry the commands that failed with a retryable error, then dead-letter the rest
+	if failed := p.retryCommands(cmds); len(failed) > 0 {
+		p.deadLetterCommands(failed)
 	}
 
 	// Iterate the commands and log the command + results:
 	for i, cmd := range cmds {
 		switch err := cmd.Reply().Err; {
 		case nil != err:
-			p.Logger.Critical("[redisBatchQueueWorker][Run][%v] Error processing Redis Command: err=%v, cmd=%v", i, err, cmd)
+			logAt(p.Logger, LogCritical, "[redisBatchQueueWorker][Run][%v] Error processing Redis Command: err=%v, cmd=%v", i, err, cmd)
 		default:
-			p.Logger.Info("[redisBatchQueueWorker][Run][%v] Success processing Redis Command: cmd=%v", i, cmd)
+			logAt(p.Logger, LogInfo, "[redisBatchQueueWorker][Run][%v] Success processing Redis Command: cmd=%v", i, cmd)
+		}
+	}
+
+	// Resolve any futures waiting on the replies
+	for _, cmd := range cmds {
+		cmd.complete()
+	}
+}
+
+//
+// Retry the commands that failed with a retryable error (see isRedisRetryable), backing off between each attempt;
+// Closing cuts the backoff short
+//
+// Returns:
+//   []    --> Every command reached Redis
+//   [...] --> The commands that still failed with a retryable error after the last retry, or once Closing
+//
+func (p *redisBatchQueueWorker) retryCommands(cmds RedisBatchCommands) RedisBatchCommands {
+	failed := retryableFailures(cmds)
+	if nil == p.Retry {
+		return failed
+	}
+
+	for attempt := 1; len(failed) > 0 && attempt <= p.Retry.MaxRetries; attempt++ {
+		delay := p.Retry.Delay(attempt)
+		logAt(p.Logger, LogWarning, "[redisBatchQueueWorker][Retry][%v/%v] Retrying %v commands in %v: err=%v", attempt, p.Retry.MaxRetries, len(failed), delay, failed[0].Reply().Err)
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-timer.C:
+		case <-p.Closing:
+			timer.Stop()
+			logAt(p.Logger, LogWarning, "[redisBatchQueueWorker][Retry][%v/%v] Closing, giving up on %v commands", attempt, p.Retry.MaxRetries, len(failed))
+			return failed
+		}
+
+		p.throttle(len(failed))
+		if err := p.executeBatch(failed); nil != err {
+			logAt(p.Logger, LogError, "[redisBatchQueueWorker][Retry][%v/%v] Error processing Redis Batch: err=%v", attempt, p.Retry.MaxRetries, err)
+		}
+		failed = retryableFailures(failed)
+	}
+
+	return failed
+}
+
+//
+// Hand the commands to the DeadLetter sink, if any
+//
+func (p *redisBatchQueueWorker) deadLetterCommands(cmds RedisBatchCommands) {
+	if nil == p.DeadLetter {
+		return
+	}
+
+	for i, cmd := range cmds {
+		if err := p.DeadLetter.DeadLetter(cmd, cmd.Reply().Err); nil != err {
+			logAt(p.Logger, LogCritical, "[redisBatchQueueWorker][DeadLetter][%v] Error dead-lettering Redis Command: err=%v, cmd=%v", i, err, cmd)
+		}
+	}
+}
+
+//
+// Commands that failed with a retryable error, see isRedisRetryable
+//
+func retryableFailures(cmds RedisBatchCommands) RedisBatchCommands {
+	output := make(RedisBatchCommands, len(cmds))[0:0]
+	for _, cmd := range cmds {
+		if isRedisRetryable(cmd) {
+			output = append(output, cmd)
 		}
 	}
+	return output
 }
@@ -0,0 +1,135 @@
+//
+// Blocking Redis Commands (BLPOP, BRPOP, BRPOPLPUSH, BLMOVE, ...)
+//
+// Blocking commands hold the socket open until the server-side timeout expires,
+// so they are run on a dedicated client whose read/write timeout is
+// the server-side timeout + RedisConnection.BlockingMargin.
+//
+
+package dog_pool
+
+import "time"
+import "github.com/RUNDSP/radix/redis"
+
+//
+// Round the blocking timeout up to whole seconds, as expected by Redis
+//
+func blockingSeconds(block_for time.Duration) int64 {
+	return int64((block_for + time.Second - 1) / time.Second)
+}
+
+//
+// BlockingCmd calls the given blocking Redis command:
+// - block_for is the server-side timeout, it is appended as the last argument (in seconds)
+// - The reply is read on a dedicated client, with a socket timeout of block_for + BlockingMargin
+//
+// Returns:
+//   NilReply   --> The server-side timeout expired
+//   ErrorReply --> block_for <= 0, or the command failed
+//   Otherwise  --> The command's reply
+//
+func (p *RedisConnection) BlockingCmd(cmd string, block_for time.Duration, args ...interface{}) *redis.Reply {
+	if block_for <= 0 {
+		return &redis.Reply{Type: redis.ErrorReply, Err: ErrBlockingTimeoutRequired}
+	}
+
+	// Default to 2s margin
+	if time.Duration(0) == p.BlockingMargin {
+		p.BlockingMargin = time.Duration(2) * time.Second
+	}
+
+	seconds := blockingSeconds(block_for)
+	timeout := time.Duration(seconds)*time.Second + p.BlockingMargin
+
+	// (Re-)open the blocking connection if it can't wait long enough
+	if nil == p.blocking_client || p.blocking_timeout < timeout {
+		if err := p.openBlockingClient(timeout); nil != err {
+			return &redis.Reply{Type: redis.ErrorReply, Err: err}
+		}
+	}
+
+	if isLogEnabled(p.Logger, LogTrace) {
+		text, _ := redactionPolicyOr(p.Redaction).FormatCommand(cmd, args)
+		logAt(p.Logger, LogTrace, "[RedisConnection][BlockingCmd][%s/%s] Redis Command = '%s', Timeout = %ds", p.Url, p.Id, text, seconds)
+	}
+
+	reply := p.blocking_client.Cmd(cmd, append(args, seconds)...)
+
+	// Errors leave the connection in an unknown state, re-open it on the next call
+	if reply.Type == redis.ErrorReply {
+		logAt(p.Logger, LogError, "[RedisConnection][BlockingCmd][%s/%s] Error from Redis, cmd=%v, Error = %v", p.Url, p.Id, cmd, reply.Err)
+		p.closeBlockingClient()
+	}
+
+	return reply
+}
+
+//
+// BLPOP <KEY> <KEY> ... <TIMEOUT>
+//
+func (p *RedisConnection) BLPOP(block_for time.Duration, keys ...string) *redis.Reply {
+	return p.BlockingCmd("BLPOP", block_for, keys)
+}
+
+//
+// BRPOP <KEY> <KEY> ... <TIMEOUT>
+//
+func (p *RedisConnection) BRPOP(block_for time.Duration, keys ...string) *redis.Reply {
+	return p.BlockingCmd("BRPOP", block_for, keys)
+}
+
+//
+// BRPOPLPUSH <SOURCE> <DESTINATION> <TIMEOUT>
+//
+func (p *RedisConnection) BRPOPLPUSH(source, destination string, block_for time.Duration) *redis.Reply {
+	return p.BlockingCmd("BRPOPLPUSH", block_for, source, destination)
+}
+
+//
+// BLMOVE <SOURCE> <DESTINATION> <LEFT|RIGHT> <LEFT|RIGHT> <TIMEOUT>
+//
+func (p *RedisConnection) BLMOVE(source, destination, where_from, where_to string, block_for time.Duration) *redis.Reply {
+	return p.BlockingCmd("BLMOVE", block_for, source, destination, where_from, where_to)
+}
+
+//
+// Open the dedicated connection for blocking commands
+//
+func (p *RedisConnection) openBlockingClient(timeout time.Duration) error {
+	p.closeBlockingClient()
+
+	// Open the TCP connection
+	client, err := redis.DialTimeout("tcp", p.Url, timeout)
+
+	// Check for errors
+	if nil != err {
+		// Log the event
+		logAt(p.Logger, LogError, "[RedisConnection][openBlockingClient][%s/%s] --> Error = %v", p.Url, p.Id, err)
+
+		// Return the error
+		return err
+	}
+
+	// Save the client pointer
+	p.blocking_client = client
+	p.blocking_timeout = timeout
+
+	// Log the event
+	if isLogEnabled(p.Logger, LogInfo) {
+		logAt(p.Logger, LogInfo, "[RedisConnection][openBlockingClient][%s/%s] --> Opened! Timeout = %v", p.Url, p.Id, timeout)
+	}
+
+	return nil
+}
+
+//
+// Close the dedicated connection for blocking commands
+//
+func (p *RedisConnection) closeBlockingClient() {
+	if nil != p.blocking_client {
+		p.blocking_client.Close()
+	}
+
+	p.blocking_client = nil
+	p.blocking_timeout = 0
+}
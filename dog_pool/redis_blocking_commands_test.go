@@ -0,0 +1,97 @@
+package dog_pool
+
+import "time"
+import "testing"
+import "github.com/orfjackal/gospec/src/gospec"
+import "github.com/alecthomas/log4go"
+import "github.com/RUNDSP/radix/redis"
+
+func TestRedisBlockingCommandsSpecs(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping test in benchmark mode.")
+		return
+	}
+	r := gospec.NewRunner()
+	r.AddSpec(RedisBlockingCommandsSpecs)
+	gospec.MainGoTest(r, t)
+}
+
+// Helpers
+func RedisBlockingCommandsSpecs(c gospec.Context) {
+
+	c.Specify("[blockingSeconds] Rounds up to whole seconds", func() {
+		c.Expect(blockingSeconds(time.Millisecond), gospec.Equals, int64(1))
+		c.Expect(blockingSeconds(time.Second), gospec.Equals, int64(1))
+		c.Expect(blockingSeconds(1500*time.Millisecond), gospec.Equals, int64(2))
+		c.Expect(blockingSeconds(time.Minute), gospec.Equals, int64(60))
+	})
+
+	c.Specify("[RedisConnection][BlockingCmd] Requires a timeout", func() {
+		logger := MakeLog4goLogger(log4go.NewDefaultLogger(log4go.CRITICAL))
+		connection := &RedisConnection{Url: "127.0.0.1:6990", Logger: logger}
+		defer connection.Close()
+
+		reply := connection.BlockingCmd("BLPOP", 0, "Queue")
+		c.Expect(reply.Type, gospec.Equals, redis.ErrorReply)
+		c.Expect(reply.Err, gospec.Equals, ErrBlockingTimeoutRequired)
+		c.Expect(connection.blocking_client, gospec.Satisfies, nil == connection.blocking_client)
+	})
+
+	c.Specify("[RedisConnection][BlockingCmd] Times out with NilReply", func() {
+		logger := MakeLog4goLogger(log4go.NewDefaultLogger(log4go.CRITICAL))
+		server, err := StartRedisServer(logger)
+		if nil != err {
+			panic(err)
+		}
+		defer server.Close()
+
+		reply := server.Connection().BLPOP(time.Second, "Queue")
+		c.Expect(reply.Err, gospec.Equals, nil)
+		c.Expect(reply.Type, gospec.Equals, redis.NilReply)
+		c.Expect(server.Connection().blocking_timeout, gospec.Equals, 3*time.Second)
+
+		// Does not touch the regular connection
+		c.Expect(server.Connection().IsClosed(), gospec.Equals, true)
+
+		// Closing the connection closes the blocking connection
+		server.Connection().Close()
+		c.Expect(server.Connection().blocking_client, gospec.Satisfies, nil == server.Connection().blocking_client)
+	})
+
+	c.Specify("[RedisConnection][BRPOPLPUSH] Moves the value", func() {
+		logger := MakeLog4goLogger(log4go.NewDefaultLogger(log4go.CRITICAL))
+		server, err := StartRedisServer(logger)
+		if nil != err {
+			panic(err)
+		}
+		defer server.Close()
+
+		server.Connection().Cmd("LPUSH", "Source", "A", "B")
+
+		value, err := server.Connection().BRPOPLPUSH("Source", "Destination", time.Second).Str()
+		c.Expect(err, gospec.Equals, nil)
+		c.Expect(value, gospec.Equals, "A")
+
+		values, err := server.Connection().Cmd("LRANGE", "Destination", 0, -1).List()
+		c.Expect(err, gospec.Equals, nil)
+		c.Expect(len(values), gospec.Equals, 1)
+		c.Expect(values[0], gospec.Equals, "A")
+	})
+
+	c.Specify("[RedisConnection][BRPOP] Pops from the first non-empty list", func() {
+		logger := MakeLog4goLogger(log4go.NewDefaultLogger(log4go.CRITICAL))
+		server, err := StartRedisServer(logger)
+		if nil != err {
+			panic(err)
+		}
+		defer server.Close()
+
+		server.Connection().Cmd("RPUSH", "B", "1", "2")
+
+		values, err := server.Connection().BRPOP(time.Second, "A", "B").List()
+		c.Expect(err, gospec.Equals, nil)
+		c.Expect(len(values), gospec.Equals, 2)
+		c.Expect(values[0], gospec.Equals, "B")
+		c.Expect(values[1], gospec.Equals, "2")
+	})
+}
@@ -5,12 +5,12 @@
 package dog_pool
 
 import "bytes"
+import "context"
 import "fmt"
 import "time"
 import "reflect"
 import "strconv"
 import "github.com/RUNDSP/radix/redis"
-import "github.com/alecthomas/log4go"
 
 //
 // Connection Wrapper for Redis
@@ -20,35 +20,48 @@ type RedisConnection struct {
 
 	Id string "(optional) Identifier for distingushing between redis connections"
 
-	Logger *log4go.Logger "Handle to the logger we are using"
+	Logger Logger "(optional) Handle to the logger we are using, nil is the NopLogger"
 
 	Timeout time.Duration "Connection Timeout"
 
+	BlockingMargin time.Duration "(optional) Extra time to wait for a blocking command's reply, beyond its server-side timeout"
+
 	client *redis.Client "Connection to a Redis, may be nil"
 
-	cmd_queue []string
+	Hooks RedisHooks "(optional) Hooks called around every command"
+
+	Tracer Tracer "(optional) Opens a span for every command, see CmdContext"
+
+	Redaction *RedactionPolicy "(optional) Hides & truncates the logged values, defaults to DefaultRedactionPolicy"
+
+	SlowLogBuffer *RedisSlowLog "(optional) Records commands slower than its Threshold, see SlowLog()"
+
+	blocking_client  *redis.Client "Dedicated connection for blocking commands, may be nil"
+	blocking_timeout time.Duration "Socket timeout the blocking connection was opened with"
+
+	cmd_queue []redisLoggedCmd "Commands pending a reply, formatted for the logs"
+
+	hook_queue []*RedisHookCall "Pending calls passing through the Hooks"
 }
 
-func (p *RedisConnection) String() string {
-	return fmt.Sprintf("RedisConnection { Id=%v, Url=%v, Timeout=%v }", p.Id, p.Url, p.Timeout)
+//
+// Command formatted for the logs
+//
+type redisLoggedCmd struct {
+	text   string "Command & arguments, after redaction"
+	redact bool   "Hide the reply's values"
 }
 
-func minLogLevel(logger *log4go.Logger) log4go.Level {
-	log_level := log4go.CRITICAL
-	for _, filter := range *logger {
-		if log_level > filter.Level {
-			log_level = filter.Level
-		}
-	}
-	return log_level
+func (p *RedisConnection) String() string {
+	return fmt.Sprintf("RedisConnection { Id=%v, Url=%v, Timeout=%v }", p.Id, p.Url, p.Timeout)
 }
 
 //
 // Lazily make a Redis Connection
 //
-func makeLazyRedisConnection(url string, id string, timeout time.Duration, logger *log4go.Logger) (*RedisConnection, error) {
+func makeLazyRedisConnection(url string, id string, timeout time.Duration, logger Logger, hooks RedisHooks) (*RedisConnection, error) {
 	// Create a new factory instance
-	p := &RedisConnection{Url: url, Id: id, Logger: logger, Timeout: timeout}
+	p := &RedisConnection{Url: url, Id: id, Logger: logger, Timeout: timeout, Hooks: hooks}
 
 	// Return the factory
 	return p, nil
@@ -57,9 +70,9 @@ func makeLazyRedisConnection(url string, id string, timeout time.Duration, logge
 //
 // Agressively make a Redis Connection
 //
-func makeAgressiveRedisConnection(url string, id string, timeout time.Duration, logger *log4go.Logger) (*RedisConnection, error) {
+func makeAgressiveRedisConnection(url string, id string, timeout time.Duration, logger Logger, hooks RedisHooks) (*RedisConnection, error) {
 	// Create a new factory instance
-	p, _ := makeLazyRedisConnection(url, id, timeout, logger)
+	p, _ := makeLazyRedisConnection(url, id, timeout, logger, hooks)
 
 	// Ping the server
 	if err := p.Ping(); nil != err {
@@ -78,10 +91,21 @@ func makeAgressiveRedisConnection(url string, id string, timeout time.Duration,
 // Clone the connection and return a new instance of RedisConnection
 //
 func (p *RedisConnection) Clone() *RedisConnection {
-	connection, _ := makeLazyRedisConnection(p.Url, p.Id, p.Timeout, p.Logger)
+	connection, _ := makeLazyRedisConnection(p.Url, p.Id, p.Timeout, p.Logger, p.Hooks)
+	connection.BlockingMargin = p.BlockingMargin
+	connection.Tracer = p.Tracer
+	connection.Redaction = p.Redaction
+	connection.SlowLogBuffer = p.SlowLogBuffer
 	return connection
 }
 
+//
+// Tracer & URL for spans opened around this connection, see RedisBatchCommands.ExecuteBatchContext
+//
+func (p *RedisConnection) tracer() (Tracer, string) {
+	return p.Tracer, p.Url
+}
+
 //
 //  ========================================
 //
@@ -102,9 +126,12 @@ func (p *RedisConnection) Close() (err error) {
 	// Set the pointer to nil
 	p.client = nil
 
+	// Close the blocking connection too
+	p.closeBlockingClient()
+
 	// Log the event
-	if log4go.INFO >= minLogLevel(p.Logger) {
-		p.Logger.Info("[RedisConnection][Close][%s/%s] --> Closed!", p.Url, p.Id)
+	if isLogEnabled(p.Logger, LogInfo) {
+		logAt(p.Logger, LogInfo, "[RedisConnection][Close][%s/%s] --> Closed!", p.Url, p.Id)
 	}
 
 	return
@@ -116,43 +143,76 @@ func (p *RedisConnection) Close() (err error) {
 // - Returns GetReply()
 //
 func (p *RedisConnection) Cmd(cmd string, args ...interface{}) *redis.Reply {
-	// stop_watch := MakeStopWatch(p, p.Logger, strings.Join([]string{"Cmd", cmd}, " ")).Start()
-	// defer stop_watch.LogDurationAt(log4go.TRACE)
-	// defer stop_watch.Stop()
+	return p.CmdContext(context.Background(), cmd, args...)
+}
+
+//
+// CmdContext calls the given Redis command in a child span of the context:
+// - Calls Append(...)
+// - Returns GetReply()
+//
+func (p *RedisConnection) CmdContext(ctx context.Context, cmd string, args ...interface{}) *redis.Reply {
+	ctx, span := startSpan(ctx, p.Tracer, "redis", cmd, p.Url, func() int { return redisKeyCount(cmd, args) })
+
+	stop_watch := p.startSlowLogWatch(cmd)
+	p.appendContext(ctx, cmd, args...)
+	reply := p.GetReply()
+	p.observeSlowLog(stop_watch, cmd, args)
 
-	p.Append(cmd, args...)
-	return p.GetReply()
+	endSpan(span, redisReplyTypeName(reply), reply.Err)
+	return reply
 }
 
 //
 // Append adds the given call to the pipeline queue.
 // Use GetReply() to read the reply.
 //
+// The call passes through the Hooks first, any hook may abort it.
+//
 func (p *RedisConnection) Append(cmd string, args ...interface{}) {
-	var last_cmd string
-	if log4go.INFO >= minLogLevel(p.Logger) {
-		last_cmd := string(formatArgs(cmd, args))
+	p.appendContext(context.Background(), cmd, args...)
+}
+
+func (p *RedisConnection) appendContext(ctx context.Context, cmd string, args ...interface{}) {
+	if len(p.Hooks) > 0 {
+		call := &RedisHookCall{Connection: p, Cmd: cmd, Args: args, StartedAt: time.Now(), Ctx: ctx}
+		p.hook_queue = append(p.hook_queue, call)
+
+		// Aborted by a hook, GetReply() will return the error
+		if err := p.Hooks.beforeAppend(call); nil != err {
+			return
+		}
+
+		cmd = call.Cmd
+		args = call.Args
+	}
+
+	p.appendCmd(cmd, args...)
+}
+
+func (p *RedisConnection) appendCmd(cmd string, args ...interface{}) {
+	var last_cmd redisLoggedCmd
+	if isLogEnabled(p.Logger, LogInfo) {
+		last_cmd.text, last_cmd.redact = redactionPolicyOr(p.Redaction).FormatCommand(cmd, args)
 		p.cmd_queue = append(p.cmd_queue, last_cmd)
 	}
 
 	// Wrap in a lambda to prevent evaulation, unless logging is enabled ...
-	if log4go.TRACE >= minLogLevel(p.Logger) {
-		p.Logger.Trace("[RedisConnection][Append][%s/%s] Redis Command = '%s'", p.Url, p.Id, last_cmd)
+	if isLogEnabled(p.Logger, LogTrace) {
+		logAt(p.Logger, LogTrace, "[RedisConnection][Append][%s/%s] Redis Command = '%s'", p.Url, p.Id, last_cmd.text)
 	}
 
 	// If the connection is not open, then open it
 	if !p.IsOpen() {
 		// Did opening the connection fail?
 		if err := p.Open(); nil != err {
-			p.Logger.Warn("[RedisConnection][Append][%s/%s] Redis Command = '%s' --> Error = %v", p.Url, p.Id, last_cmd, err)
+			logAt(p.Logger, LogWarning, "[RedisConnection][Append][%s/%s] Redis Command = '%s' --> Error = %v", p.Url, p.Id, last_cmd.text, err)
 			return
 		}
 	}
 
 	// Append the command
-	// stop_watch := MakeStopWatchTags(p, p.Logger, []string{p.Url, p.Id, "Append", cmd}).Start()
 	p.client.Append(cmd, args...)
-	// stop_watch.Stop().LogDurationAt(log4go.FINEST)
 }
 
 //
@@ -160,18 +220,35 @@ func (p *RedisConnection) Append(cmd string, args ...interface{}) {
 // Error reply with PipelineQueueEmptyError is returned,
 // if the pipeline queue is empty.
 //
+// The reply passes through the Hooks before it is returned.
+//
 func (p *RedisConnection) GetReply() *redis.Reply {
+	// No hooks were called for this command
+	if 0 == len(p.hook_queue) {
+		return p.getReply()
+	}
+
+	call := p.hook_queue[0]
+	p.hook_queue = p.hook_queue[1:]
+
+	// Aborted by a hook, nothing was sent to Redis
+	if nil != call.Err {
+		return p.Hooks.afterReply(call, &redis.Reply{Type: redis.ErrorReply, Err: call.Err})
+	}
+
+	return p.Hooks.afterReply(call, p.getReply())
+}
+
+func (p *RedisConnection) getReply() *redis.Reply {
 	// Connection is closed?
 	if !p.IsOpen() {
 		return &redis.Reply{Type: redis.ErrorReply, Err: ErrConnectionIsClosed}
 	}
 
 	// Get the reply from redis
-	// stop_watch := MakeStopWatchTags(p, p.Logger, []string{p.Url, p.Id, "GetReply"}).Start()
 	reply := p.client.GetReply()
-	// stop_watch.Stop().LogDurationAt(log4go.FINEST)
 
-	var first_cmd string
+	var first_cmd redisLoggedCmd
 	switch {
 	case 1 == len(p.cmd_queue):
 		first_cmd = p.cmd_queue[0]
@@ -193,13 +270,13 @@ func (p *RedisConnection) GetReply() *redis.Reply {
 			fallthrough
 		case redis.PipelineQueueEmptyError.Error():
 			// Log the error & break
-			p.Logger.Warn("[RedisConnection][GetReply][%s/%s] Ignored Error from Redis, cmd=%v, Error = %v", p.Url, p.Id, first_cmd, reply.Err)
+			logAt(p.Logger, LogWarning, "[RedisConnection][GetReply][%s/%s] Ignored Error from Redis, cmd=%v, Error = %v", p.Url, p.Id, first_cmd.text, reply.Err)
 			break
 
 		default:
 			// All other errors are fatal!
 			// Close the connection and log the error
-			p.Logger.Error("[RedisConnection][GetReply][%s/%s] Fatal Error from Redis, cmd=%v, Error = %v", p.Url, p.Id, first_cmd, reply.Err)
+			logAt(p.Logger, LogError, "[RedisConnection][GetReply][%s/%s] Fatal Error from Redis, cmd=%v, Error = %v", p.Url, p.Id, first_cmd.text, reply.Err)
 			p.Close()
 		}
 	} else {
@@ -210,8 +287,8 @@ func (p *RedisConnection) GetReply() *redis.Reply {
 	return reply
 }
 
-func (p *RedisConnection) logReply(cmd, reply_depth string, reply *redis.Reply) {
-	if log4go.INFO < minLogLevel(p.Logger) {
+func (p *RedisConnection) logReply(cmd redisLoggedCmd, reply_depth string, reply *redis.Reply) {
+	if !isLogEnabled(p.Logger, LogInfo) {
 		return
 	}
 
@@ -219,36 +296,37 @@ func (p *RedisConnection) logReply(cmd, reply_depth string, reply *redis.Reply)
 	switch reply.Type {
 	case redis.StatusReply:
 		b, _ := reply.Bool()
-		p.Logger.Info("[RedisConnection][GetReply][%s/%s] Redis Reply[%v], Cmd=%v, Reply.Type=StatusReply, Reply.Status=%v", p.Url, p.Id, reply_depth, cmd, b)
+		logAt(p.Logger, LogInfo, "[RedisConnection][GetReply][%s/%s] Redis Reply[%v], Cmd=%v, Reply.Type=StatusReply, Reply.Status=%v", p.Url, p.Id, reply_depth, cmd.text, b)
 		return
 
 	case redis.ErrorReply:
-		p.Logger.Info("[RedisConnection][GetReply][%s/%s] Redis Reply[%v], Cmd=%v, Reply.Type=ErrorReply, Reply.Err=%v", p.Url, p.Id, reply_depth, cmd, reply.Err)
+		logAt(p.Logger, LogInfo, "[RedisConnection][GetReply][%s/%s] Redis Reply[%v], Cmd=%v, Reply.Type=ErrorReply, Reply.Err=%v", p.Url, p.Id, reply_depth, cmd.text, reply.Err)
 		return
 
 	case redis.IntegerReply:
 		i, _ := reply.Int64()
-		p.Logger.Info("[RedisConnection][GetReply][%s/%s] Redis Reply[%v], Cmd=%v, Reply.Type=IntegerReply, Reply.Int=%v", p.Url, p.Id, reply_depth, cmd, i)
+		logAt(p.Logger, LogInfo, "[RedisConnection][GetReply][%s/%s] Redis Reply[%v], Cmd=%v, Reply.Type=IntegerReply, Reply.Int=%v", p.Url, p.Id, reply_depth, cmd.text, i)
 		return
 
 	case redis.NilReply:
-		p.Logger.Info("[RedisConnection][GetReply][%s/%s] Redis Reply[%v], Cmd=%v, Reply.Type=NilReply", p.Url, p.Id, reply_depth, cmd)
+		logAt(p.Logger, LogInfo, "[RedisConnection][GetReply][%s/%s] Redis Reply[%v], Cmd=%v, Reply.Type=NilReply", p.Url, p.Id, reply_depth, cmd.text)
 		return
 
 	case redis.BulkReply:
 		b, _ := reply.Bytes()
-		p.Logger.Info("[RedisConnection][GetReply][%s/%s] Redis Reply[%v], Cmd=%v, Reply.Type=BulkReply, Reply.Str=%v, Reply.Bytes=%v", p.Url, p.Id, reply_depth, cmd, string(b), b)
+		str := redactionPolicyOr(p.Redaction).FormatValue(cmd.redact, b)
+		logAt(p.Logger, LogInfo, "[RedisConnection][GetReply][%s/%s] Redis Reply[%v], Cmd=%v, Reply.Type=BulkReply, Reply.Str=%v, Reply.Length=%v", p.Url, p.Id, reply_depth, cmd.text, str, len(b))
 		return
 
 	case redis.MultiReply:
-		p.Logger.Info("[RedisConnection][GetReply][%s/%s] Redis Reply[%v], Cmd=%v, Reply.Type=MultiReply, Reply.Elems.Count=%v", p.Url, p.Id, reply_depth, cmd, len(reply.Elems))
+		logAt(p.Logger, LogInfo, "[RedisConnection][GetReply][%s/%s] Redis Reply[%v], Cmd=%v, Reply.Type=MultiReply, Reply.Elems.Count=%v", p.Url, p.Id, reply_depth, cmd.text, len(reply.Elems))
 		for i, elem := range reply.Elems {
 			p.logReply(cmd, fmt.Sprintf("%s->%d", reply_depth, i), elem)
 		}
 		return
 
 	default:
-		p.Logger.Info("[RedisConnection][GetReply][%s/%s] Redis Reply[%v], Cmd=%v, Reply=%#v", p.Url, p.Id, reply_depth, cmd, reply)
+		logAt(p.Logger, LogInfo, "[RedisConnection][GetReply][%s/%s] Redis Reply[%v], Cmd=%v, Reply.Type=%v", p.Url, p.Id, reply_depth, cmd.text, reply.Type)
 		return
 	}
 }
@@ -340,12 +418,14 @@ func (p *RedisConnection) HashFieldsExist(key string, fields ...string) ([]bool,
 func (p *RedisConnection) Client() (*redis.Client, error) {
 	// Is a saved connection available?
 	if p.IsOpen() {
-		p.Logger.Trace("[RedisConnection][Client][%s/%s] --> Found Opened Connection!", p.Url, p.Id)
+		if isLogEnabled(p.Logger, LogTrace) {
+			logAt(p.Logger, LogTrace, "[RedisConnection][Client][%s/%s] --> Found Opened Connection!", p.Url, p.Id)
+		}
 
 		// Return the connection
 		return p.client, nil
 	} else {
-		p.Logger.Warn("[RedisConnection][Client][%s/%s] --> Found Closed Connection!", p.Url, p.Id)
+		logAt(p.Logger, LogWarning, "[RedisConnection][Client][%s/%s] --> Found Closed Connection!", p.Url, p.Id)
 	}
 
 	// Open a new connection to redis
@@ -374,8 +454,10 @@ func (p *RedisConnection) Ping() error {
 func (p *RedisConnection) IsOpen() bool {
 	output := nil != p.client
 
-	// Debug logging
-	p.Logger.Trace("[RedisConnection][IsOpen][%s/%s] --> %v", p.Url, p.Id, output)
+	// Debug logging, guarded so the args aren't boxed on every call
+	if isLogEnabled(p.Logger, LogTrace) {
+		logAt(p.Logger, LogTrace, "[RedisConnection][IsOpen][%s/%s] --> %v", p.Url, p.Id, output)
+	}
 
 	return output
 }
@@ -386,8 +468,10 @@ func (p *RedisConnection) IsOpen() bool {
 func (p *RedisConnection) IsClosed() bool {
 	output := nil == p.client
 
-	// Debug logging
-	p.Logger.Trace("[RedisConnection][IsClosed][%s/%s] --> %v", p.Url, p.Id, output)
+	// Debug logging, guarded so the args aren't boxed on every call
+	if isLogEnabled(p.Logger, LogTrace) {
+		logAt(p.Logger, LogTrace, "[RedisConnection][IsClosed][%s/%s] --> %v", p.Url, p.Id, output)
+	}
 
 	return output
 }
@@ -407,7 +491,7 @@ func (p *RedisConnection) Open() error {
 	// Check for errors
 	if nil != err {
 		// Log the event
-		p.Logger.Error("[RedisConnection][Open][%s/%s] --> Error = %v", p.Url, p.Id, err)
+		logAt(p.Logger, LogError, "[RedisConnection][Open][%s/%s] --> Error = %v", p.Url, p.Id, err)
 
 		// Return the error
 		return err
@@ -417,8 +501,8 @@ func (p *RedisConnection) Open() error {
 	p.client = client
 
 	// Log the event
-	if log4go.INFO >= minLogLevel(p.Logger) {
-		p.Logger.Info("[RedisConnection][Open][%s/%s] --> Opened!", p.Url, p.Id)
+	if isLogEnabled(p.Logger, LogInfo) {
+		logAt(p.Logger, LogInfo, "[RedisConnection][Open][%s/%s] --> Opened!", p.Url, p.Id)
 	}
 
 	// Return nil
@@ -498,47 +582,3 @@ func formatArg(v interface{}) []byte {
 	b = append(b, delim...)
 	return b
 }
-
-// createRequest creates a request string from the given requests.
-func formatArgs(cmd string, args ...interface{}) []byte {
-	var total []byte
-
-	var s []byte
-
-	// Calculate number of arguments.
-	argsLen := 1
-	for _, arg := range args {
-		kind := reflect.TypeOf(arg).Kind()
-		switch kind {
-		case reflect.Slice:
-			argsLen += reflect.ValueOf(arg).Len()
-		case reflect.Map:
-			argsLen += reflect.ValueOf(arg).Len() * 2
-		default:
-			argsLen++
-		}
-	}
-
-	var delim []byte = []byte{' '}
-
-	// number of arguments
-	// s = append(s, '*')
-	// s = append(s, []byte(strconv.Itoa(argsLen))...)
-	// s = append(s, delim...)
-
-	// command
-	// s = append(s, '$')
-	// s = append(s, []byte(strconv.Itoa(len(cmd)))...)
-	// s = append(s, delim...)
-	s = append(s, []byte(cmd)...)
-	s = append(s, delim...)
-
-	// arguments
-	for _, arg := range args {
-		s = append(s, formatArg(arg)...)
-	}
-
-	total = append(total, s...)
-
-	return total
-}
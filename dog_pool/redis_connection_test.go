@@ -17,10 +17,10 @@ func TestRedisConnectionSpecs(t *testing.T) {
 
 // Helpers
 func RedisConnectionSpecs(c gospec.Context) {
-	var redis_connection_logger = log4go.NewDefaultLogger(log4go.CRITICAL)
+	var redis_connection_logger = MakeLog4goLogger(log4go.NewDefaultLogger(log4go.CRITICAL))
 
 	c.Specify("[RedisConnection] Clone a connection", func() {
-		connection := &RedisConnection{Url: "127.0.0.1:6990", Id: "Bob", Logger: &redis_connection_logger}
+		connection := &RedisConnection{Url: "127.0.0.1:6990", Id: "Bob", Logger: redis_connection_logger}
 		defer connection.Close()
 		c.Expect(connection.IsOpen(), gospec.Equals, false)
 
@@ -33,7 +33,7 @@ func RedisConnectionSpecs(c gospec.Context) {
 	})
 
 	c.Specify("[RedisConnection] New connection is not open", func() {
-		connection := RedisConnection{Url: "127.0.0.1:6990", Logger: &redis_connection_logger}
+		connection := RedisConnection{Url: "127.0.0.1:6990", Logger: redis_connection_logger}
 		defer connection.Close()
 
 		open := connection.IsOpen()
@@ -46,7 +46,7 @@ func RedisConnectionSpecs(c gospec.Context) {
 	})
 
 	c.Specify("[RedisConnection] Opening connection to Invalid Host/Port has errors", func() {
-		connection := RedisConnection{Url: "127.0.0.1:6991", Logger: &redis_connection_logger}
+		connection := RedisConnection{Url: "127.0.0.1:6991", Logger: redis_connection_logger}
 		defer connection.Close()
 
 		// The server is not running ...
@@ -59,8 +59,8 @@ func RedisConnectionSpecs(c gospec.Context) {
 	})
 
 	c.Specify("[RedisConnection] Opening connection to Valid Host/Port has no errors", func() {
-		logger := log4go.NewDefaultLogger(log4go.CRITICAL)
-		server, err := StartRedisServer(&logger)
+		logger := MakeLog4goLogger(log4go.NewDefaultLogger(log4go.CRITICAL))
+		server, err := StartRedisServer(logger)
 		if nil != err {
 			panic(err)
 		}
@@ -72,8 +72,8 @@ func RedisConnectionSpecs(c gospec.Context) {
 	})
 
 	c.Specify("[RedisConnection][KeysExist] Checks if keys exists", func() {
-		logger := log4go.NewDefaultLogger(log4go.CRITICAL)
-		server, server_err := StartRedisServer(&logger)
+		logger := MakeLog4goLogger(log4go.NewDefaultLogger(log4go.CRITICAL))
+		server, server_err := StartRedisServer(logger)
 		if nil != server_err {
 			panic(server_err)
 		}
@@ -102,8 +102,8 @@ func RedisConnectionSpecs(c gospec.Context) {
 	})
 
 	c.Specify("[RedisConnection][HashFieldsExist] Checks if keys exists", func() {
-		logger := log4go.NewDefaultLogger(log4go.CRITICAL)
-		server, server_err := StartRedisServer(&logger)
+		logger := MakeLog4goLogger(log4go.NewDefaultLogger(log4go.CRITICAL))
+		server, server_err := StartRedisServer(logger)
 		if nil != server_err {
 			panic(server_err)
 		}
@@ -140,8 +140,8 @@ func RedisConnectionSpecs(c gospec.Context) {
 	})
 
 	c.Specify("[RedisConnection] Ping (-->Cmd-->Append+GetReply) (re-)opens the connection automatically", func() {
-		logger := log4go.NewDefaultLogger(log4go.INFO)
-		server, err := StartRedisServer(&logger)
+		logger := MakeLog4goLogger(log4go.NewDefaultLogger(log4go.INFO))
+		server, err := StartRedisServer(logger)
 		if nil != err {
 			panic(err)
 		}
@@ -167,11 +167,45 @@ func RedisConnectionSpecs(c gospec.Context) {
 		c.Expect(server.Connection().IsClosed(), gospec.Equals, false)
 	})
 
+	c.Specify("[RedisConnection][IsOpen] Doesn't allocate with the default logger", func() {
+		connection := &RedisConnection{Url: "127.0.0.1:6990", Id: "Bob"}
+		defer connection.Close()
+
+		allocs := testing.AllocsPerRun(100, func() {
+			connection.IsOpen()
+			connection.IsClosed()
+		})
+		c.Expect(allocs, gospec.Equals, float64(0))
+	})
+
+	c.Specify("[RedisConnection][Cmd] Doesn't allocate for logging or tracing by default", func() {
+		server, err := StartRedisServer(nil)
+		if nil != err {
+			panic(err)
+		}
+		defer server.Close()
+
+		connection := server.Connection()
+		c.Expect(connection.Ping(), gospec.Equals, nil)
+
+		// Allocations of the client, everything else is logging & tracing
+		append_cmd, get_reply := connection.client.Append, connection.client.GetReply
+		client := testing.AllocsPerRun(100, func() {
+			append_cmd("GET", "Key")
+			get_reply()
+		})
+
+		allocs := testing.AllocsPerRun(100, func() {
+			connection.Cmd("GET", "Key")
+		})
+		c.Expect(allocs, gospec.Satisfies, allocs <= client)
+	})
+
 }
 
 func Benchmark_Get_RedisConnection(b *testing.B) {
-	logger := log4go.NewDefaultLogger(log4go.CRITICAL)
-	server, err := StartRedisServer(&logger)
+	logger := MakeLog4goLogger(log4go.NewDefaultLogger(log4go.CRITICAL))
+	server, err := StartRedisServer(logger)
 	if nil != err {
 		panic(err)
 	}
@@ -186,8 +220,8 @@ func Benchmark_Get_RedisConnection(b *testing.B) {
 }
 
 func Benchmark_Set_RedisConnection(b *testing.B) {
-	logger := log4go.NewDefaultLogger(log4go.CRITICAL)
-	server, err := StartRedisServer(&logger)
+	logger := MakeLog4goLogger(log4go.NewDefaultLogger(log4go.CRITICAL))
+	server, err := StartRedisServer(logger)
 	if nil != err {
 		panic(err)
 	}
@@ -200,8 +234,8 @@ func Benchmark_Set_RedisConnection(b *testing.B) {
 }
 
 func Benchmark_Del_CacheMiss_RedisConnection(b *testing.B) {
-	logger := log4go.NewDefaultLogger(log4go.CRITICAL)
-	server, err := StartRedisServer(&logger)
+	logger := MakeLog4goLogger(log4go.NewDefaultLogger(log4go.CRITICAL))
+	server, err := StartRedisServer(logger)
 	if nil != err {
 		panic(err)
 	}
@@ -214,8 +248,8 @@ func Benchmark_Del_CacheMiss_RedisConnection(b *testing.B) {
 }
 
 func Benchmark_SetGet_RedisConnection(b *testing.B) {
-	logger := log4go.NewDefaultLogger(log4go.CRITICAL)
-	server, err := StartRedisServer(&logger)
+	logger := MakeLog4goLogger(log4go.NewDefaultLogger(log4go.CRITICAL))
+	server, err := StartRedisServer(logger)
 	if nil != err {
 		panic(err)
 	}
@@ -229,8 +263,8 @@ func Benchmark_SetGet_RedisConnection(b *testing.B) {
 }
 
 func Benchmark_Bit_Get_RedisConnection(b *testing.B) {
-	logger := log4go.NewDefaultLogger(log4go.CRITICAL)
-	server, err := StartRedisServer(&logger)
+	logger := MakeLog4goLogger(log4go.NewDefaultLogger(log4go.CRITICAL))
+	server, err := StartRedisServer(logger)
 	if nil != err {
 		panic(err)
 	}
@@ -247,8 +281,8 @@ func Benchmark_Bit_Get_RedisConnection(b *testing.B) {
 }
 
 func Benchmark_BitOp_And_RedisConnection(b *testing.B) {
-	logger := log4go.NewDefaultLogger(log4go.CRITICAL)
-	server, err := StartRedisServer(&logger)
+	logger := MakeLog4goLogger(log4go.NewDefaultLogger(log4go.CRITICAL))
+	server, err := StartRedisServer(logger)
 	if nil != err {
 		panic(err)
 	}
@@ -268,8 +302,8 @@ func Benchmark_BitOp_And_RedisConnection(b *testing.B) {
 }
 
 func Benchmark_BitOp_Or_RedisConnection(b *testing.B) {
-	logger := log4go.NewDefaultLogger(log4go.CRITICAL)
-	server, err := StartRedisServer(&logger)
+	logger := MakeLog4goLogger(log4go.NewDefaultLogger(log4go.CRITICAL))
+	server, err := StartRedisServer(logger)
 	if nil != err {
 		panic(err)
 	}
@@ -289,8 +323,8 @@ func Benchmark_BitOp_Or_RedisConnection(b *testing.B) {
 }
 
 func Benchmark_BitOp_Not_RedisConnection(b *testing.B) {
-	logger := log4go.NewDefaultLogger(log4go.CRITICAL)
-	server, err := StartRedisServer(&logger)
+	logger := MakeLog4goLogger(log4go.NewDefaultLogger(log4go.CRITICAL))
+	server, err := StartRedisServer(logger)
 	if nil != err {
 		panic(err)
 	}
@@ -315,8 +349,8 @@ func Benchmark_BitOp_Not_RedisConnection(b *testing.B) {
 
 // Pre-format the commands as a single string, and compare the results to above
 func Benchmark_BitOp_ComplementSet_RedisConnection(b *testing.B) {
-	logger := log4go.NewDefaultLogger(log4go.CRITICAL)
-	server, err := StartRedisServer(&logger)
+	logger := MakeLog4goLogger(log4go.NewDefaultLogger(log4go.CRITICAL))
+	server, err := StartRedisServer(logger)
 	if nil != err {
 		panic(err)
 	}
@@ -1,6 +1,8 @@
 package dog_pool
 
 import "fmt"
+import "sort"
+import "time"
 import "github.com/RUNDSP/radix/redis"
 
 //
@@ -531,3 +533,354 @@ func (p RedisDsl) HASHES_GETALL_FLOAT64S(keys []string) ([][]*float64, error) {
 
 	return output, nil
 }
+
+//
+// ==================================================
+//
+// Short Hand Conversion for the above HASH_GETALL/HASHES_GETALL operations into maps:
+//
+// ==================================================
+//
+
+// Get the hash key's fields & string values
+func (p RedisDsl) HASH_GETALL_MAP(key string) (map[string]string, error) {
+	return ReplyToStringMap(p.HASH_GETALL(key))
+}
+
+// Get the hash key's fields & int64 values
+func (p RedisDsl) HASH_GETALL_INT64_MAP(key string) (map[string]int64, error) {
+	return ReplyToInt64Map(p.HASH_GETALL(key))
+}
+
+// Get the hash key's fields & float64 values
+func (p RedisDsl) HASH_GETALL_FLOAT64_MAP(key string) (map[string]float64, error) {
+	return ReplyToFloat64Map(p.HASH_GETALL(key))
+}
+
+// Get the fields & string values of several parallel hash keys
+func (p RedisDsl) HASHES_GETALL_MAPS(keys []string) ([]map[string]string, error) {
+	replys := p.HASHES_GETALL(keys)
+
+	output := make([]map[string]string, len(keys))
+	for i, reply := range replys {
+		values, err := ReplyToStringMap(reply)
+		if nil != err {
+			return nil, err
+		}
+		output[i] = values
+	}
+
+	return output, nil
+}
+
+// Get the fields & int64 values of several parallel hash keys
+func (p RedisDsl) HASHES_GETALL_INT64_MAPS(keys []string) ([]map[string]int64, error) {
+	replys := p.HASHES_GETALL(keys)
+
+	output := make([]map[string]int64, len(keys))
+	for i, reply := range replys {
+		values, err := ReplyToInt64Map(reply)
+		if nil != err {
+			return nil, err
+		}
+		output[i] = values
+	}
+
+	return output, nil
+}
+
+// Get the fields & float64 values of several parallel hash keys
+func (p RedisDsl) HASHES_GETALL_FLOAT64_MAPS(keys []string) ([]map[string]float64, error) {
+	replys := p.HASHES_GETALL(keys)
+
+	output := make([]map[string]float64, len(keys))
+	for i, reply := range replys {
+		values, err := ReplyToFloat64Map(reply)
+		if nil != err {
+			return nil, err
+		}
+		output[i] = values
+	}
+
+	return output, nil
+}
+
+//
+// ==================================================
+//
+// Common Redis HASH SET/EXPIRE "X" Operations:
+//
+// ==================================================
+//
+
+// Set the hash key's fields, returns the number of fields added
+func (p RedisDsl) HASH_SET_MAP(key string, values map[string]string) (int64, error) {
+	if len(key) == 0 {
+		return 0, fmt.Errorf("Empty key")
+	}
+
+	fields := make([]string, 0, len(values))
+	for field := range values {
+		fields = append(fields, field)
+	}
+	sort.Strings(fields)
+
+	args := make([]string, 0, 2*len(fields))
+	for _, field := range fields {
+		args = append(args, field, values[field])
+	}
+	return p.Cmd("HSET", key, args).Int64()
+}
+
+// Expire the hash key's fields after the duration, returns the status of each field
+func (p RedisDsl) HASH_FIELDS_EXPIRE(key string, expire_in time.Duration, fields ...string) ([]*int64, error) {
+	if isWholeSeconds(expire_in) {
+		return ReplyToInt64Ptrs(p.Cmd("HEXPIRE", key, int64(expire_in/time.Second), "FIELDS", len(fields), fields))
+	}
+	return ReplyToInt64Ptrs(p.Cmd("HPEXPIRE", key, toRedisMilliseconds(expire_in), "FIELDS", len(fields), fields))
+}
+
+// Get the time left before the hash key's fields expire, in milliseconds
+func (p RedisDsl) HASH_FIELDS_PTTL(key string, fields ...string) ([]*int64, error) {
+	return ReplyToInt64Ptrs(p.Cmd("HPTTL", key, "FIELDS", len(fields), fields))
+}
+
+// Remove the expiry of the hash key's fields
+func (p RedisDsl) HASH_FIELDS_PERSIST(key string, fields ...string) ([]*int64, error) {
+	return ReplyToInt64Ptrs(p.Cmd("HPERSIST", key, "FIELDS", len(fields), fields))
+}
+
+//
+// ==================================================
+//
+// Common Redis LIST "X" Operations:
+//
+// ==================================================
+//
+
+// Push the values onto the head of the list, returns the list's length
+func (p RedisDsl) LIST_LPUSH(key string, values ...string) (int64, error) {
+	return p.Cmd("LPUSH", key, values).Int64()
+}
+
+// Push the values onto the tail of the list, returns the list's length
+func (p RedisDsl) LIST_RPUSH(key string, values ...string) (int64, error) {
+	return p.Cmd("RPUSH", key, values).Int64()
+}
+
+// Get the list's values between start & stop, inclusive
+func (p RedisDsl) LIST_RANGE(key string, start, stop int64) *redis.Reply {
+	return p.Cmd("LRANGE", key, start, stop)
+}
+
+// Trim the list to the values between start & stop, inclusive
+func (p RedisDsl) LIST_TRIM(key string, start, stop int64) error {
+	return p.Cmd("LTRIM", key, start, stop).Err
+}
+
+// Remove count occurrences of the value from the list, returns the number removed
+func (p RedisDsl) LIST_REM(key string, count int64, value string) (int64, error) {
+	return p.Cmd("LREM", key, count, value).Int64()
+}
+
+// Get the list's length
+func (p RedisDsl) LIST_LEN(key string) (int64, error) {
+	return p.Cmd("LLEN", key).Int64()
+}
+
+// Get the list's string values between start & stop, inclusive
+func (p RedisDsl) LIST_RANGE_STRINGS(key string, start, stop int64) ([]string, error) {
+	return ReplyToStrings(p.LIST_RANGE(key, start, stop))
+}
+
+// Get the length of several parallel lists
+func (p RedisDsl) LISTS_LEN(keys []string) ([]int64, error) {
+	for _, key := range keys {
+		p.Append("LLEN", key)
+	}
+
+	output := make([]int64, len(keys))
+	for i := range keys {
+		value, err := p.GetReply().Int64()
+		if nil != err {
+			return nil, err
+		}
+		output[i] = value
+	}
+
+	return output, nil
+}
+
+// Get the string values between start & stop, inclusive, from several parallel lists
+func (p RedisDsl) LISTS_RANGE_STRINGS(keys []string, start, stop int64) ([][]string, error) {
+	for _, key := range keys {
+		p.Append("LRANGE", key, start, stop)
+	}
+
+	output := make([][]string, len(keys))
+	for i := range keys {
+		values, err := ReplyToStrings(p.GetReply())
+		if nil != err {
+			return nil, err
+		}
+		output[i] = values
+	}
+
+	return output, nil
+}
+
+//
+// ==================================================
+//
+// Common Redis SET "X" Operations:
+//
+// ==================================================
+//
+
+// Add the members to the set, returns the number added
+func (p RedisDsl) SET_ADD(key string, members ...string) (int64, error) {
+	return p.Cmd("SADD", key, members).Int64()
+}
+
+// Remove the members from the set, returns the number removed
+func (p RedisDsl) SET_REM(key string, members ...string) (int64, error) {
+	return p.Cmd("SREM", key, members).Int64()
+}
+
+// Is the member in the set?
+func (p RedisDsl) SET_ISMEMBER(key, member string) (bool, error) {
+	if len(key) == 0 {
+		return false, fmt.Errorf("Empty key")
+	}
+
+	return ReplyToBool(p.Cmd("SISMEMBER", key, member))
+}
+
+// Are the members in the set?
+func (p RedisDsl) SET_MISMEMBER(key string, members ...string) ([]bool, error) {
+	if len(key) == 0 {
+		return nil, fmt.Errorf("Empty key")
+	}
+
+	return ReplyToBools(p.Cmd("SMISMEMBER", key, members))
+}
+
+// Get the set's members
+func (p RedisDsl) SET_MEMBERS(key string) *redis.Reply {
+	return p.Cmd("SMEMBERS", key)
+}
+
+// Get the set's size
+func (p RedisDsl) SET_CARD(key string) (int64, error) {
+	return p.Cmd("SCARD", key).Int64()
+}
+
+// Get the members of the sets' intersection
+func (p RedisDsl) SET_INTER(keys ...string) *redis.Reply {
+	return p.Cmd("SINTER", keys)
+}
+
+// Get the members of the sets' union
+func (p RedisDsl) SET_UNION(keys ...string) *redis.Reply {
+	return p.Cmd("SUNION", keys)
+}
+
+// Get the members of the first set missing from the other sets
+func (p RedisDsl) SET_DIFF(keys ...string) *redis.Reply {
+	return p.Cmd("SDIFF", keys)
+}
+
+// Store the sets' intersection in dest, returns its size
+func (p RedisDsl) SET_INTERSTORE(dest string, keys ...string) (int64, error) {
+	return p.Cmd("SINTERSTORE", dest, keys).Int64()
+}
+
+// Store the sets' union in dest, returns its size
+func (p RedisDsl) SET_UNIONSTORE(dest string, keys ...string) (int64, error) {
+	return p.Cmd("SUNIONSTORE", dest, keys).Int64()
+}
+
+// Store the members of the first set missing from the other sets in dest, returns its size
+func (p RedisDsl) SET_DIFFSTORE(dest string, keys ...string) (int64, error) {
+	return p.Cmd("SDIFFSTORE", dest, keys).Int64()
+}
+
+// Get the set's string members
+func (p RedisDsl) SET_MEMBERS_STRINGS(key string) ([]string, error) {
+	return ReplyToStrings(p.SET_MEMBERS(key))
+}
+
+// Get the string members of the sets' intersection
+func (p RedisDsl) SET_INTER_STRINGS(keys ...string) ([]string, error) {
+	return ReplyToStrings(p.SET_INTER(keys...))
+}
+
+// Get the string members of the sets' union
+func (p RedisDsl) SET_UNION_STRINGS(keys ...string) ([]string, error) {
+	return ReplyToStrings(p.SET_UNION(keys...))
+}
+
+// Get the string members of the first set missing from the other sets
+func (p RedisDsl) SET_DIFF_STRINGS(keys ...string) ([]string, error) {
+	return ReplyToStrings(p.SET_DIFF(keys...))
+}
+
+// Is the member in several parallel sets?
+func (p RedisDsl) SETS_ISMEMBER(keys []string, member string) ([]bool, error) {
+	for i, key := range keys {
+		if len(key) == 0 {
+			return nil, fmt.Errorf("Empty key[%d]", i)
+		}
+	}
+	for _, key := range keys {
+		p.Append("SISMEMBER", key, member)
+	}
+
+	output := make([]bool, len(keys))
+	for i := range keys {
+		b, err := ReplyToBool(p.GetReply())
+		if nil != err {
+			return nil, err
+		}
+
+		output[i] = b
+	}
+
+	return output, nil
+}
+
+// Get the size of several parallel sets
+func (p RedisDsl) SETS_CARD(keys []string) ([]int64, error) {
+	for _, key := range keys {
+		p.Append("SCARD", key)
+	}
+
+	output := make([]int64, len(keys))
+	for i := range keys {
+		value, err := p.GetReply().Int64()
+		if nil != err {
+			return nil, err
+		}
+		output[i] = value
+	}
+
+	return output, nil
+}
+
+// Get the string members of several parallel sets
+func (p RedisDsl) SETS_MEMBERS_STRINGS(keys []string) ([][]string, error) {
+	for _, key := range keys {
+		p.Append("SMEMBERS", key)
+	}
+
+	output := make([][]string, len(keys))
+	for i := range keys {
+		members, err := ReplyToStrings(p.GetReply())
+		if nil != err {
+			return nil, err
+		}
+		output[i] = members
+	}
+
+	return output, nil
+}
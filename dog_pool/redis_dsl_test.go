@@ -2,6 +2,7 @@ package dog_pool
 
 import "fmt"
 import "math"
+import "time"
 import "testing"
 import "github.com/orfjackal/gospec/src/gospec"
 import "github.com/alecthomas/log4go"
@@ -29,8 +30,8 @@ func RedisDslSpecs(c gospec.Context) {
 	//
 
 	c.Specify("[RedisDsl][KEY_EXISTS]", func() {
-		logger := log4go.NewDefaultLogger(log4go.CRITICAL)
-		server, err := StartRedisServer(&logger)
+		logger := MakeLog4goLogger(log4go.NewDefaultLogger(log4go.CRITICAL))
+		server, err := StartRedisServer(logger)
 		if nil != err {
 			panic(err)
 		}
@@ -52,8 +53,8 @@ func RedisDslSpecs(c gospec.Context) {
 	})
 
 	c.Specify("[RedisDsl][KEYS_EXIST]", func() {
-		logger := log4go.NewDefaultLogger(log4go.CRITICAL)
-		server, err := StartRedisServer(&logger)
+		logger := MakeLog4goLogger(log4go.NewDefaultLogger(log4go.CRITICAL))
+		server, err := StartRedisServer(logger)
 		if nil != err {
 			panic(err)
 		}
@@ -74,8 +75,8 @@ func RedisDslSpecs(c gospec.Context) {
 	})
 
 	c.Specify("[RedisDsl][HASH_FIELD_EXISTS]", func() {
-		logger := log4go.NewDefaultLogger(log4go.CRITICAL)
-		server, err := StartRedisServer(&logger)
+		logger := MakeLog4goLogger(log4go.NewDefaultLogger(log4go.CRITICAL))
+		server, err := StartRedisServer(logger)
 		if nil != err {
 			panic(err)
 		}
@@ -105,8 +106,8 @@ func RedisDslSpecs(c gospec.Context) {
 	})
 
 	c.Specify("[RedisDsl][HASH_FIELDS_EXIST]", func() {
-		logger := log4go.NewDefaultLogger(log4go.CRITICAL)
-		server, err := StartRedisServer(&logger)
+		logger := MakeLog4goLogger(log4go.NewDefaultLogger(log4go.CRITICAL))
+		server, err := StartRedisServer(logger)
 		if nil != err {
 			panic(err)
 		}
@@ -127,8 +128,8 @@ func RedisDslSpecs(c gospec.Context) {
 	})
 
 	c.Specify("[RedisDsl][HASHES_FIELD_EXISTS]", func() {
-		logger := log4go.NewDefaultLogger(log4go.CRITICAL)
-		server, err := StartRedisServer(&logger)
+		logger := MakeLog4goLogger(log4go.NewDefaultLogger(log4go.CRITICAL))
+		server, err := StartRedisServer(logger)
 		if nil != err {
 			panic(err)
 		}
@@ -157,8 +158,8 @@ func RedisDslSpecs(c gospec.Context) {
 	//
 
 	c.Specify("[RedisDsl][INCR]", func() {
-		logger := log4go.NewDefaultLogger(log4go.CRITICAL)
-		server, err := StartRedisServer(&logger)
+		logger := MakeLog4goLogger(log4go.NewDefaultLogger(log4go.CRITICAL))
+		server, err := StartRedisServer(logger)
 		if nil != err {
 			panic(err)
 		}
@@ -176,8 +177,8 @@ func RedisDslSpecs(c gospec.Context) {
 	})
 
 	c.Specify("[RedisDsl][INCRBY]", func() {
-		logger := log4go.NewDefaultLogger(log4go.CRITICAL)
-		server, err := StartRedisServer(&logger)
+		logger := MakeLog4goLogger(log4go.NewDefaultLogger(log4go.CRITICAL))
+		server, err := StartRedisServer(logger)
 		if nil != err {
 			panic(err)
 		}
@@ -195,8 +196,8 @@ func RedisDslSpecs(c gospec.Context) {
 	})
 
 	c.Specify("[RedisDsl][INCRBYFLOAT]", func() {
-		logger := log4go.NewDefaultLogger(log4go.CRITICAL)
-		server, err := StartRedisServer(&logger)
+		logger := MakeLog4goLogger(log4go.NewDefaultLogger(log4go.CRITICAL))
+		server, err := StartRedisServer(logger)
 		if nil != err {
 			panic(err)
 		}
@@ -214,8 +215,8 @@ func RedisDslSpecs(c gospec.Context) {
 	})
 
 	c.Specify("[RedisDsl][DECR]", func() {
-		logger := log4go.NewDefaultLogger(log4go.CRITICAL)
-		server, err := StartRedisServer(&logger)
+		logger := MakeLog4goLogger(log4go.NewDefaultLogger(log4go.CRITICAL))
+		server, err := StartRedisServer(logger)
 		if nil != err {
 			panic(err)
 		}
@@ -233,8 +234,8 @@ func RedisDslSpecs(c gospec.Context) {
 	})
 
 	c.Specify("[RedisDsl][DECRBY]", func() {
-		logger := log4go.NewDefaultLogger(log4go.CRITICAL)
-		server, err := StartRedisServer(&logger)
+		logger := MakeLog4goLogger(log4go.NewDefaultLogger(log4go.CRITICAL))
+		server, err := StartRedisServer(logger)
 		if nil != err {
 			panic(err)
 		}
@@ -252,8 +253,8 @@ func RedisDslSpecs(c gospec.Context) {
 	})
 
 	c.Specify("[RedisDsl][DECRBYFLOAT]", func() {
-		logger := log4go.NewDefaultLogger(log4go.CRITICAL)
-		server, err := StartRedisServer(&logger)
+		logger := MakeLog4goLogger(log4go.NewDefaultLogger(log4go.CRITICAL))
+		server, err := StartRedisServer(logger)
 		if nil != err {
 			panic(err)
 		}
@@ -271,8 +272,8 @@ func RedisDslSpecs(c gospec.Context) {
 	})
 
 	c.Specify("[RedisDsl][HASH_INCR]", func() {
-		logger := log4go.NewDefaultLogger(log4go.CRITICAL)
-		server, err := StartRedisServer(&logger)
+		logger := MakeLog4goLogger(log4go.NewDefaultLogger(log4go.CRITICAL))
+		server, err := StartRedisServer(logger)
 		if nil != err {
 			panic(err)
 		}
@@ -290,8 +291,8 @@ func RedisDslSpecs(c gospec.Context) {
 	})
 
 	c.Specify("[RedisDsl][HASH_INCRBY]", func() {
-		logger := log4go.NewDefaultLogger(log4go.CRITICAL)
-		server, err := StartRedisServer(&logger)
+		logger := MakeLog4goLogger(log4go.NewDefaultLogger(log4go.CRITICAL))
+		server, err := StartRedisServer(logger)
 		if nil != err {
 			panic(err)
 		}
@@ -309,8 +310,8 @@ func RedisDslSpecs(c gospec.Context) {
 	})
 
 	c.Specify("[RedisDsl][HASH_INCRBYFLOAT]", func() {
-		logger := log4go.NewDefaultLogger(log4go.CRITICAL)
-		server, err := StartRedisServer(&logger)
+		logger := MakeLog4goLogger(log4go.NewDefaultLogger(log4go.CRITICAL))
+		server, err := StartRedisServer(logger)
 		if nil != err {
 			panic(err)
 		}
@@ -328,8 +329,8 @@ func RedisDslSpecs(c gospec.Context) {
 	})
 
 	c.Specify("[RedisDsl][HASH_DECR]", func() {
-		logger := log4go.NewDefaultLogger(log4go.CRITICAL)
-		server, err := StartRedisServer(&logger)
+		logger := MakeLog4goLogger(log4go.NewDefaultLogger(log4go.CRITICAL))
+		server, err := StartRedisServer(logger)
 		if nil != err {
 			panic(err)
 		}
@@ -347,8 +348,8 @@ func RedisDslSpecs(c gospec.Context) {
 	})
 
 	c.Specify("[RedisDsl][HASH_DECRBY]", func() {
-		logger := log4go.NewDefaultLogger(log4go.CRITICAL)
-		server, err := StartRedisServer(&logger)
+		logger := MakeLog4goLogger(log4go.NewDefaultLogger(log4go.CRITICAL))
+		server, err := StartRedisServer(logger)
 		if nil != err {
 			panic(err)
 		}
@@ -366,8 +367,8 @@ func RedisDslSpecs(c gospec.Context) {
 	})
 
 	c.Specify("[RedisDsl][HASH_DECRBYFLOAT]", func() {
-		logger := log4go.NewDefaultLogger(log4go.CRITICAL)
-		server, err := StartRedisServer(&logger)
+		logger := MakeLog4goLogger(log4go.NewDefaultLogger(log4go.CRITICAL))
+		server, err := StartRedisServer(logger)
 		if nil != err {
 			panic(err)
 		}
@@ -394,8 +395,8 @@ func RedisDslSpecs(c gospec.Context) {
 	//
 
 	c.Specify("[RedisDsl][GET]", func() {
-		logger := log4go.NewDefaultLogger(log4go.CRITICAL)
-		server, err := StartRedisServer(&logger)
+		logger := MakeLog4goLogger(log4go.NewDefaultLogger(log4go.CRITICAL))
+		server, err := StartRedisServer(logger)
 		if nil != err {
 			panic(err)
 		}
@@ -421,8 +422,8 @@ func RedisDslSpecs(c gospec.Context) {
 	})
 
 	c.Specify("[RedisDsl][GET_STRING]", func() {
-		logger := log4go.NewDefaultLogger(log4go.CRITICAL)
-		server, err := StartRedisServer(&logger)
+		logger := MakeLog4goLogger(log4go.NewDefaultLogger(log4go.CRITICAL))
+		server, err := StartRedisServer(logger)
 		if nil != err {
 			panic(err)
 		}
@@ -444,8 +445,8 @@ func RedisDslSpecs(c gospec.Context) {
 	})
 
 	c.Specify("[RedisDsl][GET_INT64]", func() {
-		logger := log4go.NewDefaultLogger(log4go.CRITICAL)
-		server, err := StartRedisServer(&logger)
+		logger := MakeLog4goLogger(log4go.NewDefaultLogger(log4go.CRITICAL))
+		server, err := StartRedisServer(logger)
 		if nil != err {
 			panic(err)
 		}
@@ -467,8 +468,8 @@ func RedisDslSpecs(c gospec.Context) {
 	})
 
 	c.Specify("[RedisDsl][GET_FLOAT64]", func() {
-		logger := log4go.NewDefaultLogger(log4go.CRITICAL)
-		server, err := StartRedisServer(&logger)
+		logger := MakeLog4goLogger(log4go.NewDefaultLogger(log4go.CRITICAL))
+		server, err := StartRedisServer(logger)
 		if nil != err {
 			panic(err)
 		}
@@ -490,8 +491,8 @@ func RedisDslSpecs(c gospec.Context) {
 	})
 
 	c.Specify("[RedisDsl][MGET]", func() {
-		logger := log4go.NewDefaultLogger(log4go.CRITICAL)
-		server, err := StartRedisServer(&logger)
+		logger := MakeLog4goLogger(log4go.NewDefaultLogger(log4go.CRITICAL))
+		server, err := StartRedisServer(logger)
 		if nil != err {
 			panic(err)
 		}
@@ -538,8 +539,8 @@ func RedisDslSpecs(c gospec.Context) {
 	})
 
 	c.Specify("[RedisDsl][MGET_STRINGS]", func() {
-		logger := log4go.NewDefaultLogger(log4go.CRITICAL)
-		server, err := StartRedisServer(&logger)
+		logger := MakeLog4goLogger(log4go.NewDefaultLogger(log4go.CRITICAL))
+		server, err := StartRedisServer(logger)
 		if nil != err {
 			panic(err)
 		}
@@ -567,8 +568,8 @@ func RedisDslSpecs(c gospec.Context) {
 	})
 
 	c.Specify("[RedisDsl][MGET_INT64S]", func() {
-		logger := log4go.NewDefaultLogger(log4go.CRITICAL)
-		server, err := StartRedisServer(&logger)
+		logger := MakeLog4goLogger(log4go.NewDefaultLogger(log4go.CRITICAL))
+		server, err := StartRedisServer(logger)
 		if nil != err {
 			panic(err)
 		}
@@ -596,8 +597,8 @@ func RedisDslSpecs(c gospec.Context) {
 	})
 
 	c.Specify("[RedisDsl][MGET_FLOAT64S]", func() {
-		logger := log4go.NewDefaultLogger(log4go.CRITICAL)
-		server, err := StartRedisServer(&logger)
+		logger := MakeLog4goLogger(log4go.NewDefaultLogger(log4go.CRITICAL))
+		server, err := StartRedisServer(logger)
 		if nil != err {
 			panic(err)
 		}
@@ -625,8 +626,8 @@ func RedisDslSpecs(c gospec.Context) {
 	})
 
 	c.Specify("[RedisDsl][GETBIT]", func() {
-		logger := log4go.NewDefaultLogger(log4go.CRITICAL)
-		server, err := StartRedisServer(&logger)
+		logger := MakeLog4goLogger(log4go.NewDefaultLogger(log4go.CRITICAL))
+		server, err := StartRedisServer(logger)
 		if nil != err {
 			panic(err)
 		}
@@ -653,8 +654,8 @@ func RedisDslSpecs(c gospec.Context) {
 	})
 
 	c.Specify("[RedisDsl][GETBITS]", func() {
-		logger := log4go.NewDefaultLogger(log4go.CRITICAL)
-		server, err := StartRedisServer(&logger)
+		logger := MakeLog4goLogger(log4go.NewDefaultLogger(log4go.CRITICAL))
+		server, err := StartRedisServer(logger)
 		if nil != err {
 			panic(err)
 		}
@@ -712,8 +713,8 @@ func RedisDslSpecs(c gospec.Context) {
 	})
 
 	c.Specify("[RedisDsl][GETBITS_TURNED_ON]", func() {
-		logger := log4go.NewDefaultLogger(log4go.CRITICAL)
-		server, err := StartRedisServer(&logger)
+		logger := MakeLog4goLogger(log4go.NewDefaultLogger(log4go.CRITICAL))
+		server, err := StartRedisServer(logger)
 		if nil != err {
 			panic(err)
 		}
@@ -770,8 +771,8 @@ func RedisDslSpecs(c gospec.Context) {
 	//
 
 	c.Specify("[RedisDsl][HASH_GET]", func() {
-		logger := log4go.NewDefaultLogger(log4go.CRITICAL)
-		server, err := StartRedisServer(&logger)
+		logger := MakeLog4goLogger(log4go.NewDefaultLogger(log4go.CRITICAL))
+		server, err := StartRedisServer(logger)
 		if nil != err {
 			panic(err)
 		}
@@ -797,8 +798,8 @@ func RedisDslSpecs(c gospec.Context) {
 	})
 
 	c.Specify("[RedisDsl][HASH_GET_STRING]", func() {
-		logger := log4go.NewDefaultLogger(log4go.CRITICAL)
-		server, err := StartRedisServer(&logger)
+		logger := MakeLog4goLogger(log4go.NewDefaultLogger(log4go.CRITICAL))
+		server, err := StartRedisServer(logger)
 		if nil != err {
 			panic(err)
 		}
@@ -820,8 +821,8 @@ func RedisDslSpecs(c gospec.Context) {
 	})
 
 	c.Specify("[RedisDsl][HASH_GET_INT64]", func() {
-		logger := log4go.NewDefaultLogger(log4go.CRITICAL)
-		server, err := StartRedisServer(&logger)
+		logger := MakeLog4goLogger(log4go.NewDefaultLogger(log4go.CRITICAL))
+		server, err := StartRedisServer(logger)
 		if nil != err {
 			panic(err)
 		}
@@ -843,8 +844,8 @@ func RedisDslSpecs(c gospec.Context) {
 	})
 
 	c.Specify("[RedisDsl][HASH_GET_FLOAT64]", func() {
-		logger := log4go.NewDefaultLogger(log4go.CRITICAL)
-		server, err := StartRedisServer(&logger)
+		logger := MakeLog4goLogger(log4go.NewDefaultLogger(log4go.CRITICAL))
+		server, err := StartRedisServer(logger)
 		if nil != err {
 			panic(err)
 		}
@@ -866,8 +867,8 @@ func RedisDslSpecs(c gospec.Context) {
 	})
 
 	c.Specify("[RedisDsl][HASH_MGET]", func() {
-		logger := log4go.NewDefaultLogger(log4go.CRITICAL)
-		server, err := StartRedisServer(&logger)
+		logger := MakeLog4goLogger(log4go.NewDefaultLogger(log4go.CRITICAL))
+		server, err := StartRedisServer(logger)
 		if nil != err {
 			panic(err)
 		}
@@ -914,8 +915,8 @@ func RedisDslSpecs(c gospec.Context) {
 	})
 
 	c.Specify("[RedisDsl][HASH_MGET_STRINGS]", func() {
-		logger := log4go.NewDefaultLogger(log4go.CRITICAL)
-		server, err := StartRedisServer(&logger)
+		logger := MakeLog4goLogger(log4go.NewDefaultLogger(log4go.CRITICAL))
+		server, err := StartRedisServer(logger)
 		if nil != err {
 			panic(err)
 		}
@@ -943,8 +944,8 @@ func RedisDslSpecs(c gospec.Context) {
 	})
 
 	c.Specify("[RedisDsl][HASH_MGET_INT64S]", func() {
-		logger := log4go.NewDefaultLogger(log4go.CRITICAL)
-		server, err := StartRedisServer(&logger)
+		logger := MakeLog4goLogger(log4go.NewDefaultLogger(log4go.CRITICAL))
+		server, err := StartRedisServer(logger)
 		if nil != err {
 			panic(err)
 		}
@@ -972,8 +973,8 @@ func RedisDslSpecs(c gospec.Context) {
 	})
 
 	c.Specify("[RedisDsl][HASH_MGET_FLOAT64S]", func() {
-		logger := log4go.NewDefaultLogger(log4go.CRITICAL)
-		server, err := StartRedisServer(&logger)
+		logger := MakeLog4goLogger(log4go.NewDefaultLogger(log4go.CRITICAL))
+		server, err := StartRedisServer(logger)
 		if nil != err {
 			panic(err)
 		}
@@ -1010,8 +1011,8 @@ func RedisDslSpecs(c gospec.Context) {
 	//
 
 	c.Specify("[RedisDsl][HASHES_GET]", func() {
-		logger := log4go.NewDefaultLogger(log4go.CRITICAL)
-		server, err := StartRedisServer(&logger)
+		logger := MakeLog4goLogger(log4go.NewDefaultLogger(log4go.CRITICAL))
+		server, err := StartRedisServer(logger)
 		if nil != err {
 			panic(err)
 		}
@@ -1059,8 +1060,8 @@ func RedisDslSpecs(c gospec.Context) {
 	})
 
 	c.Specify("[RedisDsl][HASHES_GET_STRING]", func() {
-		logger := log4go.NewDefaultLogger(log4go.CRITICAL)
-		server, err := StartRedisServer(&logger)
+		logger := MakeLog4goLogger(log4go.NewDefaultLogger(log4go.CRITICAL))
+		server, err := StartRedisServer(logger)
 		if nil != err {
 			panic(err)
 		}
@@ -1092,8 +1093,8 @@ func RedisDslSpecs(c gospec.Context) {
 	})
 
 	c.Specify("[RedisDsl][HASHES_GET_INT64]", func() {
-		logger := log4go.NewDefaultLogger(log4go.CRITICAL)
-		server, err := StartRedisServer(&logger)
+		logger := MakeLog4goLogger(log4go.NewDefaultLogger(log4go.CRITICAL))
+		server, err := StartRedisServer(logger)
 		if nil != err {
 			panic(err)
 		}
@@ -1125,8 +1126,8 @@ func RedisDslSpecs(c gospec.Context) {
 	})
 
 	c.Specify("[RedisDsl][HASHES_GET_FLOAT64]", func() {
-		logger := log4go.NewDefaultLogger(log4go.CRITICAL)
-		server, err := StartRedisServer(&logger)
+		logger := MakeLog4goLogger(log4go.NewDefaultLogger(log4go.CRITICAL))
+		server, err := StartRedisServer(logger)
 		if nil != err {
 			panic(err)
 		}
@@ -1158,8 +1159,8 @@ func RedisDslSpecs(c gospec.Context) {
 	})
 
 	c.Specify("[RedisDsl][HASHES_MGET]", func() {
-		logger := log4go.NewDefaultLogger(log4go.CRITICAL)
-		server, err := StartRedisServer(&logger)
+		logger := MakeLog4goLogger(log4go.NewDefaultLogger(log4go.CRITICAL))
+		server, err := StartRedisServer(logger)
 		if nil != err {
 			panic(err)
 		}
@@ -1208,8 +1209,8 @@ func RedisDslSpecs(c gospec.Context) {
 	})
 
 	c.Specify("[RedisDsl][HASHES_MGET_STRINGS]", func() {
-		logger := log4go.NewDefaultLogger(log4go.CRITICAL)
-		server, err := StartRedisServer(&logger)
+		logger := MakeLog4goLogger(log4go.NewDefaultLogger(log4go.CRITICAL))
+		server, err := StartRedisServer(logger)
 		if nil != err {
 			panic(err)
 		}
@@ -1248,8 +1249,8 @@ func RedisDslSpecs(c gospec.Context) {
 	})
 
 	c.Specify("[RedisDsl][HASHES_MGET_INT64S]", func() {
-		logger := log4go.NewDefaultLogger(log4go.CRITICAL)
-		server, err := StartRedisServer(&logger)
+		logger := MakeLog4goLogger(log4go.NewDefaultLogger(log4go.CRITICAL))
+		server, err := StartRedisServer(logger)
 		if nil != err {
 			panic(err)
 		}
@@ -1288,8 +1289,8 @@ func RedisDslSpecs(c gospec.Context) {
 	})
 
 	c.Specify("[RedisDsl][HASHES_MGET_FLOAT64S]", func() {
-		logger := log4go.NewDefaultLogger(log4go.CRITICAL)
-		server, err := StartRedisServer(&logger)
+		logger := MakeLog4goLogger(log4go.NewDefaultLogger(log4go.CRITICAL))
+		server, err := StartRedisServer(logger)
 		if nil != err {
 			panic(err)
 		}
@@ -1328,6 +1329,305 @@ func RedisDslSpecs(c gospec.Context) {
 
 	})
 
+	//
+	// ==================================================
+	//
+	// Common Redis LIST "X" Operations:
+	//
+	// ==================================================
+	//
+
+	c.Specify("[RedisDsl][LIST_LPUSH/LIST_RPUSH/LIST_RANGE_STRINGS]", func() {
+		logger := MakeLog4goLogger(log4go.NewDefaultLogger(log4go.CRITICAL))
+		server, err := StartRedisServer(logger)
+		if nil != err {
+			panic(err)
+		}
+		defer server.Close()
+
+		dsl := RedisDsl{server.Connection()}
+		values, err := dsl.LIST_RANGE_STRINGS("Miss", 0, -1)
+		c.Expect(err, gospec.Equals, nil)
+		c.Expect(len(values), gospec.Equals, 0)
+
+		length, err := dsl.LIST_LPUSH("List", "B", "A")
+		c.Expect(err, gospec.Equals, nil)
+		c.Expect(length, gospec.Equals, int64(2))
+		length, err = dsl.LIST_RPUSH("List", "C", "A")
+		c.Expect(err, gospec.Equals, nil)
+		c.Expect(length, gospec.Equals, int64(4))
+
+		values, err = dsl.LIST_RANGE_STRINGS("List", 0, -1)
+		c.Expect(err, gospec.Equals, nil)
+		c.Expect(values, gospec.Equals, []string{"A", "B", "C", "A"})
+	})
+
+	c.Specify("[RedisDsl][LIST_TRIM/LIST_REM/LIST_LEN]", func() {
+		logger := MakeLog4goLogger(log4go.NewDefaultLogger(log4go.CRITICAL))
+		server, err := StartRedisServer(logger)
+		if nil != err {
+			panic(err)
+		}
+		defer server.Close()
+
+		dsl := RedisDsl{server.Connection()}
+		dsl.LIST_RPUSH("List", "A", "B", "A", "C", "D")
+
+		removed, err := dsl.LIST_REM("List", 0, "A")
+		c.Expect(err, gospec.Equals, nil)
+		c.Expect(removed, gospec.Equals, int64(2))
+
+		c.Expect(dsl.LIST_TRIM("List", 0, 1), gospec.Equals, nil)
+		length, err := dsl.LIST_LEN("List")
+		c.Expect(err, gospec.Equals, nil)
+		c.Expect(length, gospec.Equals, int64(2))
+
+		length, err = dsl.LIST_LEN("Miss")
+		c.Expect(err, gospec.Equals, nil)
+		c.Expect(length, gospec.Equals, int64(0))
+	})
+
+	c.Specify("[RedisDsl][LISTS_LEN/LISTS_RANGE_STRINGS]", func() {
+		logger := MakeLog4goLogger(log4go.NewDefaultLogger(log4go.CRITICAL))
+		server, err := StartRedisServer(logger)
+		if nil != err {
+			panic(err)
+		}
+		defer server.Close()
+
+		dsl := RedisDsl{server.Connection()}
+		dsl.LIST_RPUSH("List A", "A", "B")
+		dsl.LIST_RPUSH("List B", "C")
+
+		lengths, err := dsl.LISTS_LEN([]string{"List A", "List B", "Miss"})
+		c.Expect(err, gospec.Equals, nil)
+		c.Expect(lengths, gospec.Equals, []int64{2, 1, 0})
+
+		values, err := dsl.LISTS_RANGE_STRINGS([]string{"List A", "List B", "Miss"}, 0, 0)
+		c.Expect(err, gospec.Equals, nil)
+		c.Expect(values, gospec.Equals, [][]string{{"A"}, {"C"}, {}})
+	})
+
+	//
+	// ==================================================
+	//
+	// Common Redis SET "X" Operations:
+	//
+	// ==================================================
+	//
+
+	c.Specify("[RedisDsl][SET_ADD/SET_REM/SET_CARD/SET_MEMBERS_STRINGS]", func() {
+		logger := MakeLog4goLogger(log4go.NewDefaultLogger(log4go.CRITICAL))
+		server, err := StartRedisServer(logger)
+		if nil != err {
+			panic(err)
+		}
+		defer server.Close()
+
+		dsl := RedisDsl{server.Connection()}
+		added, err := dsl.SET_ADD("Set", "A", "B", "A")
+		c.Expect(err, gospec.Equals, nil)
+		c.Expect(added, gospec.Equals, int64(2))
+
+		removed, err := dsl.SET_REM("Set", "B", "Miss")
+		c.Expect(err, gospec.Equals, nil)
+		c.Expect(removed, gospec.Equals, int64(1))
+
+		size, err := dsl.SET_CARD("Set")
+		c.Expect(err, gospec.Equals, nil)
+		c.Expect(size, gospec.Equals, int64(1))
+
+		members, err := dsl.SET_MEMBERS_STRINGS("Set")
+		c.Expect(err, gospec.Equals, nil)
+		c.Expect(members, gospec.Equals, []string{"A"})
+	})
+
+	c.Specify("[RedisDsl][SET_ISMEMBER/SET_MISMEMBER]", func() {
+		logger := MakeLog4goLogger(log4go.NewDefaultLogger(log4go.CRITICAL))
+		server, err := StartRedisServer(logger)
+		if nil != err {
+			panic(err)
+		}
+		defer server.Close()
+
+		dsl := RedisDsl{server.Connection()}
+		dsl.SET_ADD("Set", "A", "B")
+
+		value, err := dsl.SET_ISMEMBER("Set", "A")
+		c.Expect(err, gospec.Equals, nil)
+		c.Expect(value, gospec.Equals, true)
+
+		value, err = dsl.SET_ISMEMBER("Set", "Miss")
+		c.Expect(err, gospec.Equals, nil)
+		c.Expect(value, gospec.Equals, false)
+
+		values, err := dsl.SET_MISMEMBER("Set", "B", "Miss")
+		c.Expect(err, gospec.Equals, nil)
+		c.Expect(values, gospec.Equals, []bool{true, false})
+
+		value, err = dsl.SET_ISMEMBER("", "A")
+		c.Expect(err, gospec.Satisfies, nil != err)
+		c.Expect(value, gospec.Equals, false)
+	})
+
+	c.Specify("[RedisDsl][SET_INTER_STRINGS/SET_UNION_STRINGS/SET_DIFF_STRINGS/SET_DIFFSTORE]", func() {
+		logger := MakeLog4goLogger(log4go.NewDefaultLogger(log4go.CRITICAL))
+		server, err := StartRedisServer(logger)
+		if nil != err {
+			panic(err)
+		}
+		defer server.Close()
+
+		dsl := RedisDsl{server.Connection()}
+		dsl.SET_ADD("Set A", "A", "B")
+		dsl.SET_ADD("Set B", "B", "C")
+
+		members, err := dsl.SET_INTER_STRINGS("Set A", "Set B")
+		c.Expect(err, gospec.Equals, nil)
+		c.Expect(members, gospec.Equals, []string{"B"})
+
+		members, err = dsl.SET_UNION_STRINGS("Set A", "Set B")
+		c.Expect(err, gospec.Equals, nil)
+		c.Expect(len(members), gospec.Equals, 3)
+
+		members, err = dsl.SET_DIFF_STRINGS("Set A", "Set B")
+		c.Expect(err, gospec.Equals, nil)
+		c.Expect(members, gospec.Equals, []string{"A"})
+
+		size, err := dsl.SET_DIFFSTORE("Set D", "Set B", "Set A")
+		c.Expect(err, gospec.Equals, nil)
+		c.Expect(size, gospec.Equals, int64(1))
+		size, err = dsl.SET_UNIONSTORE("Set U", "Set A", "Set B")
+		c.Expect(err, gospec.Equals, nil)
+		c.Expect(size, gospec.Equals, int64(3))
+		size, err = dsl.SET_INTERSTORE("Set I", "Set A", "Set B")
+		c.Expect(err, gospec.Equals, nil)
+		c.Expect(size, gospec.Equals, int64(1))
+	})
+
+	c.Specify("[RedisDsl][SETS_ISMEMBER] Pipelines a SISMEMBER per key", func() {
+		client := &recordingRedisClient{}
+		dsl := RedisDsl{client}
+
+		values, err := dsl.SETS_ISMEMBER([]string{"Set A", "Set B"}, "Bob")
+		c.Expect(err, gospec.Equals, nil)
+		c.Expect(values, gospec.Equals, []bool{false, false})
+		c.Expect(client.cmds, gospec.Equals, []string{"SISMEMBER", "SISMEMBER"})
+		c.Expect(client.args, gospec.Equals, [][]string{{"Set A", "Bob"}, {"Set B", "Bob"}})
+
+		values, err = dsl.SETS_ISMEMBER([]string{"Set A", ""}, "Bob")
+		c.Expect(err, gospec.Satisfies, nil != err)
+		c.Expect(len(client.cmds), gospec.Equals, 2)
+	})
+
+	c.Specify("[RedisDsl][SETS_ISMEMBER/SETS_CARD/SETS_MEMBERS_STRINGS]", func() {
+		logger := MakeLog4goLogger(log4go.NewDefaultLogger(log4go.CRITICAL))
+		server, err := StartRedisServer(logger)
+		if nil != err {
+			panic(err)
+		}
+		defer server.Close()
+
+		dsl := RedisDsl{server.Connection()}
+		dsl.SET_ADD("Set A", "Bob")
+		dsl.SET_ADD("Set B", "Gary", "George")
+
+		values, err := dsl.SETS_ISMEMBER([]string{"Set A", "Set B", "Miss"}, "Bob")
+		c.Expect(err, gospec.Equals, nil)
+		c.Expect(values, gospec.Equals, []bool{true, false, false})
+
+		sizes, err := dsl.SETS_CARD([]string{"Set A", "Set B", "Miss"})
+		c.Expect(err, gospec.Equals, nil)
+		c.Expect(sizes, gospec.Equals, []int64{1, 2, 0})
+
+		members, err := dsl.SETS_MEMBERS_STRINGS([]string{"Set A", "Miss"})
+		c.Expect(err, gospec.Equals, nil)
+		c.Expect(members, gospec.Equals, [][]string{{"Bob"}, {}})
+	})
+
+	//
+	// ==================================================
+	//
+	// Common Redis HASH GETALL/SET/EXPIRE "X" Operations:
+	//
+	// ==================================================
+	//
+
+	c.Specify("[RedisDsl][HASH_SET_MAP/HASH_GETALL_MAP]", func() {
+		logger := MakeLog4goLogger(log4go.NewDefaultLogger(log4go.CRITICAL))
+		server, err := StartRedisServer(logger)
+		if nil != err {
+			panic(err)
+		}
+		defer server.Close()
+
+		dsl := RedisDsl{server.Connection()}
+		values, err := dsl.HASH_GETALL_MAP("Miss")
+		c.Expect(err, gospec.Equals, nil)
+		c.Expect(len(values), gospec.Equals, 0)
+
+		added, err := dsl.HASH_SET_MAP("Hash", map[string]string{"Bob": "123", "Gary": "456.5"})
+		c.Expect(err, gospec.Equals, nil)
+		c.Expect(added, gospec.Equals, int64(2))
+
+		values, err = dsl.HASH_GETALL_MAP("Hash")
+		c.Expect(err, gospec.Equals, nil)
+		c.Expect(values, gospec.Equals, map[string]string{"Bob": "123", "Gary": "456.5"})
+
+		floats, err := dsl.HASH_GETALL_FLOAT64_MAP("Hash")
+		c.Expect(err, gospec.Equals, nil)
+		c.Expect(floats, gospec.Equals, map[string]float64{"Bob": 123, "Gary": 456.5})
+
+		_, err = dsl.HASH_GETALL_INT64_MAP("Hash")
+		c.Expect(err, gospec.Satisfies, nil != err)
+
+		_, err = dsl.HASH_SET_MAP("", map[string]string{"Bob": "123"})
+		c.Expect(err, gospec.Satisfies, nil != err)
+	})
+
+	c.Specify("[RedisDsl][HASHES_GETALL_MAPS/HASHES_GETALL_INT64_MAPS/HASHES_GETALL_FLOAT64_MAPS]", func() {
+		logger := MakeLog4goLogger(log4go.NewDefaultLogger(log4go.CRITICAL))
+		server, err := StartRedisServer(logger)
+		if nil != err {
+			panic(err)
+		}
+		defer server.Close()
+
+		dsl := RedisDsl{server.Connection()}
+		dsl.HASH_SET_MAP("Hash A", map[string]string{"Bob": "1", "Gary": "2"})
+		dsl.HASH_SET_MAP("Hash B", map[string]string{"George": "3"})
+
+		values, err := dsl.HASHES_GETALL_MAPS([]string{"Hash A", "Hash B", "Miss"})
+		c.Expect(err, gospec.Equals, nil)
+		c.Expect(values, gospec.Equals, []map[string]string{{"Bob": "1", "Gary": "2"}, {"George": "3"}, {}})
+
+		ints, err := dsl.HASHES_GETALL_INT64_MAPS([]string{"Hash A", "Hash B"})
+		c.Expect(err, gospec.Equals, nil)
+		c.Expect(ints, gospec.Equals, []map[string]int64{{"Bob": 1, "Gary": 2}, {"George": 3}})
+
+		floats, err := dsl.HASHES_GETALL_FLOAT64_MAPS([]string{"Hash B"})
+		c.Expect(err, gospec.Equals, nil)
+		c.Expect(floats, gospec.Equals, []map[string]float64{{"George": 3}})
+	})
+
+	c.Specify("[RedisDsl][HASH_SET_MAP/HASH_FIELDS_EXPIRE/HASH_FIELDS_PTTL/HASH_FIELDS_PERSIST] Sends the commands", func() {
+		client := &recordingRedisClient{}
+		dsl := RedisDsl{client}
+
+		dsl.HASH_SET_MAP("Hash", map[string]string{"Gary": "2", "Bob": "1"})
+		dsl.HASH_FIELDS_EXPIRE("Hash", time.Hour, "Bob", "Gary")
+		dsl.HASH_FIELDS_EXPIRE("Hash", 1500*time.Millisecond, "Bob")
+		dsl.HASH_FIELDS_PTTL("Hash", "Bob")
+		dsl.HASH_FIELDS_PERSIST("Hash", "Bob")
+
+		c.Expect(client.cmds, gospec.Equals, []string{"HSET", "HEXPIRE", "HPEXPIRE", "HPTTL", "HPERSIST"})
+		c.Expect(client.args[0], gospec.Equals, []string{"Hash", "Bob", "1", "Gary", "2"})
+		c.Expect(client.args[1], gospec.Equals, []string{"Hash", "3600", "FIELDS", "2", "Bob", "Gary"})
+		c.Expect(client.args[2], gospec.Equals, []string{"Hash", "1500", "FIELDS", "1", "Bob"})
+		c.Expect(client.args[3], gospec.Equals, []string{"Hash", "FIELDS", "1", "Bob"})
+		c.Expect(client.args[4], gospec.Equals, []string{"Hash", "FIELDS", "1", "Bob"})
+	})
+
 }
 
 //
@@ -1339,8 +1639,8 @@ func RedisDslSpecs(c gospec.Context) {
 //
 
 func Benchmark_RedisDsl_KEY_EXISTS(b *testing.B) {
-	logger := log4go.NewDefaultLogger(log4go.CRITICAL)
-	server, err := StartRedisServer(&logger)
+	logger := MakeLog4goLogger(log4go.NewDefaultLogger(log4go.CRITICAL))
+	server, err := StartRedisServer(logger)
 	if nil != err {
 		panic(err)
 	}
@@ -1356,8 +1656,8 @@ func Benchmark_RedisDsl_KEY_EXISTS(b *testing.B) {
 }
 
 func Benchmark_RedisDsl_KEYS_EXIST(b *testing.B) {
-	logger := log4go.NewDefaultLogger(log4go.CRITICAL)
-	server, err := StartRedisServer(&logger)
+	logger := MakeLog4goLogger(log4go.NewDefaultLogger(log4go.CRITICAL))
+	server, err := StartRedisServer(logger)
 	if nil != err {
 		panic(err)
 	}
@@ -1373,8 +1673,8 @@ func Benchmark_RedisDsl_KEYS_EXIST(b *testing.B) {
 }
 
 func Benchmark_RedisDsl_HASH_FIELD_EXISTS(b *testing.B) {
-	logger := log4go.NewDefaultLogger(log4go.CRITICAL)
-	server, err := StartRedisServer(&logger)
+	logger := MakeLog4goLogger(log4go.NewDefaultLogger(log4go.CRITICAL))
+	server, err := StartRedisServer(logger)
 	if nil != err {
 		panic(err)
 	}
@@ -1390,8 +1690,8 @@ func Benchmark_RedisDsl_HASH_FIELD_EXISTS(b *testing.B) {
 }
 
 func Benchmark_RedisDsl_HASH_FIELDS_EXIST(b *testing.B) {
-	logger := log4go.NewDefaultLogger(log4go.CRITICAL)
-	server, err := StartRedisServer(&logger)
+	logger := MakeLog4goLogger(log4go.NewDefaultLogger(log4go.CRITICAL))
+	server, err := StartRedisServer(logger)
 	if nil != err {
 		panic(err)
 	}
@@ -1415,8 +1715,8 @@ func Benchmark_RedisDsl_HASH_FIELDS_EXIST(b *testing.B) {
 //
 
 func Benchmark_RedisDsl_INCR(b *testing.B) {
-	logger := log4go.NewDefaultLogger(log4go.CRITICAL)
-	server, err := StartRedisServer(&logger)
+	logger := MakeLog4goLogger(log4go.NewDefaultLogger(log4go.CRITICAL))
+	server, err := StartRedisServer(logger)
 	if nil != err {
 		panic(err)
 	}
@@ -1432,8 +1732,8 @@ func Benchmark_RedisDsl_INCR(b *testing.B) {
 }
 
 func Benchmark_RedisDsl_INCRBY(b *testing.B) {
-	logger := log4go.NewDefaultLogger(log4go.CRITICAL)
-	server, err := StartRedisServer(&logger)
+	logger := MakeLog4goLogger(log4go.NewDefaultLogger(log4go.CRITICAL))
+	server, err := StartRedisServer(logger)
 	if nil != err {
 		panic(err)
 	}
@@ -1449,8 +1749,8 @@ func Benchmark_RedisDsl_INCRBY(b *testing.B) {
 }
 
 func Benchmark_RedisDsl_INCRBYFLOAT(b *testing.B) {
-	logger := log4go.NewDefaultLogger(log4go.CRITICAL)
-	server, err := StartRedisServer(&logger)
+	logger := MakeLog4goLogger(log4go.NewDefaultLogger(log4go.CRITICAL))
+	server, err := StartRedisServer(logger)
 	if nil != err {
 		panic(err)
 	}
@@ -1466,8 +1766,8 @@ func Benchmark_RedisDsl_INCRBYFLOAT(b *testing.B) {
 }
 
 func Benchmark_RedisDsl_DECR(b *testing.B) {
-	logger := log4go.NewDefaultLogger(log4go.CRITICAL)
-	server, err := StartRedisServer(&logger)
+	logger := MakeLog4goLogger(log4go.NewDefaultLogger(log4go.CRITICAL))
+	server, err := StartRedisServer(logger)
 	if nil != err {
 		panic(err)
 	}
@@ -1483,8 +1783,8 @@ func Benchmark_RedisDsl_DECR(b *testing.B) {
 }
 
 func Benchmark_RedisDsl_DECRBY(b *testing.B) {
-	logger := log4go.NewDefaultLogger(log4go.CRITICAL)
-	server, err := StartRedisServer(&logger)
+	logger := MakeLog4goLogger(log4go.NewDefaultLogger(log4go.CRITICAL))
+	server, err := StartRedisServer(logger)
 	if nil != err {
 		panic(err)
 	}
@@ -1500,8 +1800,8 @@ func Benchmark_RedisDsl_DECRBY(b *testing.B) {
 }
 
 func Benchmark_RedisDsl_DECRBYFLOAT(b *testing.B) {
-	logger := log4go.NewDefaultLogger(log4go.CRITICAL)
-	server, err := StartRedisServer(&logger)
+	logger := MakeLog4goLogger(log4go.NewDefaultLogger(log4go.CRITICAL))
+	server, err := StartRedisServer(logger)
 	if nil != err {
 		panic(err)
 	}
@@ -1517,8 +1817,8 @@ func Benchmark_RedisDsl_DECRBYFLOAT(b *testing.B) {
 }
 
 func Benchmark_RedisDsl_HASH_INCR(b *testing.B) {
-	logger := log4go.NewDefaultLogger(log4go.CRITICAL)
-	server, err := StartRedisServer(&logger)
+	logger := MakeLog4goLogger(log4go.NewDefaultLogger(log4go.CRITICAL))
+	server, err := StartRedisServer(logger)
 	if nil != err {
 		panic(err)
 	}
@@ -1534,8 +1834,8 @@ func Benchmark_RedisDsl_HASH_INCR(b *testing.B) {
 }
 
 func Benchmark_RedisDsl_HASH_INCRBY(b *testing.B) {
-	logger := log4go.NewDefaultLogger(log4go.CRITICAL)
-	server, err := StartRedisServer(&logger)
+	logger := MakeLog4goLogger(log4go.NewDefaultLogger(log4go.CRITICAL))
+	server, err := StartRedisServer(logger)
 	if nil != err {
 		panic(err)
 	}
@@ -1551,8 +1851,8 @@ func Benchmark_RedisDsl_HASH_INCRBY(b *testing.B) {
 }
 
 func Benchmark_RedisDsl_HASH_INCRBYFLOAT(b *testing.B) {
-	logger := log4go.NewDefaultLogger(log4go.CRITICAL)
-	server, err := StartRedisServer(&logger)
+	logger := MakeLog4goLogger(log4go.NewDefaultLogger(log4go.CRITICAL))
+	server, err := StartRedisServer(logger)
 	if nil != err {
 		panic(err)
 	}
@@ -1568,8 +1868,8 @@ func Benchmark_RedisDsl_HASH_INCRBYFLOAT(b *testing.B) {
 }
 
 func Benchmark_RedisDsl_HASH_DECR(b *testing.B) {
-	logger := log4go.NewDefaultLogger(log4go.CRITICAL)
-	server, err := StartRedisServer(&logger)
+	logger := MakeLog4goLogger(log4go.NewDefaultLogger(log4go.CRITICAL))
+	server, err := StartRedisServer(logger)
 	if nil != err {
 		panic(err)
 	}
@@ -1585,8 +1885,8 @@ func Benchmark_RedisDsl_HASH_DECR(b *testing.B) {
 }
 
 func Benchmark_RedisDsl_HASH_DECRBY(b *testing.B) {
-	logger := log4go.NewDefaultLogger(log4go.CRITICAL)
-	server, err := StartRedisServer(&logger)
+	logger := MakeLog4goLogger(log4go.NewDefaultLogger(log4go.CRITICAL))
+	server, err := StartRedisServer(logger)
 	if nil != err {
 		panic(err)
 	}
@@ -1602,8 +1902,8 @@ func Benchmark_RedisDsl_HASH_DECRBY(b *testing.B) {
 }
 
 func Benchmark_RedisDsl_HASH_DECRBYFLOAT(b *testing.B) {
-	logger := log4go.NewDefaultLogger(log4go.CRITICAL)
-	server, err := StartRedisServer(&logger)
+	logger := MakeLog4goLogger(log4go.NewDefaultLogger(log4go.CRITICAL))
+	server, err := StartRedisServer(logger)
 	if nil != err {
 		panic(err)
 	}
@@ -1628,8 +1928,8 @@ func Benchmark_RedisDsl_HASH_DECRBYFLOAT(b *testing.B) {
 //
 
 func Benchmark_RedisDsl_GET(b *testing.B) {
-	logger := log4go.NewDefaultLogger(log4go.CRITICAL)
-	server, err := StartRedisServer(&logger)
+	logger := MakeLog4goLogger(log4go.NewDefaultLogger(log4go.CRITICAL))
+	server, err := StartRedisServer(logger)
 	if nil != err {
 		panic(err)
 	}
@@ -1645,8 +1945,8 @@ func Benchmark_RedisDsl_GET(b *testing.B) {
 }
 
 func Benchmark_RedisDsl_GET_x2(b *testing.B) {
-	logger := log4go.NewDefaultLogger(log4go.CRITICAL)
-	server, err := StartRedisServer(&logger)
+	logger := MakeLog4goLogger(log4go.NewDefaultLogger(log4go.CRITICAL))
+	server, err := StartRedisServer(logger)
 	if nil != err {
 		panic(err)
 	}
@@ -1663,8 +1963,8 @@ func Benchmark_RedisDsl_GET_x2(b *testing.B) {
 }
 
 func Benchmark_RedisDsl_GET_STRING(b *testing.B) {
-	logger := log4go.NewDefaultLogger(log4go.CRITICAL)
-	server, err := StartRedisServer(&logger)
+	logger := MakeLog4goLogger(log4go.NewDefaultLogger(log4go.CRITICAL))
+	server, err := StartRedisServer(logger)
 	if nil != err {
 		panic(err)
 	}
@@ -1680,8 +1980,8 @@ func Benchmark_RedisDsl_GET_STRING(b *testing.B) {
 }
 
 func Benchmark_RedisDsl_GET_INT64(b *testing.B) {
-	logger := log4go.NewDefaultLogger(log4go.CRITICAL)
-	server, err := StartRedisServer(&logger)
+	logger := MakeLog4goLogger(log4go.NewDefaultLogger(log4go.CRITICAL))
+	server, err := StartRedisServer(logger)
 	if nil != err {
 		panic(err)
 	}
@@ -1697,8 +1997,8 @@ func Benchmark_RedisDsl_GET_INT64(b *testing.B) {
 }
 
 func Benchmark_RedisDsl_GET_FLOAT64(b *testing.B) {
-	logger := log4go.NewDefaultLogger(log4go.CRITICAL)
-	server, err := StartRedisServer(&logger)
+	logger := MakeLog4goLogger(log4go.NewDefaultLogger(log4go.CRITICAL))
+	server, err := StartRedisServer(logger)
 	if nil != err {
 		panic(err)
 	}
@@ -1714,8 +2014,8 @@ func Benchmark_RedisDsl_GET_FLOAT64(b *testing.B) {
 }
 
 func Benchmark_RedisDsl_MGET(b *testing.B) {
-	logger := log4go.NewDefaultLogger(log4go.CRITICAL)
-	server, err := StartRedisServer(&logger)
+	logger := MakeLog4goLogger(log4go.NewDefaultLogger(log4go.CRITICAL))
+	server, err := StartRedisServer(logger)
 	if nil != err {
 		panic(err)
 	}
@@ -1732,8 +2032,8 @@ func Benchmark_RedisDsl_MGET(b *testing.B) {
 }
 
 func Benchmark_RedisDsl_MGET_STRINGS(b *testing.B) {
-	logger := log4go.NewDefaultLogger(log4go.CRITICAL)
-	server, err := StartRedisServer(&logger)
+	logger := MakeLog4goLogger(log4go.NewDefaultLogger(log4go.CRITICAL))
+	server, err := StartRedisServer(logger)
 	if nil != err {
 		panic(err)
 	}
@@ -1750,8 +2050,8 @@ func Benchmark_RedisDsl_MGET_STRINGS(b *testing.B) {
 }
 
 func Benchmark_RedisDsl_MGET_INT64S(b *testing.B) {
-	logger := log4go.NewDefaultLogger(log4go.CRITICAL)
-	server, err := StartRedisServer(&logger)
+	logger := MakeLog4goLogger(log4go.NewDefaultLogger(log4go.CRITICAL))
+	server, err := StartRedisServer(logger)
 	if nil != err {
 		panic(err)
 	}
@@ -1768,8 +2068,8 @@ func Benchmark_RedisDsl_MGET_INT64S(b *testing.B) {
 }
 
 func Benchmark_RedisDsl_MGET_FLOAT64S(b *testing.B) {
-	logger := log4go.NewDefaultLogger(log4go.CRITICAL)
-	server, err := StartRedisServer(&logger)
+	logger := MakeLog4goLogger(log4go.NewDefaultLogger(log4go.CRITICAL))
+	server, err := StartRedisServer(logger)
 	if nil != err {
 		panic(err)
 	}
@@ -1787,8 +2087,8 @@ func Benchmark_RedisDsl_MGET_FLOAT64S(b *testing.B) {
 }
 
 func Benchmark_RedisDsl_GETBIT(b *testing.B) {
-	logger := log4go.NewDefaultLogger(log4go.CRITICAL)
-	server, err := StartRedisServer(&logger)
+	logger := MakeLog4goLogger(log4go.NewDefaultLogger(log4go.CRITICAL))
+	server, err := StartRedisServer(logger)
 	if nil != err {
 		panic(err)
 	}
@@ -1804,8 +2104,8 @@ func Benchmark_RedisDsl_GETBIT(b *testing.B) {
 }
 
 func Benchmark_RedisDsl_GETBIT_x4(b *testing.B) {
-	logger := log4go.NewDefaultLogger(log4go.CRITICAL)
-	server, err := StartRedisServer(&logger)
+	logger := MakeLog4goLogger(log4go.NewDefaultLogger(log4go.CRITICAL))
+	server, err := StartRedisServer(logger)
 	if nil != err {
 		panic(err)
 	}
@@ -1827,8 +2127,8 @@ func Benchmark_RedisDsl_GETBIT_x4(b *testing.B) {
 }
 
 func Benchmark_RedisDsl_GETBITS(b *testing.B) {
-	logger := log4go.NewDefaultLogger(log4go.CRITICAL)
-	server, err := StartRedisServer(&logger)
+	logger := MakeLog4goLogger(log4go.NewDefaultLogger(log4go.CRITICAL))
+	server, err := StartRedisServer(logger)
 	if nil != err {
 		panic(err)
 	}
@@ -1847,8 +2147,8 @@ func Benchmark_RedisDsl_GETBITS(b *testing.B) {
 }
 
 func Benchmark_RedisDsl_GETBITS_TURNED_ON(b *testing.B) {
-	logger := log4go.NewDefaultLogger(log4go.CRITICAL)
-	server, err := StartRedisServer(&logger)
+	logger := MakeLog4goLogger(log4go.NewDefaultLogger(log4go.CRITICAL))
+	server, err := StartRedisServer(logger)
 	if nil != err {
 		panic(err)
 	}
@@ -1875,8 +2175,8 @@ func Benchmark_RedisDsl_GETBITS_TURNED_ON(b *testing.B) {
 //
 
 func Benchmark_RedisDsl_HASH_GET(b *testing.B) {
-	logger := log4go.NewDefaultLogger(log4go.CRITICAL)
-	server, err := StartRedisServer(&logger)
+	logger := MakeLog4goLogger(log4go.NewDefaultLogger(log4go.CRITICAL))
+	server, err := StartRedisServer(logger)
 	if nil != err {
 		panic(err)
 	}
@@ -1893,8 +2193,8 @@ func Benchmark_RedisDsl_HASH_GET(b *testing.B) {
 
 // Compare to Benchmark_RedisDsl_HASHES_GET
 func Benchmark_RedisDsl_HASH_GET_x4(b *testing.B) {
-	logger := log4go.NewDefaultLogger(log4go.CRITICAL)
-	server, err := StartRedisServer(&logger)
+	logger := MakeLog4goLogger(log4go.NewDefaultLogger(log4go.CRITICAL))
+	server, err := StartRedisServer(logger)
 	if nil != err {
 		panic(err)
 	}
@@ -1916,8 +2216,8 @@ func Benchmark_RedisDsl_HASH_GET_x4(b *testing.B) {
 }
 
 func Benchmark_RedisDsl_HASH_GET_STRING(b *testing.B) {
-	logger := log4go.NewDefaultLogger(log4go.CRITICAL)
-	server, err := StartRedisServer(&logger)
+	logger := MakeLog4goLogger(log4go.NewDefaultLogger(log4go.CRITICAL))
+	server, err := StartRedisServer(logger)
 	if nil != err {
 		panic(err)
 	}
@@ -1932,8 +2232,8 @@ func Benchmark_RedisDsl_HASH_GET_STRING(b *testing.B) {
 }
 
 func Benchmark_RedisDsl_HASH_GET_INT64(b *testing.B) {
-	logger := log4go.NewDefaultLogger(log4go.CRITICAL)
-	server, err := StartRedisServer(&logger)
+	logger := MakeLog4goLogger(log4go.NewDefaultLogger(log4go.CRITICAL))
+	server, err := StartRedisServer(logger)
 	if nil != err {
 		panic(err)
 	}
@@ -1948,8 +2248,8 @@ func Benchmark_RedisDsl_HASH_GET_INT64(b *testing.B) {
 }
 
 func Benchmark_RedisDsl_HASH_GET_FLOAT64(b *testing.B) {
-	logger := log4go.NewDefaultLogger(log4go.CRITICAL)
-	server, err := StartRedisServer(&logger)
+	logger := MakeLog4goLogger(log4go.NewDefaultLogger(log4go.CRITICAL))
+	server, err := StartRedisServer(logger)
 	if nil != err {
 		panic(err)
 	}
@@ -1964,8 +2264,8 @@ func Benchmark_RedisDsl_HASH_GET_FLOAT64(b *testing.B) {
 }
 
 func Benchmark_RedisDsl_HASH_MGET(b *testing.B) {
-	logger := log4go.NewDefaultLogger(log4go.CRITICAL)
-	server, err := StartRedisServer(&logger)
+	logger := MakeLog4goLogger(log4go.NewDefaultLogger(log4go.CRITICAL))
+	server, err := StartRedisServer(logger)
 	if nil != err {
 		panic(err)
 	}
@@ -1982,8 +2282,8 @@ func Benchmark_RedisDsl_HASH_MGET(b *testing.B) {
 
 // Compare to Benchmark_RedisDsl_HASHES_MGET
 func Benchmark_RedisDsl_HASH_MGET_x4(b *testing.B) {
-	logger := log4go.NewDefaultLogger(log4go.CRITICAL)
-	server, err := StartRedisServer(&logger)
+	logger := MakeLog4goLogger(log4go.NewDefaultLogger(log4go.CRITICAL))
+	server, err := StartRedisServer(logger)
 	if nil != err {
 		panic(err)
 	}
@@ -2005,8 +2305,8 @@ func Benchmark_RedisDsl_HASH_MGET_x4(b *testing.B) {
 }
 
 func Benchmark_RedisDsl_HASH_MGET_STRINGS(b *testing.B) {
-	logger := log4go.NewDefaultLogger(log4go.CRITICAL)
-	server, err := StartRedisServer(&logger)
+	logger := MakeLog4goLogger(log4go.NewDefaultLogger(log4go.CRITICAL))
+	server, err := StartRedisServer(logger)
 	if nil != err {
 		panic(err)
 	}
@@ -2023,8 +2323,8 @@ func Benchmark_RedisDsl_HASH_MGET_STRINGS(b *testing.B) {
 }
 
 func Benchmark_RedisDsl_HASH_MGET_INT64S(b *testing.B) {
-	logger := log4go.NewDefaultLogger(log4go.CRITICAL)
-	server, err := StartRedisServer(&logger)
+	logger := MakeLog4goLogger(log4go.NewDefaultLogger(log4go.CRITICAL))
+	server, err := StartRedisServer(logger)
 	if nil != err {
 		panic(err)
 	}
@@ -2041,8 +2341,8 @@ func Benchmark_RedisDsl_HASH_MGET_INT64S(b *testing.B) {
 }
 
 func Benchmark_RedisDsl_HASH_MGET_FLOAT64S(b *testing.B) {
-	logger := log4go.NewDefaultLogger(log4go.CRITICAL)
-	server, err := StartRedisServer(&logger)
+	logger := MakeLog4goLogger(log4go.NewDefaultLogger(log4go.CRITICAL))
+	server, err := StartRedisServer(logger)
 	if nil != err {
 		panic(err)
 	}
@@ -2068,8 +2368,8 @@ func Benchmark_RedisDsl_HASH_MGET_FLOAT64S(b *testing.B) {
 //
 
 func Benchmark_RedisDsl_HASHES_GET(b *testing.B) {
-	logger := log4go.NewDefaultLogger(log4go.CRITICAL)
-	server, err := StartRedisServer(&logger)
+	logger := MakeLog4goLogger(log4go.NewDefaultLogger(log4go.CRITICAL))
+	server, err := StartRedisServer(logger)
 	if nil != err {
 		panic(err)
 	}
@@ -2088,8 +2388,8 @@ func Benchmark_RedisDsl_HASHES_GET(b *testing.B) {
 }
 
 func Benchmark_RedisDsl_HASHES_GET_STRING(b *testing.B) {
-	logger := log4go.NewDefaultLogger(log4go.CRITICAL)
-	server, err := StartRedisServer(&logger)
+	logger := MakeLog4goLogger(log4go.NewDefaultLogger(log4go.CRITICAL))
+	server, err := StartRedisServer(logger)
 	if nil != err {
 		panic(err)
 	}
@@ -2107,8 +2407,8 @@ func Benchmark_RedisDsl_HASHES_GET_STRING(b *testing.B) {
 }
 
 func Benchmark_RedisDsl_HASHES_GET_INT64(b *testing.B) {
-	logger := log4go.NewDefaultLogger(log4go.CRITICAL)
-	server, err := StartRedisServer(&logger)
+	logger := MakeLog4goLogger(log4go.NewDefaultLogger(log4go.CRITICAL))
+	server, err := StartRedisServer(logger)
 	if nil != err {
 		panic(err)
 	}
@@ -2126,8 +2426,8 @@ func Benchmark_RedisDsl_HASHES_GET_INT64(b *testing.B) {
 }
 
 func Benchmark_RedisDsl_HASHES_GET_FLOAT64(b *testing.B) {
-	logger := log4go.NewDefaultLogger(log4go.CRITICAL)
-	server, err := StartRedisServer(&logger)
+	logger := MakeLog4goLogger(log4go.NewDefaultLogger(log4go.CRITICAL))
+	server, err := StartRedisServer(logger)
 	if nil != err {
 		panic(err)
 	}
@@ -2145,8 +2445,8 @@ func Benchmark_RedisDsl_HASHES_GET_FLOAT64(b *testing.B) {
 }
 
 func Benchmark_RedisDsl_HASHES_MGET(b *testing.B) {
-	logger := log4go.NewDefaultLogger(log4go.CRITICAL)
-	server, err := StartRedisServer(&logger)
+	logger := MakeLog4goLogger(log4go.NewDefaultLogger(log4go.CRITICAL))
+	server, err := StartRedisServer(logger)
 	if nil != err {
 		panic(err)
 	}
@@ -2164,8 +2464,8 @@ func Benchmark_RedisDsl_HASHES_MGET(b *testing.B) {
 }
 
 func Benchmark_RedisDsl_HASHES_MGET_STRINGS(b *testing.B) {
-	logger := log4go.NewDefaultLogger(log4go.CRITICAL)
-	server, err := StartRedisServer(&logger)
+	logger := MakeLog4goLogger(log4go.NewDefaultLogger(log4go.CRITICAL))
+	server, err := StartRedisServer(logger)
 	if nil != err {
 		panic(err)
 	}
@@ -2182,8 +2482,8 @@ func Benchmark_RedisDsl_HASHES_MGET_STRINGS(b *testing.B) {
 }
 
 func Benchmark_RedisDsl_HASHES_MGET_INT64S(b *testing.B) {
-	logger := log4go.NewDefaultLogger(log4go.CRITICAL)
-	server, err := StartRedisServer(&logger)
+	logger := MakeLog4goLogger(log4go.NewDefaultLogger(log4go.CRITICAL))
+	server, err := StartRedisServer(logger)
 	if nil != err {
 		panic(err)
 	}
@@ -2200,8 +2500,8 @@ func Benchmark_RedisDsl_HASHES_MGET_INT64S(b *testing.B) {
 }
 
 func Benchmark_RedisDsl_HASHES_MGET_FLOAT64S(b *testing.B) {
-	logger := log4go.NewDefaultLogger(log4go.CRITICAL)
-	server, err := StartRedisServer(&logger)
+	logger := MakeLog4goLogger(log4go.NewDefaultLogger(log4go.CRITICAL))
+	server, err := StartRedisServer(logger)
 	if nil != err {
 		panic(err)
 	}
@@ -0,0 +1,77 @@
+//
+// Classify Redis errors, for metrics/retries/etc
+//
+
+package dog_pool
+
+import "errors"
+import "io"
+import "net"
+import "github.com/RUNDSP/radix/redis"
+
+//
+// Classes of errors returned by Redis connections
+//
+const (
+	RedisErrorClassNone       = ""           // No error
+	RedisErrorClassConnection = "connection" // Unable to reach Redis, or the connection was dropped
+	RedisErrorClassTimeout    = "timeout"    // Redis did not reply in time
+	RedisErrorClassProtocol   = "protocol"   // Unable to parse the reply, or the pipeline is out of sync
+	RedisErrorClassServer     = "server"     // Redis is loading, or the client is not authorized
+	RedisErrorClassCommand    = "command"    // Redis rejected the command, i.e. WRONGTYPE
+)
+
+//
+// Return the class of the error, looking through wrapped errors (i.e. RedisBatchError & RedisBatchCommandError)
+//
+func RedisErrorClass(err error) string {
+	switch {
+	case nil == err:
+		return RedisErrorClassNone
+	case isAnyRedisError(err, ErrConnectionIsClosed, ErrNoConnectionsAvailable):
+		return RedisErrorClassConnection
+	case isAnyRedisError(err, io.EOF, io.ErrUnexpectedEOF):
+		return RedisErrorClassConnection
+	}
+
+	var net_err net.Error
+	if errors.As(err, &net_err) {
+		if net_err.Timeout() {
+			return RedisErrorClassTimeout
+		}
+		return RedisErrorClassConnection
+	}
+
+	switch {
+	case isAnyRedisError(err, redis.ParseError, redis.PipelineQueueEmptyError):
+		return RedisErrorClassProtocol
+	case isAnyRedisError(err, redis.LoadingError, redis.AuthError):
+		return RedisErrorClassServer
+	}
+
+	return RedisErrorClassCommand
+}
+
+//
+// Is the error (or any error it wraps) one of the targets, or does it have the same message?
+//
+func isAnyRedisError(err error, targets ...error) bool {
+	for _, target := range targets {
+		if errors.Is(err, target) || err.Error() == target.Error() {
+			return true
+		}
+	}
+	return false
+}
+
+//
+// Is the error caused by the connection (i.e. Redis is down), rather than the command?
+//
+func IsRedisConnectionError(err error) bool {
+	switch RedisErrorClass(err) {
+	case RedisErrorClassConnection, RedisErrorClassTimeout:
+		return true
+	default:
+		return false
+	}
+}
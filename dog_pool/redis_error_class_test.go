@@ -0,0 +1,59 @@
+package dog_pool
+
+import "errors"
+import "fmt"
+import "io"
+import "net"
+import "testing"
+import "github.com/orfjackal/gospec/src/gospec"
+import "github.com/RUNDSP/radix/redis"
+
+func TestRedisErrorClassSpecs(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping test in benchmark mode.")
+		return
+	}
+	r := gospec.NewRunner()
+	r.AddSpec(RedisErrorClassSpecs)
+	gospec.MainGoTest(r, t)
+}
+
+// Helpers
+func RedisErrorClassSpecs(c gospec.Context) {
+
+	c.Specify("[RedisErrorClass] Classifies connection errors", func() {
+		c.Expect(RedisErrorClass(nil), gospec.Equals, RedisErrorClassNone)
+		c.Expect(RedisErrorClass(ErrConnectionIsClosed), gospec.Equals, RedisErrorClassConnection)
+		c.Expect(RedisErrorClass(io.EOF), gospec.Equals, RedisErrorClassConnection)
+		c.Expect(RedisErrorClass(&net.OpError{Op: "dial", Err: errors.New("connection refused")}), gospec.Equals, RedisErrorClassConnection)
+		c.Expect(RedisErrorClass(&net.DNSError{IsTimeout: true}), gospec.Equals, RedisErrorClassTimeout)
+	})
+
+	c.Specify("[RedisErrorClass] Classifies protocol, server & command errors", func() {
+		c.Expect(RedisErrorClass(redis.ParseError), gospec.Equals, RedisErrorClassProtocol)
+		c.Expect(RedisErrorClass(redis.LoadingError), gospec.Equals, RedisErrorClassServer)
+		c.Expect(RedisErrorClass(errors.New("WRONGTYPE Operation against a key holding the wrong kind of value")), gospec.Equals, RedisErrorClassCommand)
+	})
+
+	c.Specify("[RedisErrorClass] Classifies wrapped errors", func() {
+		eof := &RedisBatchCommandError{Index: 0, Command: MakeRedisBatchCommandGet("Key"), Err: io.EOF}
+		c.Expect(RedisErrorClass(eof), gospec.Equals, RedisErrorClassConnection)
+		c.Expect(RedisErrorClass(&RedisBatchError{Size: 1, Errors: []*RedisBatchCommandError{eof}}), gospec.Equals, RedisErrorClassConnection)
+		c.Expect(RedisErrorClass(fmt.Errorf("batch: %w", io.ErrUnexpectedEOF)), gospec.Equals, RedisErrorClassConnection)
+
+		timeout := &RedisBatchCommandError{Index: 1, Command: MakeRedisBatchCommandGet("Key"), Err: &net.DNSError{IsTimeout: true}}
+		c.Expect(RedisErrorClass(timeout), gospec.Equals, RedisErrorClassTimeout)
+		c.Expect(RedisErrorClass(fmt.Errorf("batch: %w", redis.LoadingError)), gospec.Equals, RedisErrorClassServer)
+
+		wrongtype := &RedisBatchCommandError{Index: 2, Command: MakeRedisBatchCommandGet("Key"), Err: errors.New("WRONGTYPE Operation against a key holding the wrong kind of value")}
+		c.Expect(RedisErrorClass(wrongtype), gospec.Equals, RedisErrorClassCommand)
+	})
+
+	c.Specify("[IsRedisConnectionError] Only connection & timeout errors", func() {
+		c.Expect(IsRedisConnectionError(nil), gospec.Equals, false)
+		c.Expect(IsRedisConnectionError(io.EOF), gospec.Equals, true)
+		c.Expect(IsRedisConnectionError(&net.DNSError{IsTimeout: true}), gospec.Equals, true)
+		c.Expect(IsRedisConnectionError(redis.ParseError), gospec.Equals, false)
+		c.Expect(IsRedisConnectionError(errors.New("ERR unknown command")), gospec.Equals, false)
+	})
+}
@@ -0,0 +1,129 @@
+//
+// Middleware/Hook chain for RedisConnection calls
+//
+// Hooks are called for every command sent through RedisConnection.Append/Cmd:
+// - BeforeAppend is called in order, before the command is added to the pipeline
+// - AfterReply is called in reverse order, after the reply is read from Redis
+//
+// Use them for metrics, tracing, logging, redaction, fault injection, etc.
+//
+
+package dog_pool
+
+import "context"
+import "time"
+import "github.com/RUNDSP/radix/redis"
+
+//
+// A single command passing through the hook chain
+//
+type RedisHookCall struct {
+	Connection *RedisConnection "Connection the command is sent on"
+	Cmd        string           "Command we are executing, hooks may rewrite it"
+	Args       []interface{}    "Arguments of the command, hooks may rewrite them"
+	StartedAt  time.Time        "When the command was appended"
+	Ctx        context.Context  "Hooks may store per-call values here, i.e. spans or timers"
+	Err        error            "Set when a BeforeAppend hook aborted the command"
+
+	hooks_run int "Number of hooks BeforeAppend was called on"
+}
+
+//
+// Time since the command was appended
+//
+func (p *RedisHookCall) Elapsed() time.Duration {
+	return time.Since(p.StartedAt)
+}
+
+//
+// Hook called around every RedisConnection command
+//
+type RedisHook interface {
+	// BeforeAppend is called before the command is added to the pipeline.
+	// Returning an error aborts the command, GetReply() returns it as an ErrorReply.
+	BeforeAppend(call *RedisHookCall) error
+
+	// AfterReply is called after the reply is read from Redis (or the command was aborted).
+	// Return the reply to pass on to the next hook/caller, hooks may replace it.
+	AfterReply(call *RedisHookCall, reply *redis.Reply) *redis.Reply
+}
+
+//
+// Adapter for building a RedisHook from functions, nil functions are skipped
+//
+type RedisHookFuncs struct {
+	Before func(call *RedisHookCall) error
+	After  func(call *RedisHookCall, reply *redis.Reply) *redis.Reply
+}
+
+func (p RedisHookFuncs) BeforeAppend(call *RedisHookCall) error {
+	if nil == p.Before {
+		return nil
+	}
+	return p.Before(call)
+}
+
+func (p RedisHookFuncs) AfterReply(call *RedisHookCall, reply *redis.Reply) *redis.Reply {
+	if nil == p.After {
+		return reply
+	}
+	return p.After(call, reply)
+}
+
+//
+// Chain of hooks
+//
+type RedisHooks []RedisHook
+
+//
+// Call BeforeAppend on each hook in order, stopping at the first error
+//
+func (hooks RedisHooks) beforeAppend(call *RedisHookCall) error {
+	for _, hook := range hooks {
+		call.hooks_run++
+		if err := hook.BeforeAppend(call); nil != err {
+			call.Err = err
+			return err
+		}
+	}
+	return nil
+}
+
+//
+// Call AfterReply in reverse order, on every hook BeforeAppend was called on
+//
+func (hooks RedisHooks) afterReply(call *RedisHookCall, reply *redis.Reply) *redis.Reply {
+	for i := call.hooks_run - 1; i >= 0 && i < len(hooks); i-- {
+		reply = hooks[i].AfterReply(call, reply)
+	}
+	return reply
+}
+
+//
+// ==================================================
+//
+// Built-in hooks:
+//
+// ==================================================
+//
+
+type redisStopWatchKey struct{}
+
+//
+// Log the duration of every command with a StopWatch
+//
+func MakeRedisStopWatchHook(logger Logger, level LogLevel) RedisHook {
+	return RedisHookFuncs{
+		Before: func(call *RedisHookCall) error {
+			stop_watch := MakeStopWatchTags(call.Connection, logger, []string{call.Connection.Url, call.Connection.Id, call.Cmd}).Start()
+			call.Ctx = context.WithValue(call.Ctx, redisStopWatchKey{}, stop_watch)
+			return nil
+		},
+		After: func(call *RedisHookCall, reply *redis.Reply) *redis.Reply {
+			if stop_watch, ok := call.Ctx.Value(redisStopWatchKey{}).(*StopWatch); ok {
+				stop_watch.Stop().LogDurationAt(level)
+			}
+			return reply
+		},
+	}
+}
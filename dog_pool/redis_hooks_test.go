@@ -0,0 +1,145 @@
+package dog_pool
+
+import "errors"
+import "strings"
+import "testing"
+import "github.com/orfjackal/gospec/src/gospec"
+import "github.com/alecthomas/log4go"
+import "github.com/RUNDSP/radix/redis"
+
+func TestRedisHooksSpecs(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping test in benchmark mode.")
+		return
+	}
+	r := gospec.NewRunner()
+	r.AddSpec(RedisHooksSpecs)
+	gospec.MainGoTest(r, t)
+}
+
+// Records the order the hooks were called in
+func makeRecordingRedisHook(name string, calls *[]string) RedisHook {
+	return RedisHookFuncs{
+		Before: func(call *RedisHookCall) error {
+			*calls = append(*calls, "Before "+name+" "+call.Cmd)
+			return nil
+		},
+		After: func(call *RedisHookCall, reply *redis.Reply) *redis.Reply {
+			*calls = append(*calls, "After "+name+" "+call.Cmd)
+			return reply
+		},
+	}
+}
+
+// Helpers
+func RedisHooksSpecs(c gospec.Context) {
+
+	c.Specify("[RedisHookFuncs] Nil functions are skipped", func() {
+		hook := RedisHookFuncs{}
+		reply := &redis.Reply{Type: redis.NilReply}
+		c.Expect(hook.BeforeAppend(&RedisHookCall{}), gospec.Equals, nil)
+		c.Expect(hook.AfterReply(&RedisHookCall{}, reply), gospec.Equals, reply)
+	})
+
+	c.Specify("[RedisHooks] Calls the hooks in order, and the replies in reverse order", func() {
+		logger := MakeLog4goLogger(log4go.NewDefaultLogger(log4go.CRITICAL))
+		server, err := StartRedisServer(logger)
+		if nil != err {
+			panic(err)
+		}
+		defer server.Close()
+
+		calls := []string{}
+		connection := server.Connection()
+		connection.Hooks = RedisHooks{makeRecordingRedisHook("A", &calls), makeRecordingRedisHook("B", &calls)}
+
+		connection.Append("SET", "Key", "1")
+		connection.Append("GET", "Key")
+		connection.GetReply()
+		value, err := connection.GetReply().Str()
+		c.Expect(err, gospec.Equals, nil)
+		c.Expect(value, gospec.Equals, "1")
+
+		c.Expect(strings.Join(calls, ", "), gospec.Equals, "Before A SET, Before B SET, Before A GET, Before B GET, After B SET, After A SET, After B GET, After A GET")
+	})
+
+	c.Specify("[RedisHooks] Hooks can abort commands", func() {
+		logger := MakeLog4goLogger(log4go.NewDefaultLogger(log4go.CRITICAL))
+		server, err := StartRedisServer(logger)
+		if nil != err {
+			panic(err)
+		}
+		defer server.Close()
+
+		calls := []string{}
+		injected := errors.New("Injected fault")
+		connection := server.Connection()
+		connection.Hooks = RedisHooks{
+			makeRecordingRedisHook("A", &calls),
+			RedisHookFuncs{Before: func(call *RedisHookCall) error {
+				if call.Cmd == "DEL" {
+					return injected
+				}
+				return nil
+			}},
+			makeRecordingRedisHook("C", &calls),
+		}
+
+		connection.Cmd("SET", "Key", "1")
+		calls = []string{}
+
+		reply := connection.Cmd("DEL", "Key")
+		c.Expect(reply.Type, gospec.Equals, redis.ErrorReply)
+		c.Expect(reply.Err, gospec.Equals, injected)
+		c.Expect(strings.Join(calls, ", "), gospec.Equals, "Before A DEL, After A DEL")
+
+		// The command never reached Redis
+		value, err := connection.Cmd("GET", "Key").Str()
+		c.Expect(err, gospec.Equals, nil)
+		c.Expect(value, gospec.Equals, "1")
+	})
+
+	c.Specify("[RedisHooks] Hooks can rewrite commands and replies", func() {
+		logger := MakeLog4goLogger(log4go.NewDefaultLogger(log4go.CRITICAL))
+		server, err := StartRedisServer(logger)
+		if nil != err {
+			panic(err)
+		}
+		defer server.Close()
+
+		connection := server.Connection()
+		connection.Hooks = RedisHooks{
+			RedisHookFuncs{
+				Before: func(call *RedisHookCall) error {
+					call.Cmd = strings.ToUpper(call.Cmd)
+					return nil
+				},
+				After: func(call *RedisHookCall, reply *redis.Reply) *redis.Reply {
+					if call.Cmd == "GET" && redis.NilReply == reply.Type {
+						return &redis.Reply{Type: redis.ErrorReply, Err: errors.New("Cache Miss")}
+					}
+					return reply
+				},
+			},
+		}
+
+		reply := connection.Cmd("get", "Miss")
+		c.Expect(reply.Type, gospec.Equals, redis.ErrorReply)
+		c.Expect(reply.Err.Error(), gospec.Equals, "Cache Miss")
+	})
+
+	c.Specify("[RedisHooks] Pool attaches the hooks to every connection", func() {
+		logger := MakeLog4goLogger(log4go.NewDefaultLogger(log4go.CRITICAL))
+		hooks := RedisHooks{MakeRedisStopWatchHook(logger, LogFinest)}
+		pool := RedisConnectionPool{Mode: LAZY, Size: 2, Urls: []string{"127.0.0.1:6995"}, Logger: logger, Hooks: hooks}
+		defer pool.Close()
+
+		c.Expect(pool.Open(), gospec.Equals, nil)
+
+		connection, err := pool.Pop()
+		c.Expect(err, gospec.Equals, nil)
+		c.Expect(len(connection.Hooks), gospec.Equals, 1)
+		c.Expect(len(connection.Clone().Hooks), gospec.Equals, 1)
+		pool.Push(connection)
+	})
+}
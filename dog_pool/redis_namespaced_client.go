@@ -0,0 +1,616 @@
+//
+// Key Namespacing for RedisClientInterface
+//
+// Wraps a RedisClientInterface and transparently adds a prefix to every key argument,
+// so several applications can share one Redis without stepping on each other's keys.
+//
+// NOTE: radix replies are read-only, so keys returned by the server (KEYS, SCAN, BLPOP, XREAD, ...)
+// still carry the prefix. Read them with ReplyKeys(), which removes it;
+// RedisScanIterator does this automatically for SCAN.
+//
+
+package dog_pool
+
+import "fmt"
+import "reflect"
+import "strconv"
+import "strings"
+import "github.com/RUNDSP/radix/redis"
+
+//
+// Returns the positions of the key arguments in a (flattened) command
+//
+type RedisKeyPositions func(args []interface{}) []int
+
+//
+// Keys are the arguments from first to last (inclusive), every step arguments.
+// Negative positions count back from the end of the arguments, i.e. -1 is the last argument.
+//
+func RedisKeysRange(first, last, step int) RedisKeyPositions {
+	return func(args []interface{}) []int {
+		end := last
+		if end < 0 {
+			end = len(args) + end
+		}
+
+		output := []int{}
+		for i := first; i <= end && i < len(args); i += step {
+			output = append(output, i)
+		}
+		return output
+	}
+}
+
+//
+// Keys are counted by a "numkeys" argument, i.e. EVAL <SCRIPT> <NUMKEYS> <KEY> <KEY> ...
+// Any fixed key positions (i.e. the destination of ZUNIONSTORE) are included, if the command has them.
+//
+func RedisKeysNumkeys(numkeys_at int, fixed ...int) RedisKeyPositions {
+	return func(args []interface{}) []int {
+		output := []int{}
+		for _, i := range fixed {
+			if i < len(args) {
+				output = append(output, i)
+			}
+		}
+		if numkeys_at >= len(args) {
+			return output
+		}
+
+		numkeys, err := strconv.Atoi(toArgString(args[numkeys_at]))
+		if nil != err {
+			return output
+		}
+
+		for i := numkeys_at + 1; i <= numkeys_at+numkeys && i < len(args); i++ {
+			output = append(output, i)
+		}
+		return output
+	}
+}
+
+//
+// Keys follow the STREAMS keyword, and are followed by the same number of IDs (XREAD, XREADGROUP)
+//
+func RedisKeysStreams() RedisKeyPositions {
+	return func(args []interface{}) []int {
+		for i, arg := range args {
+			if strings.ToUpper(toArgString(arg)) != "STREAMS" {
+				continue
+			}
+
+			count := (len(args) - i - 1) / 2
+			output := make([]int, count)
+			for j := range output {
+				output[j] = i + 1 + j
+			}
+			return output
+		}
+		return []int{}
+	}
+}
+
+var redisKeysNone = RedisKeyPositions(func(args []interface{}) []int { return []int{} })
+var redisKeysFirst = RedisKeysRange(0, 0, 1)
+var redisKeysFirstTwo = RedisKeysRange(0, 1, 1)
+var redisKeysAll = RedisKeysRange(0, -1, 1)
+var redisKeysAllButLast = RedisKeysRange(0, -2, 1)
+var redisKeysPairs = RedisKeysRange(0, -1, 2)
+
+//
+// Command table: command --> key positions
+//
+// Commands that are not listed here are rejected by RedisNamespacedClient,
+// add them here (or to RedisNamespacedClient.Commands) to allow them.
+//
+// Commands that act on every namespace (FLUSHDB, FLUSHALL, DBSIZE, RANDOMKEY, ...) are deliberately omitted.
+//
+var RedisNamespaceCommands = map[string]RedisKeyPositions{
+	// Connection & Transactions:
+	"PING":    redisKeysNone,
+	"ECHO":    redisKeysNone,
+	"INFO":    redisKeysNone,
+	"TIME":    redisKeysNone,
+	"MULTI":   redisKeysNone,
+	"EXEC":    redisKeysNone,
+	"DISCARD": redisKeysNone,
+	"UNWATCH": redisKeysNone,
+	"WATCH":   redisKeysAll,
+
+	// Keys:
+	"DEL":       redisKeysAll,
+	"UNLINK":    redisKeysAll,
+	"EXISTS":    redisKeysAll,
+	"TOUCH":     redisKeysAll,
+	"KEYS":      redisKeysFirst,
+	"TYPE":      redisKeysFirst,
+	"EXPIRE":    redisKeysFirst,
+	"PEXPIRE":   redisKeysFirst,
+	"EXPIREAT":  redisKeysFirst,
+	"PEXPIREAT": redisKeysFirst,
+	"TTL":       redisKeysFirst,
+	"PTTL":      redisKeysFirst,
+	"PERSIST":   redisKeysFirst,
+	"DUMP":      redisKeysFirst,
+	"RESTORE":   redisKeysFirst,
+	"RENAME":    redisKeysFirstTwo,
+	"RENAMENX":  redisKeysFirstTwo,
+	"COPY":      redisKeysFirstTwo,
+
+	// Strings:
+	"GET":         redisKeysFirst,
+	"SET":         redisKeysFirst,
+	"SETNX":       redisKeysFirst,
+	"SETEX":       redisKeysFirst,
+	"PSETEX":      redisKeysFirst,
+	"GETSET":      redisKeysFirst,
+	"GETEX":       redisKeysFirst,
+	"GETDEL":      redisKeysFirst,
+	"APPEND":      redisKeysFirst,
+	"STRLEN":      redisKeysFirst,
+	"GETRANGE":    redisKeysFirst,
+	"SETRANGE":    redisKeysFirst,
+	"INCR":        redisKeysFirst,
+	"INCRBY":      redisKeysFirst,
+	"INCRBYFLOAT": redisKeysFirst,
+	"DECR":        redisKeysFirst,
+	"DECRBY":      redisKeysFirst,
+	"MGET":        redisKeysAll,
+	"MSET":        redisKeysPairs,
+	"MSETNX":      redisKeysPairs,
+
+	// Bitmaps:
+	"GETBIT":   redisKeysFirst,
+	"SETBIT":   redisKeysFirst,
+	"BITCOUNT": redisKeysFirst,
+	"BITPOS":   redisKeysFirst,
+	"BITOP":    RedisKeysRange(1, -1, 1),
+
+	// Hashes:
+	"HSET":         redisKeysFirst,
+	"HSETNX":       redisKeysFirst,
+	"HMSET":        redisKeysFirst,
+	"HGET":         redisKeysFirst,
+	"HMGET":        redisKeysFirst,
+	"HGETALL":      redisKeysFirst,
+	"HKEYS":        redisKeysFirst,
+	"HVALS":        redisKeysFirst,
+	"HLEN":         redisKeysFirst,
+	"HDEL":         redisKeysFirst,
+	"HEXISTS":      redisKeysFirst,
+	"HSTRLEN":      redisKeysFirst,
+	"HINCRBY":      redisKeysFirst,
+	"HINCRBYFLOAT": redisKeysFirst,
+	"HSCAN":        redisKeysFirst,
+	"HEXPIRE":      redisKeysFirst,
+	"HPEXPIRE":     redisKeysFirst,
+	"HTTL":         redisKeysFirst,
+	"HPTTL":        redisKeysFirst,
+	"HPERSIST":     redisKeysFirst,
+
+	// Lists:
+	"LPUSH":      redisKeysFirst,
+	"RPUSH":      redisKeysFirst,
+	"LPUSHX":     redisKeysFirst,
+	"RPUSHX":     redisKeysFirst,
+	"LPOP":       redisKeysFirst,
+	"RPOP":       redisKeysFirst,
+	"LLEN":       redisKeysFirst,
+	"LRANGE":     redisKeysFirst,
+	"LINDEX":     redisKeysFirst,
+	"LSET":       redisKeysFirst,
+	"LINSERT":    redisKeysFirst,
+	"LREM":       redisKeysFirst,
+	"LTRIM":      redisKeysFirst,
+	"LPOS":       redisKeysFirst,
+	"RPOPLPUSH":  redisKeysFirstTwo,
+	"LMOVE":      redisKeysFirstTwo,
+	"BRPOPLPUSH": redisKeysFirstTwo,
+	"BLMOVE":     redisKeysFirstTwo,
+	"BLPOP":      redisKeysAllButLast,
+	"BRPOP":      redisKeysAllButLast,
+	"LMPOP":      RedisKeysNumkeys(0),
+	"BLMPOP":     RedisKeysNumkeys(1),
+
+	// Sets:
+	"SADD":        redisKeysFirst,
+	"SREM":        redisKeysFirst,
+	"SCARD":       redisKeysFirst,
+	"SISMEMBER":   redisKeysFirst,
+	"SMISMEMBER":  redisKeysFirst,
+	"SMEMBERS":    redisKeysFirst,
+	"SPOP":        redisKeysFirst,
+	"SRANDMEMBER": redisKeysFirst,
+	"SSCAN":       redisKeysFirst,
+	"SMOVE":       redisKeysFirstTwo,
+	"SINTER":      redisKeysAll,
+	"SUNION":      redisKeysAll,
+	"SDIFF":       redisKeysAll,
+	"SINTERSTORE": redisKeysAll,
+	"SUNIONSTORE": redisKeysAll,
+	"SDIFFSTORE":  redisKeysAll,
+	"SINTERCARD":  RedisKeysNumkeys(0),
+
+	// Sorted Sets:
+	"ZADD":             redisKeysFirst,
+	"ZINCRBY":          redisKeysFirst,
+	"ZREM":             redisKeysFirst,
+	"ZCARD":            redisKeysFirst,
+	"ZCOUNT":           redisKeysFirst,
+	"ZLEXCOUNT":        redisKeysFirst,
+	"ZSCORE":           redisKeysFirst,
+	"ZMSCORE":          redisKeysFirst,
+	"ZRANK":            redisKeysFirst,
+	"ZREVRANK":         redisKeysFirst,
+	"ZRANGE":           redisKeysFirst,
+	"ZREVRANGE":        redisKeysFirst,
+	"ZRANGEBYSCORE":    redisKeysFirst,
+	"ZREVRANGEBYSCORE": redisKeysFirst,
+	"ZRANGEBYLEX":      redisKeysFirst,
+	"ZREVRANGEBYLEX":   redisKeysFirst,
+	"ZREMRANGEBYSCORE": redisKeysFirst,
+	"ZREMRANGEBYRANK":  redisKeysFirst,
+	"ZREMRANGEBYLEX":   redisKeysFirst,
+	"ZPOPMIN":          redisKeysFirst,
+	"ZPOPMAX":          redisKeysFirst,
+	"ZRANDMEMBER":      redisKeysFirst,
+	"ZSCAN":            redisKeysFirst,
+	"ZRANGESTORE":      redisKeysFirstTwo,
+	"BZPOPMIN":         redisKeysAllButLast,
+	"BZPOPMAX":         redisKeysAllButLast,
+	"ZUNIONSTORE":      RedisKeysNumkeys(1, 0),
+	"ZINTERSTORE":      RedisKeysNumkeys(1, 0),
+	"ZDIFFSTORE":       RedisKeysNumkeys(1, 0),
+	"ZUNION":           RedisKeysNumkeys(0),
+	"ZINTER":           RedisKeysNumkeys(0),
+	"ZDIFF":            RedisKeysNumkeys(0),
+	"ZINTERCARD":       RedisKeysNumkeys(0),
+	"ZMPOP":            RedisKeysNumkeys(0),
+	"BZMPOP":           RedisKeysNumkeys(1),
+
+	// HyperLogLog:
+	"PFADD":   redisKeysFirst,
+	"PFCOUNT": redisKeysAll,
+	"PFMERGE": redisKeysAll,
+
+	// Streams:
+	"XADD":       redisKeysFirst,
+	"XLEN":       redisKeysFirst,
+	"XRANGE":     redisKeysFirst,
+	"XREVRANGE":  redisKeysFirst,
+	"XDEL":       redisKeysFirst,
+	"XTRIM":      redisKeysFirst,
+	"XACK":       redisKeysFirst,
+	"XREAD":      RedisKeysStreams(),
+	"XREADGROUP": RedisKeysStreams(),
+
+	// Scripting:
+	"EVAL":    RedisKeysNumkeys(1),
+	"EVALSHA": RedisKeysNumkeys(1),
+}
+
+//
+// Namespacing wrapper for RedisClientInterface
+//
+type RedisNamespacedClient struct {
+	Prefix   string                       "Prefix added to every key, i.e. 'my_app:'"
+	Client   RedisClientInterface         "Wrapped client"
+	Commands map[string]RedisKeyPositions "(optional) Extra commands, or overrides of RedisNamespaceCommands"
+
+	pending []error "Errors for the queued commands, nil for commands sent to Redis"
+}
+
+//
+// Wrap the client in the namespace
+//
+func MakeRedisNamespacedClient(prefix string, client RedisClientInterface) *RedisNamespacedClient {
+	return &RedisNamespacedClient{Prefix: prefix, Client: client}
+}
+
+func (p *RedisNamespacedClient) String() string {
+	return fmt.Sprintf("RedisNamespacedClient { Prefix=%v, Client=%v }", p.Prefix, p.Client)
+}
+
+//
+//  ========================================
+//
+// RedisClientInterface implementation:
+//
+//  ========================================
+//
+
+//
+// Close closes the wrapped connection.
+//
+func (p *RedisNamespacedClient) Close() error {
+	p.pending = nil
+	return p.Client.Close()
+}
+
+//
+// Cmd calls the given Redis command:
+// - Calls Append(...)
+// - Returns GetReply()
+//
+func (p *RedisNamespacedClient) Cmd(cmd string, args ...interface{}) *redis.Reply {
+	p.Append(cmd, args...)
+	return p.GetReply()
+}
+
+//
+// Append adds the given call, with namespaced keys, to the pipeline queue.
+// Use GetReply() to read the reply.
+//
+// Unknown commands are not sent to Redis, GetReply() returns an ErrorReply for them.
+//
+func (p *RedisNamespacedClient) Append(cmd string, args ...interface{}) {
+	namespaced, err := p.namespaceArgs(cmd, flattenArgs(args))
+	if nil != err {
+		p.pending = append(p.pending, err)
+		return
+	}
+
+	p.pending = append(p.pending, nil)
+	p.Client.Append(cmd, namespaced...)
+}
+
+//
+// GetReply returns the reply for the next request in the pipeline queue.
+// Error reply with PipelineQueueEmptyError is returned,
+// if the pipeline queue is empty.
+//
+func (p *RedisNamespacedClient) GetReply() *redis.Reply {
+	if 0 == len(p.pending) {
+		return p.Client.GetReply()
+	}
+
+	err := p.pending[0]
+	p.pending = p.pending[1:]
+
+	if nil != err {
+		return &redis.Reply{Type: redis.ErrorReply, Err: err}
+	}
+	return p.Client.GetReply()
+}
+
+//
+// Tracer & URL of the wrapped client
+//
+func (p *RedisNamespacedClient) tracer() (Tracer, string) {
+	return redisTracerOf(p.Client)
+}
+
+//
+// Slow-log of the wrapped client, see RedisBatchCommands.ExecuteBatchContext
+//
+func (p *RedisNamespacedClient) slowLog() (*RedisSlowLog, string) {
+	return redisSlowLogOf(p.Client)
+}
+
+//
+//  ========================================
+//
+// Key helpers:
+//
+//  ========================================
+//
+
+//
+// Add the prefix to the key
+//
+func (p *RedisNamespacedClient) Key(key string) string {
+	return p.Prefix + key
+}
+
+//
+// Remove the prefix from a key returned by Redis
+//
+func (p *RedisNamespacedClient) StripKey(key string) string {
+	return strings.TrimPrefix(key, p.Prefix)
+}
+
+//
+// Remove the prefix from the keys returned by Redis
+//
+func (p *RedisNamespacedClient) StripKeys(keys []string) []string {
+	output := make([]string, len(keys))
+	for i, key := range keys {
+		output[i] = p.StripKey(key)
+	}
+	return output
+}
+
+//
+// Return the keys in the Redis Reply (KEYS, ...) without the prefix, see ReplyKeys for the other commands
+//
+// Redis/Casting Error --> error
+// Bulk Reply          --> [key]
+// Multi Reply         --> [key, key, ...]
+//
+func (p *RedisNamespacedClient) ReplyToKeys(reply *redis.Reply) ([]string, error) {
+	switch {
+	case nil != reply.Err:
+		return nil, reply.Err
+	case redis.NilReply == reply.Type:
+		return []string{}, nil
+	case redis.MultiReply == reply.Type:
+		keys := make([]string, len(reply.Elems))
+		for i, elem := range reply.Elems {
+			key, err := elem.Str()
+			if nil != err {
+				return nil, err
+			}
+			keys[i] = p.StripKey(key)
+		}
+		return keys, nil
+	default:
+		key, err := reply.Str()
+		if nil != err {
+			return nil, err
+		}
+		return []string{p.StripKey(key)}, nil
+	}
+}
+
+//
+// Return the keys in the reply of the command without the prefix, and none of its values:
+//
+// KEYS                                --> [key, key, ...]
+// SCAN                                --> The keys of the [cursor, [key, key, ...]] reply
+// BLPOP, BRPOP, BZPOPMIN, LMPOP, ...  --> [key] popped from, [] if the pop timed out
+// XREAD, XREADGROUP                   --> [key, key, ...] of every stream read
+// Redis Error or other commands       --> error
+//
+func (p *RedisNamespacedClient) ReplyKeys(cmd string, reply *redis.Reply) ([]string, error) {
+	switch {
+	case nil != reply.Err:
+		return nil, reply.Err
+	case redis.NilReply == reply.Type:
+		return []string{}, nil
+	}
+
+	switch strings.ToUpper(cmd) {
+	case "KEYS":
+		return p.ReplyToKeys(reply)
+	case cmd_scan:
+		if redis.MultiReply != reply.Type || 2 != len(reply.Elems) {
+			return nil, fmt.Errorf("[RedisNamespacedClient][ReplyKeys] Expected a [cursor, keys] reply to SCAN!")
+		}
+		return p.ReplyToKeys(reply.Elems[1])
+	case "BLPOP", "BRPOP", "BZPOPMIN", "BZPOPMAX", "LMPOP", "BLMPOP", "ZMPOP", "BZMPOP":
+		if redis.MultiReply != reply.Type || 0 == len(reply.Elems) {
+			return nil, fmt.Errorf("[RedisNamespacedClient][ReplyKeys] Expected a [key, value ...] reply to %s!", cmd)
+		}
+		return p.ReplyToKeys(reply.Elems[0])
+	case "XREAD", "XREADGROUP":
+		keys := make([]string, len(reply.Elems))
+		for i, stream := range reply.Elems {
+			if redis.MultiReply != stream.Type || 0 == len(stream.Elems) {
+				return nil, fmt.Errorf("[RedisNamespacedClient][ReplyKeys] Expected [key, entries] streams in the reply to %s!", cmd)
+			}
+			key, err := stream.Elems[0].Str()
+			if nil != err {
+				return nil, err
+			}
+			keys[i] = p.StripKey(key)
+		}
+		return keys, nil
+	default:
+		return nil, fmt.Errorf("[RedisNamespacedClient][ReplyKeys] %s doesn't reply with keys!", cmd)
+	}
+}
+
+//
+// Add the prefix to the key arguments of the command
+//
+func (p *RedisNamespacedClient) namespaceArgs(cmd string, args []interface{}) ([]interface{}, error) {
+	upper := strings.ToUpper(cmd)
+
+	// SCAN must only return keys in this namespace
+	if upper == cmd_scan {
+		return p.namespaceScanArgs(args), nil
+	}
+
+	positions, ok := p.Commands[upper]
+	if !ok {
+		positions, ok = RedisNamespaceCommands[upper]
+	}
+	if !ok {
+		return nil, fmt.Errorf("[RedisNamespacedClient] Unknown command '%s', unable to namespace its keys", cmd)
+	}
+
+	output := make([]interface{}, len(args))
+	copy(output, args)
+
+	for _, i := range positions(args) {
+		switch {
+		case upper == "KEYS":
+			output[i] = escapeGlob(p.Prefix) + toArgString(args[i])
+		default:
+			output[i] = p.prefixArg(args[i])
+		}
+	}
+
+	return output, nil
+}
+
+//
+// SCAN <CURSOR> [MATCH <PATTERN>] [COUNT <COUNT>] [TYPE <TYPE>]
+//
+func (p *RedisNamespacedClient) namespaceScanArgs(args []interface{}) []interface{} {
+	output := make([]interface{}, len(args))
+	copy(output, args)
+
+	for i := 1; i+1 < len(output); i++ {
+		if strings.ToUpper(toArgString(output[i])) == "MATCH" {
+			output[i+1] = escapeGlob(p.Prefix) + toArgString(output[i+1])
+			return output
+		}
+	}
+
+	return append(output, "MATCH", escapeGlob(p.Prefix)+"*")
+}
+
+func (p *RedisNamespacedClient) prefixArg(arg interface{}) interface{} {
+	switch value := arg.(type) {
+	case []byte:
+		output := make([]byte, 0, len(p.Prefix)+len(value))
+		output = append(output, p.Prefix...)
+		return append(output, value...)
+	default:
+		return p.Prefix + toArgString(arg)
+	}
+}
+
+//
+// Flatten slices & maps into a single list of arguments, the same way the redis client does
+//
+func flattenArgs(args []interface{}) []interface{} {
+	output := make([]interface{}, len(args))[0:0]
+
+	for _, arg := range args {
+		switch arg.(type) {
+		case nil, string, []byte:
+			output = append(output, arg)
+			continue
+		}
+
+		rv := reflect.ValueOf(arg)
+		switch rv.Kind() {
+		case reflect.Slice:
+			for i := 0; i < rv.Len(); i++ {
+				output = append(output, flattenArgs([]interface{}{rv.Index(i).Interface()})...)
+			}
+		case reflect.Map:
+			for _, key := range rv.MapKeys() {
+				output = append(output, key.Interface(), rv.MapIndex(key).Interface())
+			}
+		default:
+			output = append(output, arg)
+		}
+	}
+
+	return output
+}
+
+//
+// Format a single argument as a string
+//
+func toArgString(arg interface{}) string {
+	switch value := arg.(type) {
+	case string:
+		return value
+	case []byte:
+		return string(value)
+	default:
+		return strings.TrimSuffix(string(formatArg(arg)), " ")
+	}
+}
+
+//
+// Escape the glob-style characters used by KEYS & SCAN MATCH
+//
+func escapeGlob(value string) string {
+	replacer := strings.NewReplacer(`\`, `\\`, `*`, `\*`, `?`, `\?`, `[`, `\[`, `]`, `\]`)
+	return replacer.Replace(value)
+}
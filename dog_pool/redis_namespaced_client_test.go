@@ -0,0 +1,257 @@
+package dog_pool
+
+import "reflect"
+import "sort"
+import "unsafe"
+import "context"
+import "testing"
+import "github.com/orfjackal/gospec/src/gospec"
+import "github.com/alecthomas/log4go"
+import "github.com/RUNDSP/radix/redis"
+
+func TestRedisNamespacedClientSpecs(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping test in benchmark mode.")
+		return
+	}
+	r := gospec.NewRunner()
+	r.AddSpec(RedisNamespacedClientSpecs)
+	gospec.MainGoTest(r, t)
+}
+
+// Records the commands instead of sending them to Redis
+type recordingRedisClient struct {
+	cmds    []string
+	args    [][]string
+	replies []*redis.Reply "(optional) Replies to return in order, then NilReply"
+}
+
+func (p *recordingRedisClient) Close() error {
+	return nil
+}
+
+func (p *recordingRedisClient) Cmd(cmd string, args ...interface{}) *redis.Reply {
+	p.Append(cmd, args...)
+	return p.GetReply()
+}
+
+func (p *recordingRedisClient) Append(cmd string, args ...interface{}) {
+	flat := flattenArgs(args)
+	strs := make([]string, len(flat))
+	for i, arg := range flat {
+		strs[i] = toArgString(arg)
+	}
+	p.cmds = append(p.cmds, cmd)
+	p.args = append(p.args, strs)
+}
+
+func (p *recordingRedisClient) GetReply() *redis.Reply {
+	if len(p.replies) > 0 {
+		reply := p.replies[0]
+		p.replies = p.replies[1:]
+		return reply
+	}
+	return &redis.Reply{Type: redis.NilReply}
+}
+
+// BulkReply holding the value, radix keeps the value unexported so it is set through reflection
+func makeTestRedisBulkReply(value string) *redis.Reply {
+	output := &redis.Reply{Type: redis.BulkReply}
+	field := reflect.ValueOf(output).Elem().FieldByName("buf")
+	reflect.NewAt(field.Type(), unsafe.Pointer(field.UnsafeAddr())).Elem().SetBytes([]byte(value))
+	return output
+}
+
+func makeTestRedisMultiReply(values ...string) *redis.Reply {
+	output := &redis.Reply{Type: redis.MultiReply}
+	for _, value := range values {
+		output.Elems = append(output.Elems, makeTestRedisBulkReply(value))
+	}
+	return output
+}
+
+// Helpers
+func RedisNamespacedClientSpecs(c gospec.Context) {
+
+	c.Specify("[flattenArgs] Flattens slices and maps", func() {
+		args := flattenArgs([]interface{}{"A", []string{"B", "C"}, [][]byte{[]byte("D")}, int64(5), map[string]int{"E": 6}})
+		c.Expect(len(args), gospec.Equals, 7)
+		c.Expect(toArgString(args[0]), gospec.Equals, "A")
+		c.Expect(toArgString(args[1]), gospec.Equals, "B")
+		c.Expect(toArgString(args[2]), gospec.Equals, "C")
+		c.Expect(toArgString(args[3]), gospec.Equals, "D")
+		c.Expect(toArgString(args[4]), gospec.Equals, "5")
+		c.Expect(toArgString(args[5]), gospec.Equals, "E")
+		c.Expect(toArgString(args[6]), gospec.Equals, "6")
+	})
+
+	c.Specify("[RedisKeyPositions] Finds the keys", func() {
+		args := []interface{}{"A", "B", "C", "D", "5"}
+		c.Expect(RedisKeysRange(0, 0, 1)(args), gospec.ContainsExactly, []int{0})
+		c.Expect(RedisKeysRange(0, -2, 1)(args), gospec.ContainsExactly, []int{0, 1, 2, 3})
+		c.Expect(RedisKeysRange(0, -1, 2)(args), gospec.ContainsExactly, []int{0, 2, 4})
+		c.Expect(RedisKeysRange(1, -1, 1)(args), gospec.ContainsExactly, []int{1, 2, 3, 4})
+
+		// ZUNIONSTORE <DEST> <NUMKEYS> <KEY> <KEY> WEIGHTS ...
+		args = []interface{}{"Dest", "2", "A", "B", "WEIGHTS", "1", "2"}
+		c.Expect(RedisKeysNumkeys(1, 0)(args), gospec.ContainsExactly, []int{0, 2, 3})
+
+		// XREAD COUNT 2 STREAMS <KEY> <KEY> <ID> <ID>
+		args = []interface{}{"COUNT", "2", "STREAMS", "A", "B", "0", "0"}
+		c.Expect(RedisKeysStreams()(args), gospec.ContainsExactly, []int{3, 4})
+	})
+
+	c.Specify("[RedisNamespacedClient] Prefixes the keys", func() {
+		recorder := &recordingRedisClient{}
+		client := MakeRedisNamespacedClient("app:", recorder)
+
+		client.Cmd("GET", "A")
+		client.Cmd("mget", []string{"A", "B"})
+		client.Cmd("HSET", "Hash", "Field", []byte("Value"))
+		client.Cmd("MSET", "A", "1", "B", "2")
+		client.Cmd("BITOP", "AND", "Dest", "A", "B")
+		client.Cmd("PING")
+		MakeRedisBatchCommandHashMget("Hash", "A", "B").RedisCmd(client)
+
+		c.Expect(recorder.args[0], gospec.ContainsExactly, []string{"app:A"})
+		c.Expect(recorder.args[1], gospec.ContainsExactly, []string{"app:A", "app:B"})
+		c.Expect(recorder.args[2], gospec.ContainsExactly, []string{"app:Hash", "Field", "Value"})
+		c.Expect(recorder.args[3], gospec.ContainsExactly, []string{"app:A", "1", "app:B", "2"})
+		c.Expect(recorder.args[4], gospec.ContainsExactly, []string{"AND", "app:Dest", "app:A", "app:B"})
+		c.Expect(len(recorder.args[5]), gospec.Equals, 0)
+		c.Expect(recorder.args[6], gospec.ContainsExactly, []string{"app:Hash", "A", "B"})
+	})
+
+	c.Specify("[RedisNamespacedClient] Limits SCAN and KEYS to the namespace", func() {
+		recorder := &recordingRedisClient{}
+		client := MakeRedisNamespacedClient("app*:", recorder)
+
+		client.Cmd("SCAN", "0")
+		client.Cmd("SCAN", "0", "MATCH", "user:*", "COUNT", 10)
+		client.Cmd("KEYS", "*")
+
+		c.Expect(recorder.args[0], gospec.ContainsExactly, []string{"0", "MATCH", `app\*:*`})
+		c.Expect(recorder.args[1], gospec.ContainsExactly, []string{"0", "MATCH", `app\*:user:*`, "COUNT", "10"})
+		c.Expect(recorder.args[2], gospec.ContainsExactly, []string{`app\*:*`})
+	})
+
+	c.Specify("[RedisNamespacedClient][ReplyKeys] Removes the prefix from the keys of KEYS and SCAN replies", func() {
+		recorder := &recordingRedisClient{replies: []*redis.Reply{
+			makeTestRedisMultiReply("app:A", "app:app:B"),
+			{Type: redis.MultiReply, Elems: []*redis.Reply{makeTestRedisBulkReply("17"), makeTestRedisMultiReply("app:C")}},
+		}}
+		client := MakeRedisNamespacedClient("app:", recorder)
+
+		keys, err := client.ReplyKeys("KEYS", client.Cmd("KEYS", "*"))
+		c.Expect(err, gospec.Equals, nil)
+		c.Expect(keys, gospec.Equals, []string{"A", "app:B"})
+
+		keys, err = client.ReplyKeys("scan", client.Cmd("SCAN", "0"))
+		c.Expect(err, gospec.Equals, nil)
+		c.Expect(keys, gospec.Equals, []string{"C"})
+	})
+
+	c.Specify("[RedisNamespacedClient][ReplyKeys] Removes the prefix from the keys of blocking pops & streams, and nothing else", func() {
+		client := MakeRedisNamespacedClient("app:", &recordingRedisClient{})
+
+		keys, err := client.ReplyKeys("BLPOP", makeTestRedisMultiReply("app:List", "app:Value"))
+		c.Expect(err, gospec.Equals, nil)
+		c.Expect(keys, gospec.Equals, []string{"List"})
+
+		keys, err = client.ReplyKeys("BLPOP", &redis.Reply{Type: redis.NilReply})
+		c.Expect(err, gospec.Equals, nil)
+		c.Expect(keys, gospec.Equals, []string{})
+
+		streams := &redis.Reply{Type: redis.MultiReply, Elems: []*redis.Reply{
+			{Type: redis.MultiReply, Elems: []*redis.Reply{makeTestRedisBulkReply("app:S1"), makeTestRedisMultiReply()}},
+			{Type: redis.MultiReply, Elems: []*redis.Reply{makeTestRedisBulkReply("app:S2"), makeTestRedisMultiReply()}},
+		}}
+		keys, err = client.ReplyKeys("XREAD", streams)
+		c.Expect(err, gospec.Equals, nil)
+		c.Expect(keys, gospec.Equals, []string{"S1", "S2"})
+
+		_, err = client.ReplyKeys("GET", makeTestRedisBulkReply("app:Value"))
+		c.Expect(err.Error(), gospec.Equals, "[RedisNamespacedClient][ReplyKeys] GET doesn't reply with keys!")
+
+		_, err = client.ReplyKeys("SCAN", makeTestRedisMultiReply("0"))
+		c.Expect(err.Error(), gospec.Equals, "[RedisNamespacedClient][ReplyKeys] Expected a [cursor, keys] reply to SCAN!")
+
+		_, err = client.ReplyKeys("KEYS", &redis.Reply{Type: redis.ErrorReply, Err: ErrConnectionIsClosed})
+		c.Expect(err, gospec.Equals, ErrConnectionIsClosed)
+	})
+
+	c.Specify("[RedisKeysNumkeys] Skips the fixed positions the command doesn't have", func() {
+		positions := RedisNamespaceCommands["ZUNIONSTORE"]
+		c.Expect(positions([]interface{}{}), gospec.Equals, []int{})
+		c.Expect(positions([]interface{}{"Dest", "2", "A", "B"}), gospec.Equals, []int{0, 2, 3})
+
+		recorder := &recordingRedisClient{}
+		client := MakeRedisNamespacedClient("app:", recorder)
+		client.Cmd("ZUNIONSTORE")
+		c.Expect(recorder.cmds, gospec.Equals, []string{"ZUNIONSTORE"})
+	})
+
+	c.Specify("[RedisNamespacedClient] Rejects unknown commands", func() {
+		recorder := &recordingRedisClient{}
+		client := MakeRedisNamespacedClient("app:", recorder)
+
+		client.Append("FLUSHDB")
+		client.Append("GET", "A")
+
+		reply := client.GetReply()
+		c.Expect(reply.Type, gospec.Equals, redis.ErrorReply)
+		c.Expect(reply.Err.Error(), gospec.Equals, "[RedisNamespacedClient] Unknown command 'FLUSHDB', unable to namespace its keys")
+
+		reply = client.GetReply()
+		c.Expect(reply.Err, gospec.Equals, nil)
+		c.Expect(len(recorder.cmds), gospec.Equals, 1)
+
+		// Unless they are registered
+		client.Commands = map[string]RedisKeyPositions{"OBJECT": RedisKeysRange(1, 1, 1)}
+		client.Cmd("OBJECT", "ENCODING", "A")
+		c.Expect(recorder.args[1], gospec.ContainsExactly, []string{"ENCODING", "app:A"})
+	})
+
+	c.Specify("[RedisNamespacedClient] Works with RedisDsl, RedisBatchCommands and SCAN", func() {
+		logger := MakeLog4goLogger(log4go.NewDefaultLogger(log4go.CRITICAL))
+		server, err := StartRedisServer(logger)
+		if nil != err {
+			panic(err)
+		}
+		defer server.Close()
+
+		client := MakeRedisNamespacedClient("app:", server.Connection())
+		dsl := RedisDsl{client}
+
+		err = RedisBatchCommands{
+			MakeRedisBatchCommandSet("A", []byte("1")),
+			MakeRedisBatchCommandHashSet("Hash", "Field", []byte("2")),
+		}.ExecuteBatch(client)
+		c.Expect(err, gospec.Equals, nil)
+
+		value, err := dsl.GET_STRING("A")
+		c.Expect(err, gospec.Equals, nil)
+		c.Expect(*value, gospec.Equals, "1")
+
+		value, err = dsl.HASH_GET_STRING("Hash", "Field")
+		c.Expect(err, gospec.Equals, nil)
+		c.Expect(*value, gospec.Equals, "2")
+
+		// The keys are stored with the prefix
+		value, err = RedisDsl{server.Connection()}.GET_STRING("app:A")
+		c.Expect(err, gospec.Equals, nil)
+		c.Expect(*value, gospec.Equals, "1")
+
+		// Other namespaces are not visible
+		server.Connection().Cmd("SET", "other:A", "3")
+		keys, err := dsl.SCAN(context.Background(), RedisScanOptions{}).All()
+		c.Expect(err, gospec.Equals, nil)
+		sort.Strings(keys)
+		c.Expect(keys, gospec.ContainsExactly, []string{"A", "Hash"})
+
+		keys, err = client.ReplyToKeys(client.Cmd("KEYS", "*"))
+		c.Expect(err, gospec.Equals, nil)
+		sort.Strings(keys)
+		c.Expect(keys, gospec.ContainsExactly, []string{"A", "Hash"})
+	})
+}
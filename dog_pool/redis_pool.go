@@ -7,18 +7,21 @@ package dog_pool
 import "fmt"
 import "errors"
 import "time"
-import "github.com/alecthomas/log4go"
 
 //
 // Redis Connection Pool wrapper
 //
 type RedisConnectionPool struct {
-	Mode    ConnectionMode         "How should we prepare the connection pool?"
-	Size    int                    "(Max) Pool size"
-	Urls    []string               "Redis URLs to connect to"
-	Logger  log4go.Logger          "Logger we are using in the connection pool"
-	Timeout time.Duration          "Timeout to use for connecting to Redis"
-	myPool  *ConnectionPoolWrapper "Connection Pool wrapper"
+	Mode          ConnectionMode         "How should we prepare the connection pool?"
+	Size          int                    "(Max) Pool size"
+	Urls          []string               "Redis URLs to connect to"
+	Logger        Logger                 "(optional) Logger we are using in the connection pool, nil is the NopLogger"
+	Timeout       time.Duration          "Timeout to use for connecting to Redis"
+	Hooks         RedisHooks             "(optional) Hooks called around every command, on every connection"
+	Tracer        Tracer                 "(optional) Opens a span for every command, on every connection"
+	Redaction     *RedactionPolicy       "(optional) Hides & truncates the logged values, on every connection"
+	SlowLogBuffer *RedisSlowLog          "(optional) Records commands slower than its Threshold, shared by every connection"
+	myPool        *ConnectionPoolWrapper "Connection Pool wrapper"
 }
 
 func (p *RedisConnectionPool) String() string {
@@ -73,7 +76,7 @@ func (p *RedisConnectionPool) Open() error {
 		// DON'T Test the connection
 		initfn = func() (interface{}, error) {
 			values := nextUrl()
-			return makeLazyRedisConnection(values[0], values[1], p.Timeout, &p.Logger)
+			return makeLazyRedisConnection(values[0], values[1], p.Timeout, p.Logger, p.Hooks)
 		}
 	case AGRESSIVE:
 		// Create the factory
@@ -81,13 +84,27 @@ func (p *RedisConnectionPool) Open() error {
 		// AND Test the connection
 		initfn = func() (interface{}, error) {
 			values := nextUrl()
-			return makeAgressiveRedisConnection(values[0], values[1], p.Timeout, &p.Logger)
+			return makeAgressiveRedisConnection(values[0], values[1], p.Timeout, p.Logger, p.Hooks)
 		}
 		// No mode specified!
 	default:
 		return errors.New(fmt.Sprintf("Invalid connection mode: %v", p.Mode))
 	}
 
+	// Trace, redact & slow-log every connection
+	if nil != p.Tracer || nil != p.Redaction || nil != p.SlowLogBuffer {
+		makeConnection := initfn
+		initfn = func() (interface{}, error) {
+			connection, err := makeConnection()
+			if nil == err {
+				connection.(*RedisConnection).Tracer = p.Tracer
+				connection.(*RedisConnection).Redaction = p.Redaction
+				connection.(*RedisConnection).SlowLogBuffer = p.SlowLogBuffer
+			}
+			return connection, err
+		}
+	}
+
 	// Create the new pool
 	pool, err := MakeConnectionPoolWrapper(p.Size, initfn)
 
@@ -138,12 +155,43 @@ func (p *RedisConnectionPool) Pop() (*RedisConnection, error) {
 
 	// Return the connection
 	if c != nil {
-		p.Logger.Finest("Removed connection %v", c)
+		logAt(p.Logger, LogFinest, "Removed connection %v", c)
 		return c.(*RedisConnection), nil
 	}
 
 	// Return an error when all connections are exhausted
-	p.Logger.Critical("[RedisConnectionPool][Pop] No connections available pool=%v", p.String())
+	logAt(p.Logger, LogCritical, "[RedisConnectionPool][Pop] No connections available pool=%v", p.String())
+	return nil, ErrNoConnectionsAvailable
+}
+
+//
+// Get a RedisConnection to the url from the pool, i.e. to a shard of the pool
+//
+// The connections to other urls are returned to the pool, they are briefly unavailable to other callers.
+//
+func (p *RedisConnectionPool) PopUrl(url string) (*RedisConnection, error) {
+	skipped := make([]interface{}, p.myPool.Len())[0:0]
+	defer func() {
+		for _, c := range skipped {
+			p.myPool.ReleaseConnection(c)
+		}
+	}()
+
+	for i, n := 0, p.myPool.Len(); i < n; i++ {
+		c := p.myPool.GetConnection()
+		if nil == c {
+			break
+		}
+
+		if connection := c.(*RedisConnection); url == connection.Url {
+			logAt(p.Logger, LogFinest, "Removed connection %v", c)
+			return connection, nil
+		}
+		skipped = append(skipped, c)
+	}
+
+	// Return an error when all connections to the url are exhausted
+	logAt(p.Logger, LogCritical, "[RedisConnectionPool][PopUrl] No connections available url=%v, pool=%v", url, p.String())
 	return nil, ErrNoConnectionsAvailable
 }
 
@@ -151,6 +199,6 @@ func (p *RedisConnectionPool) Pop() (*RedisConnection, error) {
 // Return a RedisConnection
 //
 func (p *RedisConnectionPool) Push(c *RedisConnection) {
-	p.Logger.Finest("Returned connection %v", c)
+	logAt(p.Logger, LogFinest, "Returned connection %v", c)
 	p.myPool.ReleaseConnection(c)
 }
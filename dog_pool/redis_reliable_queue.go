@@ -0,0 +1,192 @@
+//
+// Reliable Work Queue on top of Redis Lists
+//
+// Jobs are moved atomically from the pending list to a processing list (BRPOPLPUSH),
+// and stay there until they are acknowledged. Jobs that are never acknowledged
+// (i.e. the consumer crashed) are moved back to the pending list by Recover().
+//
+// Jobs are identified by their payload, so jobs must be unique: the start times are
+// keyed by the payload, and Ack() & Recover() remove a single copy from the processing list.
+// Add an id to the payload (i.e. a UUID) to queue the same work more than once.
+//
+
+package dog_pool
+
+import "fmt"
+import "time"
+import "github.com/RUNDSP/radix/redis"
+
+//
+// Reliable Queue wrapper
+//
+type RedisReliableQueue struct {
+	Name       string           "Name of the queue, used as the prefix of the Redis keys"
+	Connection *RedisConnection "Connection to Redis"
+}
+
+func (p *RedisReliableQueue) String() string {
+	return fmt.Sprintf("RedisReliableQueue { Name=%v, Connection=%v }", p.Name, p.Connection)
+}
+
+//
+// Redis List of jobs waiting to be processed
+//
+func (p *RedisReliableQueue) PendingKey() string {
+	return p.Name + ":pending"
+}
+
+//
+// Redis List of jobs currently being processed
+//
+func (p *RedisReliableQueue) ProcessingKey() string {
+	return p.Name + ":processing"
+}
+
+//
+// Redis Hash of job --> time (unix ms) the job started processing
+//
+func (p *RedisReliableQueue) StartedAtKey() string {
+	return p.Name + ":started_at"
+}
+
+//
+// Push the job(s) onto the pending list
+//
+func (p *RedisReliableQueue) Push(jobs ...[]byte) error {
+	if 0 == len(jobs) {
+		return nil
+	}
+
+	return p.Connection.Cmd("LPUSH", p.PendingKey(), jobs).Err
+}
+
+//
+// Pop the next job and move it to the processing list, blocks for up to block_for
+//
+// Returns:
+//   job, nil --> Got a job, call Ack(job) once it is done
+//   nil, nil --> Timed out waiting for a job
+//   nil, err --> Redis error
+//
+func (p *RedisReliableQueue) Pop(block_for time.Duration) ([]byte, error) {
+	reply := p.Connection.BRPOPLPUSH(p.PendingKey(), p.ProcessingKey(), block_for)
+	switch {
+	case nil != reply.Err:
+		return nil, reply.Err
+	case redis.NilReply == reply.Type:
+		return nil, nil
+	}
+
+	job, err := reply.Bytes()
+	if nil != err {
+		return nil, err
+	}
+
+	// Record when the job started, for Recover()
+	if err := p.Connection.Cmd("HSET", p.StartedAtKey(), job, nowUnixMillis()).Err; nil != err {
+		return nil, err
+	}
+
+	return job, nil
+}
+
+//
+// Acknowledge the job is done, and remove it from the processing list
+//
+func (p *RedisReliableQueue) Ack(job []byte) error {
+	p.Connection.Append("LREM", p.ProcessingKey(), -1, job)
+	p.Connection.Append("HDEL", p.StartedAtKey(), job)
+
+	var err error
+	for i := 0; i < 2; i++ {
+		if reply_err := p.Connection.GetReply().Err; nil != reply_err {
+			err = reply_err
+		}
+	}
+	return err
+}
+
+//
+// Number of jobs waiting to be processed
+//
+func (p *RedisReliableQueue) PendingLen() (int64, error) {
+	return p.Connection.Cmd("LLEN", p.PendingKey()).Int64()
+}
+
+//
+// Number of jobs currently being processed
+//
+func (p *RedisReliableQueue) ProcessingLen() (int64, error) {
+	return p.Connection.Cmd("LLEN", p.ProcessingKey()).Int64()
+}
+
+//
+// Move jobs that have been processing for longer than stalled_after back to the pending list
+//
+// Jobs without a start time (the consumer died between BRPOPLPUSH and HSET) are
+// given one now, and are recovered on a later call.
+//
+// Returns the number of jobs moved back to the pending list.
+//
+func (p *RedisReliableQueue) Recover(stalled_after time.Duration) (int, error) {
+	jobs, err := p.Connection.Cmd("LRANGE", p.ProcessingKey(), 0, -1).ListBytes()
+	if nil != err {
+		return 0, err
+	}
+	if 0 == len(jobs) {
+		return 0, nil
+	}
+
+	started_ats, err := ReplyToInt64Ptrs(p.Connection.Cmd("HMGET", p.StartedAtKey(), jobs))
+	if nil != err {
+		return 0, err
+	}
+
+	now := nowUnixMillis()
+	stalled_at := now - int64(stalled_after/time.Millisecond)
+
+	recovered := 0
+	for i, job := range jobs {
+		switch started_at := started_ats[i]; {
+		case nil == started_at:
+			if err := p.Connection.Cmd("HSETNX", p.StartedAtKey(), job, now).Err; nil != err {
+				return recovered, err
+			}
+			continue
+		case *started_at > stalled_at:
+			continue
+		}
+
+		// Only re-queue the job if it is still processing (i.e. not acknowledged in the meantime),
+		// in a script so the job can't be lost between removing & re-queueing it
+		moved, err := p.Connection.Cmd("EVAL", redis_reliable_queue_recover_script, 3, p.ProcessingKey(), p.PendingKey(), p.StartedAtKey(), job).Int64()
+		if nil != err {
+			return recovered, err
+		}
+		if 0 == moved {
+			continue
+		}
+
+		recovered++
+	}
+
+	return recovered, nil
+}
+
+//
+// Move the job from the processing list to the pending list, if it is still processing
+// KEYS = [processing, pending, started_at], ARGV = [job]
+// Returns the number of jobs moved, 0 or 1
+//
+var redis_reliable_queue_recover_script = `
+if 0 == redis.call("LREM", KEYS[1], -1, ARGV[1]) then
+	return 0
+end
+redis.call("RPUSH", KEYS[2], ARGV[1])
+redis.call("HDEL", KEYS[3], ARGV[1])
+return 1
+`
+
+func nowUnixMillis() int64 {
+	return time.Now().UnixNano() / int64(time.Millisecond)
+}
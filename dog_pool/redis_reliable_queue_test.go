@@ -0,0 +1,156 @@
+package dog_pool
+
+import "time"
+import "testing"
+import "github.com/orfjackal/gospec/src/gospec"
+import "github.com/alecthomas/log4go"
+
+func TestRedisReliableQueueSpecs(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping test in benchmark mode.")
+		return
+	}
+	r := gospec.NewRunner()
+	r.AddSpec(RedisReliableQueueSpecs)
+	gospec.MainGoTest(r, t)
+}
+
+// Helpers
+func RedisReliableQueueSpecs(c gospec.Context) {
+
+	c.Specify("[RedisReliableQueue] Keys", func() {
+		queue := &RedisReliableQueue{Name: "Jobs"}
+		c.Expect(queue.PendingKey(), gospec.Equals, "Jobs:pending")
+		c.Expect(queue.ProcessingKey(), gospec.Equals, "Jobs:processing")
+		c.Expect(queue.StartedAtKey(), gospec.Equals, "Jobs:started_at")
+	})
+
+	c.Specify("[RedisReliableQueue] Push, Pop, Ack", func() {
+		logger := MakeLog4goLogger(log4go.NewDefaultLogger(log4go.CRITICAL))
+		server, err := StartRedisServer(logger)
+		if nil != err {
+			panic(err)
+		}
+		defer server.Close()
+
+		queue := &RedisReliableQueue{Name: "Jobs", Connection: server.Connection()}
+		c.Expect(queue.Push([]byte("A"), []byte("B")), gospec.Equals, nil)
+
+		length, err := queue.PendingLen()
+		c.Expect(err, gospec.Equals, nil)
+		c.Expect(length, gospec.Equals, int64(2))
+
+		// First in, first out
+		job, err := queue.Pop(time.Second)
+		c.Expect(err, gospec.Equals, nil)
+		c.Expect(string(job), gospec.Equals, "A")
+
+		length, err = queue.ProcessingLen()
+		c.Expect(err, gospec.Equals, nil)
+		c.Expect(length, gospec.Equals, int64(1))
+
+		c.Expect(queue.Ack(job), gospec.Equals, nil)
+
+		length, err = queue.ProcessingLen()
+		c.Expect(err, gospec.Equals, nil)
+		c.Expect(length, gospec.Equals, int64(0))
+
+		job, err = queue.Pop(time.Second)
+		c.Expect(err, gospec.Equals, nil)
+		c.Expect(string(job), gospec.Equals, "B")
+
+		// Empty queue times out
+		job, err = queue.Pop(time.Second)
+		c.Expect(err, gospec.Equals, nil)
+		c.Expect(job, gospec.Satisfies, nil == job)
+	})
+
+	c.Specify("[RedisReliableQueue] Recover stalled jobs", func() {
+		logger := MakeLog4goLogger(log4go.NewDefaultLogger(log4go.CRITICAL))
+		server, err := StartRedisServer(logger)
+		if nil != err {
+			panic(err)
+		}
+		defer server.Close()
+
+		queue := &RedisReliableQueue{Name: "Jobs", Connection: server.Connection()}
+		queue.Push([]byte("A"))
+
+		job, err := queue.Pop(time.Second)
+		c.Expect(err, gospec.Equals, nil)
+		c.Expect(string(job), gospec.Equals, "A")
+
+		// Not stalled yet
+		count, err := queue.Recover(time.Minute)
+		c.Expect(err, gospec.Equals, nil)
+		c.Expect(count, gospec.Equals, 0)
+
+		time.Sleep(10 * time.Millisecond)
+
+		count, err = queue.Recover(time.Millisecond)
+		c.Expect(err, gospec.Equals, nil)
+		c.Expect(count, gospec.Equals, 1)
+
+		length, _ := queue.ProcessingLen()
+		c.Expect(length, gospec.Equals, int64(0))
+
+		job, err = queue.Pop(time.Second)
+		c.Expect(err, gospec.Equals, nil)
+		c.Expect(string(job), gospec.Equals, "A")
+	})
+
+	c.Specify("[RedisReliableQueue] Recover moves only the stalled jobs still processing", func() {
+		logger := MakeLog4goLogger(log4go.NewDefaultLogger(log4go.CRITICAL))
+		server, err := StartRedisServer(logger)
+		if nil != err {
+			panic(err)
+		}
+		defer server.Close()
+
+		queue := &RedisReliableQueue{Name: "Jobs", Connection: server.Connection()}
+		queue.Push([]byte("A"), []byte("B"))
+		queue.Pop(time.Second)
+		queue.Pop(time.Second)
+
+		// "A" is acknowledged, but its start time is left behind
+		server.Connection().Cmd("LREM", queue.ProcessingKey(), -1, "A")
+		time.Sleep(10 * time.Millisecond)
+
+		count, err := queue.Recover(time.Millisecond)
+		c.Expect(err, gospec.Equals, nil)
+		c.Expect(count, gospec.Equals, 1)
+
+		length, _ := queue.PendingLen()
+		c.Expect(length, gospec.Equals, int64(1))
+		length, _ = queue.ProcessingLen()
+		c.Expect(length, gospec.Equals, int64(0))
+		fields, _ := server.Connection().Cmd("HLEN", queue.StartedAtKey()).Int64()
+		c.Expect(fields, gospec.Equals, int64(1))
+
+		job, err := queue.Pop(time.Second)
+		c.Expect(err, gospec.Equals, nil)
+		c.Expect(string(job), gospec.Equals, "B")
+	})
+
+	c.Specify("[RedisReliableQueue] Recover jobs without a start time on the next call", func() {
+		logger := MakeLog4goLogger(log4go.NewDefaultLogger(log4go.CRITICAL))
+		server, err := StartRedisServer(logger)
+		if nil != err {
+			panic(err)
+		}
+		defer server.Close()
+
+		queue := &RedisReliableQueue{Name: "Jobs", Connection: server.Connection()}
+		server.Connection().Cmd("LPUSH", queue.ProcessingKey(), "A")
+
+		count, err := queue.Recover(time.Millisecond)
+		c.Expect(err, gospec.Equals, nil)
+		c.Expect(count, gospec.Equals, 0)
+
+		time.Sleep(10 * time.Millisecond)
+
+		count, err = queue.Recover(time.Millisecond)
+		c.Expect(err, gospec.Equals, nil)
+		c.Expect(count, gospec.Equals, 1)
+	})
+}
@@ -171,3 +171,181 @@ func ReplyToStringPtrs(reply *redis.Reply) ([]*string, error) {
 		return output, nil
 	}
 }
+
+//
+// Return the bools in the Redis Reply, i.e. SMISMEMBER
+//
+// Redis/Casting Error --> error
+// Nil Reply Element   --> false
+// All other cases     --> true or false
+//
+func ReplyToBools(reply *redis.Reply) ([]bool, error) {
+	switch {
+	case nil != reply.Err:
+		return nil, reply.Err
+	case redis.MultiReply != reply.Type:
+		return nil, fmt.Errorf("Reply type is not MultiReply, %#v", reply)
+	default:
+		output := make([]bool, len(reply.Elems))
+		for i, reply_elem := range reply.Elems {
+			value, err := ReplyToBool(reply_elem)
+			if nil != err {
+				return nil, err
+			}
+			output[i] = value
+		}
+		return output, nil
+	}
+}
+
+//
+// Return the strings in the Redis Reply, i.e. SMEMBERS or LRANGE
+//
+// Redis/Casting Error --> error
+// Nil Reply Element   --> error
+// All other cases     --> strings, in the order of the reply
+//
+func ReplyToStrings(reply *redis.Reply) ([]string, error) {
+	switch {
+	case nil != reply.Err:
+		return nil, reply.Err
+	case redis.MultiReply != reply.Type:
+		return nil, fmt.Errorf("Reply type is not MultiReply, %#v", reply)
+	default:
+		output := make([]string, len(reply.Elems))
+		for i, reply_elem := range reply.Elems {
+			value, err := reply_elem.Str()
+			if nil != err {
+				return nil, err
+			}
+			output[i] = value
+		}
+		return output, nil
+	}
+}
+
+//
+// Return the member/score pairs in the Redis Reply, i.e. ZRANGE ... WITHSCORES
+//
+// Redis/Casting Error --> error
+// Empty Sorted Set    --> empty slice
+// All other cases     --> members & scores, in the order of the reply
+//
+func ReplyToSortedSetMembers(reply *redis.Reply) ([]RedisSortedSetMember, error) {
+	switch {
+	case nil != reply.Err:
+		return nil, reply.Err
+	case redis.MultiReply != reply.Type:
+		return nil, fmt.Errorf("Reply type is not MultiReply, %#v", reply)
+	case 0 != len(reply.Elems)%2:
+		return nil, fmt.Errorf("Reply is not member/score pairs, %v elements", len(reply.Elems))
+	default:
+		output := make([]RedisSortedSetMember, len(reply.Elems)/2)
+		for i := range output {
+			member, err := reply.Elems[2*i].Str()
+			if nil != err {
+				return nil, err
+			}
+			score, err := reply.Elems[2*i+1].Float64()
+			if nil != err {
+				return nil, err
+			}
+			output[i] = RedisSortedSetMember{Member: member, Score: score}
+		}
+		return output, nil
+	}
+}
+
+//
+// Return the field/value pairs in the Redis Reply as strings, i.e. HGETALL
+//
+// Redis/Casting Error --> error
+// Missing Hash        --> empty map
+// All other cases     --> fields & values
+//
+func ReplyToStringMap(reply *redis.Reply) (map[string]string, error) {
+	if err := checkReplyFieldPairs(reply); nil != err {
+		return nil, err
+	}
+
+	output := make(map[string]string, len(reply.Elems)/2)
+	for i := 0; i < len(reply.Elems); i += 2 {
+		field, err := reply.Elems[i].Str()
+		if nil != err {
+			return nil, err
+		}
+		value, err := reply.Elems[i+1].Str()
+		if nil != err {
+			return nil, err
+		}
+		output[field] = value
+	}
+	return output, nil
+}
+
+//
+// Return the field/value pairs in the Redis Reply as int64s, i.e. HGETALL
+//
+// Redis/Casting Error --> error
+// Missing Hash        --> empty map
+// All other cases     --> fields & values
+//
+func ReplyToInt64Map(reply *redis.Reply) (map[string]int64, error) {
+	if err := checkReplyFieldPairs(reply); nil != err {
+		return nil, err
+	}
+
+	output := make(map[string]int64, len(reply.Elems)/2)
+	for i := 0; i < len(reply.Elems); i += 2 {
+		field, err := reply.Elems[i].Str()
+		if nil != err {
+			return nil, err
+		}
+		value, err := reply.Elems[i+1].Int64()
+		if nil != err {
+			return nil, err
+		}
+		output[field] = value
+	}
+	return output, nil
+}
+
+//
+// Return the field/value pairs in the Redis Reply as float64s, i.e. HGETALL
+//
+// Redis/Casting Error --> error
+// Missing Hash        --> empty map
+// All other cases     --> fields & values
+//
+func ReplyToFloat64Map(reply *redis.Reply) (map[string]float64, error) {
+	if err := checkReplyFieldPairs(reply); nil != err {
+		return nil, err
+	}
+
+	output := make(map[string]float64, len(reply.Elems)/2)
+	for i := 0; i < len(reply.Elems); i += 2 {
+		field, err := reply.Elems[i].Str()
+		if nil != err {
+			return nil, err
+		}
+		value, err := reply.Elems[i+1].Float64()
+		if nil != err {
+			return nil, err
+		}
+		output[field] = value
+	}
+	return output, nil
+}
+
+func checkReplyFieldPairs(reply *redis.Reply) error {
+	switch {
+	case nil != reply.Err:
+		return reply.Err
+	case redis.MultiReply != reply.Type:
+		return fmt.Errorf("Reply type is not MultiReply, %#v", reply)
+	case 0 != len(reply.Elems)%2:
+		return fmt.Errorf("Reply is not field/value pairs, %v elements", len(reply.Elems))
+	default:
+		return nil
+	}
+}
@@ -20,8 +20,8 @@ func TestReplyToSpecs(t *testing.T) {
 func ReplyToSpecs(c gospec.Context) {
 
 	c.Specify("[ReplyToBool] returns boolean or error", func() {
-		logger := log4go.NewDefaultLogger(log4go.CRITICAL)
-		server, err := StartRedisServer(&logger)
+		logger := MakeLog4goLogger(log4go.NewDefaultLogger(log4go.CRITICAL))
+		server, err := StartRedisServer(logger)
 		if nil != err {
 			panic(err)
 		}
@@ -60,8 +60,8 @@ func ReplyToSpecs(c gospec.Context) {
 	})
 
 	c.Specify("[ReplyToInt64Ptr] returns *int64 or error", func() {
-		logger := log4go.NewDefaultLogger(log4go.CRITICAL)
-		server, err := StartRedisServer(&logger)
+		logger := MakeLog4goLogger(log4go.NewDefaultLogger(log4go.CRITICAL))
+		server, err := StartRedisServer(logger)
 		if nil != err {
 			panic(err)
 		}
@@ -145,8 +145,8 @@ func ReplyToSpecs(c gospec.Context) {
 	})
 
 	c.Specify("[ReplyToInt64Ptrs] returns []*int64 or error", func() {
-		logger := log4go.NewDefaultLogger(log4go.CRITICAL)
-		server, err := StartRedisServer(&logger)
+		logger := MakeLog4goLogger(log4go.NewDefaultLogger(log4go.CRITICAL))
+		server, err := StartRedisServer(logger)
 		if nil != err {
 			panic(err)
 		}
@@ -202,8 +202,8 @@ func ReplyToSpecs(c gospec.Context) {
 	})
 
 	c.Specify("[ReplyToFloat64Ptr] returns *float64 or error", func() {
-		logger := log4go.NewDefaultLogger(log4go.CRITICAL)
-		server, err := StartRedisServer(&logger)
+		logger := MakeLog4goLogger(log4go.NewDefaultLogger(log4go.CRITICAL))
+		server, err := StartRedisServer(logger)
 		if nil != err {
 			panic(err)
 		}
@@ -266,8 +266,8 @@ func ReplyToSpecs(c gospec.Context) {
 	})
 
 	c.Specify("[ReplyToFloat64Ptrs] returns []*float64 or error", func() {
-		logger := log4go.NewDefaultLogger(log4go.CRITICAL)
-		server, err := StartRedisServer(&logger)
+		logger := MakeLog4goLogger(log4go.NewDefaultLogger(log4go.CRITICAL))
+		server, err := StartRedisServer(logger)
 		if nil != err {
 			panic(err)
 		}
@@ -323,8 +323,8 @@ func ReplyToSpecs(c gospec.Context) {
 	})
 
 	c.Specify("[ReplyToStringPtr] returns *string or error", func() {
-		logger := log4go.NewDefaultLogger(log4go.CRITICAL)
-		server, err := StartRedisServer(&logger)
+		logger := MakeLog4goLogger(log4go.NewDefaultLogger(log4go.CRITICAL))
+		server, err := StartRedisServer(logger)
 		if nil != err {
 			panic(err)
 		}
@@ -387,8 +387,8 @@ func ReplyToSpecs(c gospec.Context) {
 	})
 
 	c.Specify("[ReplyToStringPtrs] returns []*string or error", func() {
-		logger := log4go.NewDefaultLogger(log4go.CRITICAL)
-		server, err := StartRedisServer(&logger)
+		logger := MakeLog4goLogger(log4go.NewDefaultLogger(log4go.CRITICAL))
+		server, err := StartRedisServer(logger)
 		if nil != err {
 			panic(err)
 		}
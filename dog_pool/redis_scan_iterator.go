@@ -0,0 +1,256 @@
+//
+// SCAN/HSCAN/SSCAN/ZSCAN Cursor Iterators
+//
+// Use these instead of "KEYS *", which blocks the Redis server until every key is returned.
+//
+
+package dog_pool
+
+import "context"
+import "fmt"
+import "github.com/RUNDSP/radix/redis"
+
+var cmd_scan = "SCAN"
+var cmd_hscan = "HSCAN"
+var cmd_sscan = "SSCAN"
+var cmd_zscan = "ZSCAN"
+
+//
+// Filters for the SCAN family of commands
+//
+type RedisScanOptions struct {
+	Match string "(optional) MATCH <PATTERN>, only return elements matching the glob-style pattern"
+	Count int64  "(optional) COUNT <HINT>, amount of work the server should do per call"
+	Type  string "(optional) TYPE <TYPE>, only return keys of the given type (SCAN only)"
+}
+
+//
+// Implemented by clients that add a prefix to keys, i.e. RedisNamespacedClient
+//
+type redisKeyStripper interface {
+	StripKeys(keys []string) []string
+}
+
+//
+// Iterator over a SCAN-family cursor
+//
+// Usage:
+//   iterator := RedisDsl{connection}.SCAN(ctx, RedisScanOptions{Match: "user:*"})
+//   for iterator.Next() {
+//     keys := iterator.Batch()
+//   }
+//   if err := iterator.Err(); nil != err {
+//     ...
+//   }
+//
+type RedisScanIterator struct {
+	client  RedisClientInterface "Connection to Redis"
+	ctx     context.Context      "Cancels the iteration"
+	cmd     string               "SCAN, HSCAN, SSCAN or ZSCAN"
+	key     string               "Key to iterate, empty for SCAN"
+	options RedisScanOptions     "Filters for the iteration"
+
+	cursor string   "Cursor returned by the last call"
+	done   bool     "True once the server returned cursor 0, or iteration stopped"
+	batch  []string "Elements returned by the last call"
+	err    error    "First error encountered"
+}
+
+//
+// ==================================================
+//
+// Common Redis SCAN "X" Operations:
+//
+// ==================================================
+//
+
+// Iterate the keys in the database
+func (p RedisDsl) SCAN(ctx context.Context, options RedisScanOptions) *RedisScanIterator {
+	return makeRedisScanIterator(ctx, p.RedisClientInterface, cmd_scan, "", options)
+}
+
+// Iterate the fields & values of the hash key
+func (p RedisDsl) HSCAN(ctx context.Context, key string, options RedisScanOptions) *RedisScanIterator {
+	return makeRedisScanIterator(ctx, p.RedisClientInterface, cmd_hscan, key, options)
+}
+
+// Iterate the members of the set key
+func (p RedisDsl) SSCAN(ctx context.Context, key string, options RedisScanOptions) *RedisScanIterator {
+	return makeRedisScanIterator(ctx, p.RedisClientInterface, cmd_sscan, key, options)
+}
+
+// Iterate the members & scores of the sorted set key
+func (p RedisDsl) ZSCAN(ctx context.Context, key string, options RedisScanOptions) *RedisScanIterator {
+	return makeRedisScanIterator(ctx, p.RedisClientInterface, cmd_zscan, key, options)
+}
+
+func makeRedisScanIterator(ctx context.Context, client RedisClientInterface, cmd, key string, options RedisScanOptions) *RedisScanIterator {
+	if nil == ctx {
+		ctx = context.Background()
+	}
+
+	return &RedisScanIterator{
+		client:  client,
+		ctx:     ctx,
+		cmd:     cmd,
+		key:     key,
+		options: options,
+		cursor:  "0",
+	}
+}
+
+func (p *RedisScanIterator) String() string {
+	return fmt.Sprintf("RedisScanIterator { Cmd=%v, Key=%v, Match=%v, Count=%v, Type=%v, Cursor=%v, Done=%v }", p.cmd, p.key, p.options.Match, p.options.Count, p.options.Type, p.cursor, p.done)
+}
+
+//
+// Fetch the next non-empty batch from the server
+//
+// Returns:
+//   true  --> Batch() holds the next elements
+//   false --> The iteration is complete, cancelled or failed (see Err())
+//
+func (p *RedisScanIterator) Next() bool {
+	p.batch = nil
+
+	for !p.done {
+		if err := p.ctx.Err(); nil != err {
+			p.stop(err)
+			return false
+		}
+
+		batch, err := p.scan()
+		if nil != err {
+			p.stop(err)
+			return false
+		}
+
+		// The server may return empty batches mid-iteration, keep going
+		if len(batch) > 0 {
+			p.batch = batch
+			return true
+		}
+	}
+
+	return false
+}
+
+//
+// Elements returned by the last call to Next()
+//
+// SCAN/SSCAN --> [key, key, ...] or [member, member, ...]
+// HSCAN      --> [field, value, field, value, ...]
+// ZSCAN      --> [member, score, member, score, ...]
+//
+// NOTE: SCAN may return the same element more than once.
+//
+func (p *RedisScanIterator) Batch() []string {
+	return p.batch
+}
+
+//
+// First error encountered, including context cancellation
+//
+func (p *RedisScanIterator) Err() error {
+	return p.err
+}
+
+//
+// Is the iteration complete?
+//
+func (p *RedisScanIterator) Done() bool {
+	return p.done
+}
+
+//
+// Deliver the batches through a channel, the channel is closed once the iteration stops.
+// Check Err() after the channel is closed.
+//
+func (p *RedisScanIterator) Channel() <-chan []string {
+	output := make(chan []string)
+
+	go func() {
+		defer close(output)
+
+		for p.Next() {
+			select {
+			case output <- p.Batch():
+			case <-p.ctx.Done():
+				p.stop(p.ctx.Err())
+				return
+			}
+		}
+	}()
+
+	return output
+}
+
+//
+// Collect every remaining element into a single slice
+//
+func (p *RedisScanIterator) All() ([]string, error) {
+	output := []string{}
+	for p.Next() {
+		output = append(output, p.Batch()...)
+	}
+	return output, p.Err()
+}
+
+//
+// Stop iterating and record the error
+//
+func (p *RedisScanIterator) stop(err error) {
+	p.done = true
+	if nil == p.err {
+		p.err = err
+	}
+}
+
+//
+// Run a single SCAN-family call and advance the cursor
+//
+func (p *RedisScanIterator) scan() ([]string, error) {
+	args := make([]interface{}, 8)[0:0]
+	if p.cmd != cmd_scan {
+		args = append(args, p.key)
+	}
+	args = append(args, p.cursor)
+
+	if len(p.options.Match) > 0 {
+		args = append(args, "MATCH", p.options.Match)
+	}
+	if p.options.Count > 0 {
+		args = append(args, "COUNT", p.options.Count)
+	}
+	if len(p.options.Type) > 0 && p.cmd == cmd_scan {
+		args = append(args, "TYPE", p.options.Type)
+	}
+
+	reply := p.client.Cmd(p.cmd, args...)
+	switch {
+	case nil != reply.Err:
+		return nil, reply.Err
+	case redis.MultiReply != reply.Type || 2 != len(reply.Elems):
+		return nil, fmt.Errorf("Reply type is not a [cursor, elements] MultiReply, %#v", reply)
+	}
+
+	cursor, err := reply.Elems[0].Str()
+	if nil != err {
+		return nil, err
+	}
+
+	batch, err := reply.Elems[1].List()
+	if nil != err {
+		return nil, err
+	}
+
+	// Remove any namespace prefix from the keys
+	if stripper, ok := p.client.(redisKeyStripper); ok && p.cmd == cmd_scan {
+		batch = stripper.StripKeys(batch)
+	}
+
+	p.cursor = cursor
+	p.done = "0" == cursor
+
+	return batch, nil
+}
@@ -0,0 +1,168 @@
+package dog_pool
+
+import "fmt"
+import "sort"
+import "context"
+import "testing"
+import "github.com/orfjackal/gospec/src/gospec"
+import "github.com/alecthomas/log4go"
+
+func TestRedisScanIteratorSpecs(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping test in benchmark mode.")
+		return
+	}
+	r := gospec.NewRunner()
+	r.AddSpec(RedisScanIteratorSpecs)
+	gospec.MainGoTest(r, t)
+}
+
+// Helpers
+func RedisScanIteratorSpecs(c gospec.Context) {
+
+	c.Specify("[RedisDsl][SCAN] Iterates every key", func() {
+		logger := MakeLog4goLogger(log4go.NewDefaultLogger(log4go.CRITICAL))
+		server, err := StartRedisServer(logger)
+		if nil != err {
+			panic(err)
+		}
+		defer server.Close()
+
+		for i := 0; i < 100; i++ {
+			server.Connection().Cmd("SET", fmt.Sprintf("Key:%d", i), i)
+		}
+
+		iterator := RedisDsl{server.Connection()}.SCAN(context.Background(), RedisScanOptions{Count: 10})
+		keys := map[string]bool{}
+		batches := 0
+		for iterator.Next() {
+			batches++
+			for _, key := range iterator.Batch() {
+				keys[key] = true
+			}
+		}
+		c.Expect(iterator.Err(), gospec.Equals, nil)
+		c.Expect(iterator.Done(), gospec.Equals, true)
+		c.Expect(len(keys), gospec.Equals, 100)
+		c.Expect(batches, gospec.Satisfies, batches > 1)
+	})
+
+	c.Specify("[RedisDsl][SCAN] Filters with MATCH and TYPE", func() {
+		logger := MakeLog4goLogger(log4go.NewDefaultLogger(log4go.CRITICAL))
+		server, err := StartRedisServer(logger)
+		if nil != err {
+			panic(err)
+		}
+		defer server.Close()
+
+		server.Connection().Cmd("SET", "A:1", "1")
+		server.Connection().Cmd("SET", "A:2", "2")
+		server.Connection().Cmd("SET", "B:1", "1")
+		server.Connection().Cmd("HSET", "A:Hash", "Field", "1")
+
+		keys, err := RedisDsl{server.Connection()}.SCAN(context.Background(), RedisScanOptions{Match: "A:*"}).All()
+		c.Expect(err, gospec.Equals, nil)
+		sort.Strings(keys)
+		c.Expect(len(keys), gospec.Equals, 3)
+		c.Expect(keys[0], gospec.Equals, "A:1")
+		c.Expect(keys[1], gospec.Equals, "A:2")
+		c.Expect(keys[2], gospec.Equals, "A:Hash")
+
+		keys, err = RedisDsl{server.Connection()}.SCAN(context.Background(), RedisScanOptions{Match: "A:*", Type: "hash"}).All()
+		c.Expect(err, gospec.Equals, nil)
+		c.Expect(len(keys), gospec.Equals, 1)
+		c.Expect(keys[0], gospec.Equals, "A:Hash")
+	})
+
+	c.Specify("[RedisDsl][HSCAN] Iterates fields and values", func() {
+		logger := MakeLog4goLogger(log4go.NewDefaultLogger(log4go.CRITICAL))
+		server, err := StartRedisServer(logger)
+		if nil != err {
+			panic(err)
+		}
+		defer server.Close()
+
+		server.Connection().Cmd("HSET", "Hash", "Field", "Value")
+
+		values, err := RedisDsl{server.Connection()}.HSCAN(context.Background(), "Hash", RedisScanOptions{}).All()
+		c.Expect(err, gospec.Equals, nil)
+		c.Expect(len(values), gospec.Equals, 2)
+		c.Expect(values[0], gospec.Equals, "Field")
+		c.Expect(values[1], gospec.Equals, "Value")
+	})
+
+	c.Specify("[RedisDsl][SSCAN] Iterates members", func() {
+		logger := MakeLog4goLogger(log4go.NewDefaultLogger(log4go.CRITICAL))
+		server, err := StartRedisServer(logger)
+		if nil != err {
+			panic(err)
+		}
+		defer server.Close()
+
+		server.Connection().Cmd("SADD", "Set", "A", "B", "C")
+
+		values, err := RedisDsl{server.Connection()}.SSCAN(context.Background(), "Set", RedisScanOptions{Match: "[AB]"}).All()
+		c.Expect(err, gospec.Equals, nil)
+		sort.Strings(values)
+		c.Expect(len(values), gospec.Equals, 2)
+		c.Expect(values[0], gospec.Equals, "A")
+		c.Expect(values[1], gospec.Equals, "B")
+	})
+
+	c.Specify("[RedisDsl][ZSCAN] Iterates members and scores", func() {
+		logger := MakeLog4goLogger(log4go.NewDefaultLogger(log4go.CRITICAL))
+		server, err := StartRedisServer(logger)
+		if nil != err {
+			panic(err)
+		}
+		defer server.Close()
+
+		server.Connection().Cmd("ZADD", "ZSet", 5, "A")
+
+		values, err := RedisDsl{server.Connection()}.ZSCAN(context.Background(), "ZSet", RedisScanOptions{}).All()
+		c.Expect(err, gospec.Equals, nil)
+		c.Expect(len(values), gospec.Equals, 2)
+		c.Expect(values[0], gospec.Equals, "A")
+		c.Expect(values[1], gospec.Equals, "5")
+	})
+
+	c.Specify("[RedisScanIterator][Channel] Delivers batches", func() {
+		logger := MakeLog4goLogger(log4go.NewDefaultLogger(log4go.CRITICAL))
+		server, err := StartRedisServer(logger)
+		if nil != err {
+			panic(err)
+		}
+		defer server.Close()
+
+		for i := 0; i < 50; i++ {
+			server.Connection().Cmd("SET", fmt.Sprintf("Key:%d", i), i)
+		}
+
+		iterator := RedisDsl{server.Connection()}.SCAN(context.Background(), RedisScanOptions{Count: 5})
+		count := 0
+		for batch := range iterator.Channel() {
+			count += len(batch)
+		}
+		c.Expect(iterator.Err(), gospec.Equals, nil)
+		c.Expect(count, gospec.Satisfies, count >= 50)
+	})
+
+	c.Specify("[RedisScanIterator] Stops when the context is cancelled", func() {
+		logger := MakeLog4goLogger(log4go.NewDefaultLogger(log4go.CRITICAL))
+		server, err := StartRedisServer(logger)
+		if nil != err {
+			panic(err)
+		}
+		defer server.Close()
+
+		server.Connection().Cmd("SET", "Key", "1")
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		iterator := RedisDsl{server.Connection()}.SCAN(ctx, RedisScanOptions{})
+		c.Expect(iterator.Next(), gospec.Equals, false)
+		c.Expect(iterator.Err(), gospec.Equals, context.Canceled)
+		c.Expect(iterator.Done(), gospec.Equals, true)
+	})
+}
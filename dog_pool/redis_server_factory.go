@@ -2,22 +2,17 @@ package dog_pool
 
 import "fmt"
 import "os/exec"
-import "errors"
 import "time"
-import "github.com/alecthomas/log4go"
 
 type RedisServerProcess struct {
 	port       int
-	logger     *log4go.Logger
+	logger     Logger
 	connection *RedisConnection
 	cmd        *exec.Cmd
 }
 
-func StartRedisServer(logger *log4go.Logger) (*RedisServerProcess, error) {
+func StartRedisServer(logger Logger) (*RedisServerProcess, error) {
 	var err error
-	if nil == logger {
-		return nil, errors.New("Nil logger")
-	}
 
 	server := &RedisServerProcess{}
 	server.port, err = findPort()
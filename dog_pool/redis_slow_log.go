@@ -0,0 +1,323 @@
+//
+// Slow-command log for RedisConnection
+//
+// Records every Cmd, and every RedisBatchCommands pipeline, slower than the Threshold in a bounded ring buffer.
+//
+// Usage:
+//   connection.SlowLogBuffer = MakeRedisSlowLog(50*time.Millisecond, 128)
+//   stop := connection.SlowLogBuffer.DumpEvery(time.Minute, logger, LogWarning)
+//   defer stop()
+//
+//   for _, entry := range connection.SlowLog() {
+//     ...
+//   }
+//
+
+package dog_pool
+
+import "fmt"
+import "strings"
+import "sync"
+import "time"
+
+//
+// Default number of entries kept by a RedisSlowLog
+//
+var DefaultRedisSlowLogSize = 128
+
+//
+// Maximum length of the argument summary
+//
+var RedisSlowLogArgsLength = 256
+
+//
+// A single slow (or sampled) command
+//
+type RedisSlowLogEntry struct {
+	At       time.Time     "When the command finished"
+	Url      string        "Server the command was sent to"
+	Cmd      string        "Command name, or PIPELINE"
+	Args     string        "Summary of the arguments, after redaction"
+	Duration time.Duration "How long the command took"
+	Sampled  bool          "Recorded by SampleEvery, rather than the Threshold"
+}
+
+func (p RedisSlowLogEntry) String() string {
+	return fmt.Sprintf("RedisSlowLogEntry { At=%v, Url=%v, Duration=%v, Cmd=%v, Args=%v, Sampled=%v }", p.At.Format(time.RFC3339Nano), p.Url, p.Duration, p.Cmd, p.Args, p.Sampled)
+}
+
+//
+// Bounded ring buffer of slow commands, safe to share between connections
+//
+type RedisSlowLog struct {
+	Threshold   time.Duration "Record commands slower than this"
+	Size        int           "Number of entries kept, the oldest are overwritten"
+	SampleEvery uint64        "(optional) Also record every Nth command, regardless of its duration"
+
+	mutex    sync.Mutex
+	entries  []RedisSlowLogEntry "Ring buffer"
+	size     int                 "Capacity of the ring buffer, Size when it was last (re)allocated"
+	next     int                 "Index the next entry is written to"
+	total    uint64              "Number of entries ever recorded"
+	observed uint64              "Number of commands observed"
+}
+
+//
+// Make a slow-log, defaults to DefaultRedisSlowLogSize entries
+//
+func MakeRedisSlowLog(threshold time.Duration, size int) *RedisSlowLog {
+	if size <= 0 {
+		size = DefaultRedisSlowLogSize
+	}
+	return &RedisSlowLog{Threshold: threshold, Size: size}
+}
+
+func (p *RedisSlowLog) String() string {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	return fmt.Sprintf("RedisSlowLog { Threshold=%v, Size=%v, SampleEvery=%v, Len=%v, Total=%v }", p.Threshold, p.Size, p.SampleEvery, len(p.entries), p.total)
+}
+
+//
+// Observe the command timed by the StopWatch, recording it if it is slow or sampled.
+// The summary is only built for recorded commands.
+//
+// Returns:
+//   true  --> The command was recorded
+//   false --> The command was not recorded
+//
+func (p *RedisSlowLog) Observe(stop_watch *StopWatch, url string, summary func() (string, string)) bool {
+	p.mutex.Lock()
+	p.observed++
+	sampled := p.SampleEvery > 0 && 0 == p.observed%p.SampleEvery
+	p.mutex.Unlock()
+
+	slow := stop_watch.Duration >= p.Threshold
+	if !slow && !sampled {
+		return false
+	}
+
+	cmd, args := summary()
+	p.Record(RedisSlowLogEntry{At: time.Now(), Url: url, Cmd: cmd, Args: args, Duration: stop_watch.Duration, Sampled: !slow})
+	return true
+}
+
+//
+// Add the entry, overwriting the oldest entry once the buffer is full
+//
+func (p *RedisSlowLog) Record(entry RedisSlowLogEntry) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	p.resize()
+	if len(p.entries) < p.size {
+		p.entries = append(p.entries, entry)
+	} else {
+		p.entries[p.next] = entry
+	}
+	p.next = (p.next + 1) % p.size
+	p.total++
+}
+
+//
+// Reallocate the ring buffer once Size changes, keeping the newest entries; the caller must hold the mutex
+//
+func (p *RedisSlowLog) resize() {
+	capacity := p.capacity()
+	if capacity == p.size {
+		return
+	}
+
+	entries := p.orderedEntries()
+	if len(entries) > capacity {
+		entries = entries[len(entries)-capacity:]
+	}
+	p.entries = entries
+	p.next = len(entries) % capacity
+	p.size = capacity
+}
+
+//
+// Entries in the buffer, oldest first
+//
+func (p *RedisSlowLog) Entries() []RedisSlowLogEntry {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	return p.orderedEntries()
+}
+
+//
+// Entries oldest first, the caller must hold the mutex
+//
+func (p *RedisSlowLog) orderedEntries() []RedisSlowLogEntry {
+	output := make([]RedisSlowLogEntry, len(p.entries))[0:0]
+	if len(p.entries) < p.size {
+		return append(output, p.entries...)
+	}
+
+	// Full buffer, the oldest entry is the next one to be overwritten
+	output = append(output, p.entries[p.next:]...)
+	return append(output, p.entries[:p.next]...)
+}
+
+//
+// Number of entries kept, defaults to DefaultRedisSlowLogSize
+//
+func (p *RedisSlowLog) capacity() int {
+	if p.Size <= 0 {
+		return DefaultRedisSlowLogSize
+	}
+	return p.Size
+}
+
+//
+// Number of entries in the buffer
+//
+func (p *RedisSlowLog) Len() int {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	return len(p.entries)
+}
+
+//
+// Number of entries ever recorded, including overwritten entries
+//
+func (p *RedisSlowLog) Total() uint64 {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	return p.total
+}
+
+//
+// Empty the buffer
+//
+func (p *RedisSlowLog) Reset() {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	p.entries = nil
+	p.next = 0
+}
+
+//
+// Log every entry at the level, then empty the buffer
+//
+func (p *RedisSlowLog) Dump(logger Logger, level LogLevel) []RedisSlowLogEntry {
+	p.mutex.Lock()
+	entries := p.orderedEntries()
+	p.entries = nil
+	p.next = 0
+	p.mutex.Unlock()
+
+	for _, entry := range entries {
+		logAt(logger, level, "[RedisSlowLog][Dump] %v", entry)
+	}
+	return entries
+}
+
+//
+// Dump the entries every interval, until stop is called
+//
+func (p *RedisSlowLog) DumpEvery(interval time.Duration, logger Logger, level LogLevel) (stop func()) {
+	ticker := time.NewTicker(interval)
+	done := make(chan struct{})
+
+	go func() {
+		for {
+			select {
+			case <-ticker.C:
+				p.Dump(logger, level)
+			case <-done:
+				return
+			}
+		}
+	}()
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			ticker.Stop()
+			close(done)
+		})
+	}
+}
+
+//
+// ==================================================
+//
+// RedisConnection & RedisBatchCommands integration:
+//
+// ==================================================
+//
+
+//
+// Implemented by clients that carry a RedisSlowLog, i.e. RedisConnection
+//
+type redisSlowLoggedClient interface {
+	slowLog() (*RedisSlowLog, string)
+}
+
+//
+// Slow-log & URL of the client, nil if it has none
+//
+func redisSlowLogOf(client RedisClientInterface) (*RedisSlowLog, string) {
+	if logged, ok := client.(redisSlowLoggedClient); ok {
+		return logged.slowLog()
+	}
+	return nil, ""
+}
+
+//
+// Entries recorded by the connection's SlowLogBuffer, oldest first
+//
+func (p *RedisConnection) SlowLog() []RedisSlowLogEntry {
+	if nil == p.SlowLogBuffer {
+		return nil
+	}
+	return p.SlowLogBuffer.Entries()
+}
+
+func (p *RedisConnection) slowLog() (*RedisSlowLog, string) {
+	return p.SlowLogBuffer, p.Url
+}
+
+//
+// Start a StopWatch if the connection has a slow-log
+//
+func (p *RedisConnection) startSlowLogWatch(cmd string) *StopWatch {
+	if nil == p.SlowLogBuffer {
+		return nil
+	}
+	return MakeStopWatchTags(p, p.Logger, []string{p.Url, p.Id, cmd}).Start()
+}
+
+//
+// Stop the StopWatch and record the command if it was slow
+//
+func (p *RedisConnection) observeSlowLog(stop_watch *StopWatch, cmd string, args []interface{}) {
+	if nil == stop_watch {
+		return
+	}
+
+	p.SlowLogBuffer.Observe(stop_watch.Stop().LogDurationAt(LogFinest), p.Url, func() (string, string) {
+		text, _ := redactionPolicyOr(p.Redaction).FormatCommand(cmd, args)
+		return strings.ToUpper(cmd), truncateSummary(text)
+	})
+}
+
+//
+// Summary of the commands in the pipeline, i.e. "3 commands: SET, GET, GET"
+//
+func (commands RedisBatchCommands) slowLogSummary() string {
+	names := make([]string, len(commands))
+	for i, command := range commands {
+		names[i] = command.cmd
+	}
+	return truncateSummary(fmt.Sprintf("%d commands: %s", len(commands), strings.Join(names, ", ")))
+}
+
+func truncateSummary(summary string) string {
+	if RedisSlowLogArgsLength > 0 && len(summary) > RedisSlowLogArgsLength {
+		return summary[:RedisSlowLogArgsLength] + "..."
+	}
+	return summary
+}
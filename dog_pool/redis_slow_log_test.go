@@ -0,0 +1,147 @@
+package dog_pool
+
+import "bytes"
+import "fmt"
+import "log"
+import "strings"
+import "testing"
+import "time"
+import "github.com/alecthomas/log4go"
+import "github.com/orfjackal/gospec/src/gospec"
+
+func TestRedisSlowLogSpecs(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping test in benchmark mode.")
+		return
+	}
+	r := gospec.NewRunner()
+	r.AddSpec(RedisSlowLogSpecs)
+	gospec.MainGoTest(r, t)
+}
+
+// Helpers
+func RedisSlowLogSpecs(c gospec.Context) {
+
+	c.Specify("[RedisSlowLog][Record] Overwrites the oldest entries once full", func() {
+		slow_log := MakeRedisSlowLog(0, 3)
+		for i := 0; i < 5; i++ {
+			slow_log.Record(RedisSlowLogEntry{Cmd: fmt.Sprintf("CMD%d", i)})
+		}
+
+		entries := slow_log.Entries()
+		c.Expect(len(entries), gospec.Equals, 3)
+		c.Expect(entries[0].Cmd, gospec.Equals, "CMD2")
+		c.Expect(entries[1].Cmd, gospec.Equals, "CMD3")
+		c.Expect(entries[2].Cmd, gospec.Equals, "CMD4")
+		c.Expect(slow_log.Len(), gospec.Equals, 3)
+		c.Expect(slow_log.Total(), gospec.Equals, uint64(5))
+	})
+
+	c.Specify("[RedisSlowLog][Record] Keeps the newest entries when the Size changes", func() {
+		cmds := func(entries []RedisSlowLogEntry) []string {
+			output := []string{}
+			for _, entry := range entries {
+				output = append(output, entry.Cmd)
+			}
+			return output
+		}
+
+		slow_log := MakeRedisSlowLog(0, 3)
+		for i := 0; i < 5; i++ {
+			slow_log.Record(RedisSlowLogEntry{Cmd: fmt.Sprintf("CMD%d", i)})
+		}
+
+		// Grown
+		slow_log.Size = 5
+		c.Expect(cmds(slow_log.Entries()), gospec.Equals, []string{"CMD2", "CMD3", "CMD4"})
+		for i := 5; i < 8; i++ {
+			slow_log.Record(RedisSlowLogEntry{Cmd: fmt.Sprintf("CMD%d", i)})
+		}
+		c.Expect(cmds(slow_log.Entries()), gospec.Equals, []string{"CMD3", "CMD4", "CMD5", "CMD6", "CMD7"})
+
+		// Shrunk
+		slow_log.Size = 2
+		slow_log.Record(RedisSlowLogEntry{Cmd: "CMD8"})
+		c.Expect(cmds(slow_log.Entries()), gospec.Equals, []string{"CMD7", "CMD8"})
+		slow_log.Record(RedisSlowLogEntry{Cmd: "CMD9"})
+		c.Expect(cmds(slow_log.Entries()), gospec.Equals, []string{"CMD8", "CMD9"})
+		c.Expect(slow_log.Total(), gospec.Equals, uint64(10))
+	})
+
+	c.Specify("[RedisSlowLog][Observe] Records commands slower than the Threshold", func() {
+		slow_log := MakeRedisSlowLog(time.Second, 10)
+		summary := func() (string, string) { return "GET", "GET A" }
+
+		c.Expect(slow_log.Observe(&StopWatch{Duration: time.Millisecond}, "127.0.0.1:6379", summary), gospec.Equals, false)
+		c.Expect(slow_log.Observe(&StopWatch{Duration: 2 * time.Second}, "127.0.0.1:6379", summary), gospec.Equals, true)
+
+		entries := slow_log.Entries()
+		c.Expect(len(entries), gospec.Equals, 1)
+		c.Expect(entries[0].Url, gospec.Equals, "127.0.0.1:6379")
+		c.Expect(entries[0].Cmd, gospec.Equals, "GET")
+		c.Expect(entries[0].Args, gospec.Equals, "GET A")
+		c.Expect(entries[0].Duration, gospec.Equals, 2*time.Second)
+		c.Expect(entries[0].Sampled, gospec.Equals, false)
+	})
+
+	c.Specify("[RedisSlowLog][Observe] Samples every Nth command", func() {
+		slow_log := MakeRedisSlowLog(time.Second, 10)
+		slow_log.SampleEvery = 3
+		summary := func() (string, string) { return "GET", "GET A" }
+
+		for i := 0; i < 7; i++ {
+			slow_log.Observe(&StopWatch{Duration: time.Millisecond}, "127.0.0.1:6379", summary)
+		}
+
+		entries := slow_log.Entries()
+		c.Expect(len(entries), gospec.Equals, 2)
+		c.Expect(entries[0].Sampled, gospec.Equals, true)
+		c.Expect(entries[1].Sampled, gospec.Equals, true)
+	})
+
+	c.Specify("[RedisSlowLog][Dump] Logs & empties the buffer", func() {
+		buffer := &bytes.Buffer{}
+		logger := MakeStdLogger(log.New(buffer, "", 0), LogWarning)
+
+		slow_log := MakeRedisSlowLog(0, 10)
+		slow_log.Record(RedisSlowLogEntry{Cmd: "GET", Args: "GET A"})
+		slow_log.Record(RedisSlowLogEntry{Cmd: "SET", Args: "SET A 1"})
+
+		entries := slow_log.Dump(logger, LogWarning)
+		c.Expect(len(entries), gospec.Equals, 2)
+		c.Expect(slow_log.Len(), gospec.Equals, 0)
+		c.Expect(slow_log.Total(), gospec.Equals, uint64(2))
+
+		lines := strings.Split(strings.TrimSpace(buffer.String()), "\n")
+		c.Expect(len(lines), gospec.Equals, 2)
+		c.Expect(strings.HasPrefix(lines[0], "[WARNING] [RedisSlowLog][Dump]"), gospec.Equals, true)
+		c.Expect(strings.Contains(lines[1], "Cmd=SET, Args=SET A 1"), gospec.Equals, true)
+	})
+
+	c.Specify("[RedisConnection][SlowLog] Records slow commands & pipelines", func() {
+		logger := MakeLog4goLogger(log4go.NewDefaultLogger(log4go.CRITICAL))
+		server, err := StartRedisServer(logger)
+		if nil != err {
+			panic(err)
+		}
+		defer server.Close()
+
+		connection := server.Connection()
+		c.Expect(len(connection.SlowLog()), gospec.Equals, 0)
+
+		connection.SlowLogBuffer = MakeRedisSlowLog(0, 10)
+		connection.Redaction = &RedactionPolicy{Commands: []string{"SET"}}
+
+		c.Expect(connection.Cmd("SET", "A", "secret").Err, gospec.Equals, nil)
+		cmds := RedisBatchCommands{MakeRedisBatchCommandGet("A"), MakeRedisBatchCommandGet("B")}
+		c.Expect(cmds.ExecuteBatch(MakeRedisNamespacedClient("ns:", connection)), gospec.Equals, nil)
+
+		entries := connection.SlowLog()
+		c.Expect(len(entries), gospec.Equals, 2)
+		c.Expect(entries[0].Cmd, gospec.Equals, "SET")
+		c.Expect(entries[0].Args, gospec.Equals, "SET A [REDACTED]")
+		c.Expect(entries[0].Url, gospec.Equals, connection.Url)
+		c.Expect(entries[1].Cmd, gospec.Equals, "PIPELINE")
+		c.Expect(entries[1].Args, gospec.Equals, "2 commands: GET, GET")
+	})
+}
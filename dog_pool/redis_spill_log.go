@@ -0,0 +1,488 @@
+//
+// Write-ahead spill log for the RedisBatchQueue
+//
+// Commands are appended as lines of JSON (see RedisDeadLetterFile) to numbered segment files in Dir.
+// Replay runs them in order and checkpoints its offset, so a restarted process resumes where it stopped.
+// Replay is at-least-once: a batch interrupted by a connection error is replayed again from its start.
+//
+// Layout:
+//   <Dir>/00000000000000000001.spill  --> Oldest segment, deleted once it is replayed
+//   <Dir>/00000000000000000002.spill  --> Active segment, commands are appended here
+//   <Dir>/checkpoint.json             --> { "segment": 1, "offset": 4096 }
+//
+// Usage:
+//   spill_log, err := OpenRedisSpillLog("/var/spool/app/redis", 64*1024*1024)
+//   queue := &RedisBatchQueue{
+//     ...
+//     Overflow: RedisBatchOverflowSpill,
+//     SpillLog: spill_log,
+//   }
+//
+
+package dog_pool
+
+import "bufio"
+import "encoding/json"
+import "fmt"
+import "io"
+import "io/ioutil"
+import "os"
+import "path/filepath"
+import "sort"
+import "strconv"
+import "strings"
+import "sync"
+import "time"
+
+const redis_spill_segment_ext = ".spill"
+const redis_spill_checkpoint = "checkpoint.json"
+const redis_spill_segment_size = 64 * 1024 * 1024
+
+type RedisSpillLog struct {
+	Logger      Logger "(optional) Logger for logging updates, errors, etc, nil is the NopLogger"
+	Dir         string "Directory holding the segments & the checkpoint"
+	SegmentSize int64  "Start a new segment once the active one is this big"
+
+	mutex   sync.Mutex
+	file    *os.File "Active segment, nil once closed"
+	segment uint64   "Number of the active segment"
+	size    int64    "Size of the active segment"
+
+	replay_mutex sync.Mutex "Serializes Replay"
+}
+
+//
+// Replay position, survives a restart
+//
+type redisSpillCheckpoint struct {
+	Segment uint64 `json:"segment"`
+	Offset  int64  `json:"offset"`
+}
+
+//
+// Open (or create) the spill log in the directory, resuming the active segment & checkpoint of a previous process.
+// A segment_size <= 0 defaults to 64MB.
+//
+func OpenRedisSpillLog(dir string, segment_size int64) (*RedisSpillLog, error) {
+	if segment_size <= 0 {
+		segment_size = redis_spill_segment_size
+	}
+
+	if err := os.MkdirAll(dir, 0755); nil != err {
+		return nil, err
+	}
+
+	p := &RedisSpillLog{Dir: dir, SegmentSize: segment_size}
+
+	segments, err := p.segments()
+	if nil != err {
+		return nil, err
+	}
+
+	checkpoint, err := p.readCheckpoint()
+	if nil != err {
+		return nil, err
+	}
+
+	// Remove the segments replayed before the last checkpoint
+	for _, segment := range segments {
+		if segment < checkpoint.Segment {
+			os.Remove(p.segmentPath(segment))
+		}
+	}
+
+	// Resume the newest segment
+	p.segment = checkpoint.Segment
+	if len(segments) > 0 && segments[len(segments)-1] > p.segment {
+		p.segment = segments[len(segments)-1]
+	}
+	if 0 == p.segment {
+		p.segment = 1
+	}
+
+	if err := p.openSegment(); nil != err {
+		return nil, err
+	}
+	return p, nil
+}
+
+func (p *RedisSpillLog) String() string {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	return fmt.Sprintf("RedisSpillLog { Dir=%v, SegmentSize=%v, Segment=%v, Size=%v }", p.Dir, p.SegmentSize, p.segment, p.size)
+}
+
+//
+// Append the command to the active segment
+//
+func (p *RedisSpillLog) Append(cmd *RedisBatchCommand) error {
+	return p.append(cmd, nil)
+}
+
+//
+// Append the command that failed to the active segment, see RedisDeadLetterSink
+//
+func (p *RedisSpillLog) DeadLetter(cmd *RedisBatchCommand, err error) error {
+	return p.append(cmd, err)
+}
+
+func (p *RedisSpillLog) append(cmd *RedisBatchCommand, err error) error {
+	line, json_err := marshalRedisDeadLetterRecord(cmd, err)
+	if nil != json_err {
+		return json_err
+	}
+
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	if nil == p.file {
+		return fmt.Errorf("[RedisSpillLog][Append] Spill log is closed!")
+	}
+
+	// Start a new segment, the replayed segments are deleted as a whole
+	if p.size > 0 && p.size+int64(len(line)) > p.SegmentSize {
+		if err := p.file.Close(); nil != err {
+			return err
+		}
+		p.file = nil
+		p.segment++
+		if err := p.openSegment(); nil != err {
+			return err
+		}
+	}
+
+	n, write_err := p.file.Write(line)
+	p.size += int64(n)
+	return write_err
+}
+
+//
+// Is every appended command replayed?
+//
+func (p *RedisSpillLog) IsEmpty() bool {
+	checkpoint, err := p.readCheckpoint()
+	if nil != err {
+		return false
+	}
+
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	return checkpoint.Segment >= p.segment && checkpoint.Offset >= p.size
+}
+
+//
+// Replay the commands in order, batch_size at a time, checkpointing after every batch.
+// Commands that fail with a Redis error are logged & skipped, a connection error stops the replay.
+//
+// Returns:
+//   n, nil --> n commands were replayed, the spill log is empty
+//   n, err --> n commands were replayed, the rest are replayed by the next call
+//
+func (p *RedisSpillLog) Replay(client RedisClientInterface, batch_size int) (int, error) {
+	return p.replay(func(cmds RedisBatchCommands) { cmds.ExecuteBatch(client) }, batch_size)
+}
+
+//
+// Replay the commands, running every batch with execute, see Replay
+//
+func (p *RedisSpillLog) replay(execute func(cmds RedisBatchCommands), batch_size int) (int, error) {
+	p.replay_mutex.Lock()
+	defer p.replay_mutex.Unlock()
+
+	if batch_size < 1 {
+		batch_size = 1
+	}
+
+	checkpoint, err := p.readCheckpoint()
+	if nil != err {
+		return 0, err
+	}
+
+	replayed := 0
+	for {
+		cmds, offset, err := p.readBatch(checkpoint, batch_size)
+		if nil != err {
+			return replayed, err
+		}
+
+		// End of the segment
+		if offset == checkpoint.Offset {
+			p.mutex.Lock()
+			active := p.segment
+			p.mutex.Unlock()
+
+			// Caught up with the writer
+			if checkpoint.Segment >= active {
+				return replayed, nil
+			}
+
+			// Move onto the next segment, then remove this one
+			replayed_segment := checkpoint.Segment
+			checkpoint = redisSpillCheckpoint{Segment: replayed_segment + 1}
+			if err := p.writeCheckpoint(checkpoint); nil != err {
+				return replayed, err
+			}
+			os.Remove(p.segmentPath(replayed_segment))
+			continue
+		}
+
+		if len(cmds) > 0 {
+			execute(cmds)
+			if failed := retryableFailures(cmds); len(failed) > 0 {
+				return replayed, failed[0].Reply().Err
+			}
+
+			for i, cmd := range cmds {
+				if err := cmd.Reply().Err; nil != err {
+					logAt(p.Logger, LogError, "[RedisSpillLog][Replay][%v] Error replaying Redis Command: err=%v, cmd=%v", i, err, cmd)
+				}
+			}
+		}
+
+		checkpoint.Offset = offset
+		if err := p.writeCheckpoint(checkpoint); nil != err {
+			return replayed, err
+		}
+		replayed += len(cmds)
+	}
+}
+
+//
+// Close the active segment, Replay still runs the commands on disk
+//
+func (p *RedisSpillLog) Close() error {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	if nil == p.file {
+		return nil
+	}
+
+	err := p.file.Close()
+	p.file = nil
+	return err
+}
+
+//
+// Read up to batch_size complete lines from the checkpoint, a partially written last line is left for later
+//
+// Returns:
+//   cmds, offset, nil --> offset is just past the last line read; malformed lines are logged & skipped
+//   nil,  0,      err --> The segment could not be read
+//
+func (p *RedisSpillLog) readBatch(checkpoint redisSpillCheckpoint, batch_size int) (RedisBatchCommands, int64, error) {
+	file, err := os.Open(p.segmentPath(checkpoint.Segment))
+	switch {
+	case os.IsNotExist(err):
+		// Nothing was ever appended to it
+		return nil, checkpoint.Offset, nil
+	case nil != err:
+		return nil, 0, err
+	}
+	defer file.Close()
+
+	if _, err := file.Seek(checkpoint.Offset, io.SeekStart); nil != err {
+		return nil, 0, err
+	}
+
+	cmds := make(RedisBatchCommands, batch_size)[0:0]
+	offset := checkpoint.Offset
+	reader := bufio.NewReader(file)
+	for len(cmds) < batch_size {
+		line, err := reader.ReadBytes('\n')
+		if nil != err {
+			// io.EOF, with or without a partial line
+			break
+		}
+		offset += int64(len(line))
+
+		cmd, err := unmarshalRedisDeadLetterRecord(line)
+		if nil != err {
+			logAt(p.Logger, LogError, "[RedisSpillLog][Replay][%v:%v] Skipping malformed command: err=%v", checkpoint.Segment, offset, err)
+			continue
+		}
+		cmds = append(cmds, cmd)
+	}
+	return cmds, offset, nil
+}
+
+//
+// Open the active segment for appending, the caller holds the mutex (or owns the spill log)
+//
+func (p *RedisSpillLog) openSegment() error {
+	file, err := os.OpenFile(p.segmentPath(p.segment), os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0644)
+	if nil != err {
+		return err
+	}
+
+	info, err := file.Stat()
+	if nil != err {
+		file.Close()
+		return err
+	}
+
+	p.file = file
+	p.size = info.Size()
+	return nil
+}
+
+//
+// Numbers of the segments on disk, oldest first
+//
+func (p *RedisSpillLog) segments() ([]uint64, error) {
+	infos, err := ioutil.ReadDir(p.Dir)
+	if nil != err {
+		return nil, err
+	}
+
+	output := make([]uint64, len(infos))[0:0]
+	for _, info := range infos {
+		name := info.Name()
+		if !strings.HasSuffix(name, redis_spill_segment_ext) {
+			continue
+		}
+		if segment, err := strconv.ParseUint(strings.TrimSuffix(name, redis_spill_segment_ext), 10, 64); nil == err {
+			output = append(output, segment)
+		}
+	}
+	sort.Slice(output, func(i, j int) bool { return output[i] < output[j] })
+	return output, nil
+}
+
+func (p *RedisSpillLog) segmentPath(segment uint64) string {
+	return filepath.Join(p.Dir, fmt.Sprintf("%020d%s", segment, redis_spill_segment_ext))
+}
+
+//
+// Read the checkpoint, a missing checkpoint starts at the oldest segment
+//
+func (p *RedisSpillLog) readCheckpoint() (redisSpillCheckpoint, error) {
+	var checkpoint redisSpillCheckpoint
+
+	data, err := ioutil.ReadFile(filepath.Join(p.Dir, redis_spill_checkpoint))
+	switch {
+	case os.IsNotExist(err):
+		segments, err := p.segments()
+		if nil == err && len(segments) > 0 {
+			checkpoint.Segment = segments[0]
+		}
+		return checkpoint, err
+	case nil != err:
+		return checkpoint, err
+	}
+
+	if err := json.Unmarshal(data, &checkpoint); nil != err {
+		return checkpoint, fmt.Errorf("[RedisSpillLog][Checkpoint] %v", err)
+	}
+	return checkpoint, nil
+}
+
+//
+// Write the checkpoint atomically, a crash leaves either the old or the new checkpoint
+//
+func (p *RedisSpillLog) writeCheckpoint(checkpoint redisSpillCheckpoint) error {
+	data, err := json.Marshal(checkpoint)
+	if nil != err {
+		return err
+	}
+
+	path := filepath.Join(p.Dir, redis_spill_checkpoint)
+	file, err := os.OpenFile(path+".tmp", os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0644)
+	if nil != err {
+		return err
+	}
+
+	_, err = file.Write(data)
+	if nil == err {
+		err = file.Sync()
+	}
+	if close_err := file.Close(); nil == err {
+		err = close_err
+	}
+	if nil != err {
+		return err
+	}
+
+	return os.Rename(path+".tmp", path)
+}
+
+//
+// ==================================================
+//
+// Replaying the SpillLog of a RedisBatchQueue:
+//
+// ==================================================
+//
+
+type redisSpillReplay struct {
+	stop chan struct{} "Closed by Close to stop replaying"
+	done chan struct{} "Closed once the replay has stopped"
+}
+
+//
+// Replay the SpillLog every SpillReplayInterval on a connection of its own, until Close stops it
+//
+func (p *RedisBatchQueue) startSpillReplay() *redisSpillReplay {
+	replay := &redisSpillReplay{stop: make(chan struct{}), done: make(chan struct{})}
+
+	// Replay on a connection of its own, or on a connection borrowed from the Pool every time
+	var connection *RedisConnection
+	var client RedisClientInterface
+	if nil == p.Pool {
+		connection = p.Connection.Clone()
+		client = connection
+		if len(p.Namespace) > 0 {
+			client = MakeRedisNamespacedClient(p.Namespace, connection)
+		}
+	}
+
+	interval := p.SpillReplayInterval
+	if interval <= 0 {
+		interval = time.Second
+	}
+
+	go func() {
+		defer close(replay.done)
+		if nil != connection {
+			defer connection.Close()
+		}
+
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-replay.stop:
+				return
+			case <-ticker.C:
+				if p.SpillLog.IsEmpty() {
+					continue
+				}
+
+				switch n, err := p.replaySpillLog(client); {
+				case nil != err:
+					logAt(p.Logger, LogWarning, "[RedisBatchQueue][SpillLog] Replayed %v commands, retrying in %v: err=%v", n, interval, err)
+				default:
+					logAt(p.Logger, LogInfo, "[RedisBatchQueue][SpillLog] Replayed %v commands", n)
+				}
+			}
+		}
+	}()
+
+	return replay
+}
+
+//
+// Replay the SpillLog on the client, or on connections borrowed from the Pool (routed to its shards) if the client is nil
+//
+func (p *RedisBatchQueue) replaySpillLog(client RedisClientInterface) (int, error) {
+	if nil != client {
+		return p.SpillLog.Replay(client, int(p.WorkersBatchSize))
+	}
+
+	worker := &redisBatchQueueWorker{Logger: p.Logger, Pool: p.Pool, Namespace: p.Namespace}
+	if shards := redisPoolShards(p.Pool); len(shards) > 1 {
+		worker.Shards = shards
+	}
+	return p.SpillLog.replay(func(cmds RedisBatchCommands) { worker.executeBatch(cmds) }, int(p.WorkersBatchSize))
+}
@@ -0,0 +1,153 @@
+package dog_pool
+
+import "io/ioutil"
+import "os"
+import "path/filepath"
+
+import "testing"
+import "github.com/orfjackal/gospec/src/gospec"
+
+func TestRedisSpillLogSpecs(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping test in benchmark mode.")
+		return
+	}
+	r := gospec.NewRunner()
+	r.AddSpec(RedisSpillLogSpecs)
+	gospec.MainGoTest(r, t)
+}
+
+func RedisSpillLogSpecs(c gospec.Context) {
+
+	c.Specify("[RedisSpillLog] Replays the commands in order, and checkpoints", func() {
+		dir, err := ioutil.TempDir("", "dog_pool")
+		c.Expect(err, gospec.Equals, nil)
+		defer os.RemoveAll(dir)
+
+		ptr, err := OpenRedisSpillLog(dir, 0)
+		c.Expect(err, gospec.Equals, nil)
+		defer ptr.Close()
+		c.Expect(ptr.IsEmpty(), gospec.Equals, true)
+
+		c.Expect(ptr.Append(MakeRedisBatchCommandIncrementBy("A", 1)), gospec.Equals, nil)
+		c.Expect(ptr.DeadLetter(MakeRedisBatchCommandIncrementBy("B", 2), ErrConnectionIsClosed), gospec.Equals, nil)
+		c.Expect(ptr.Append(MakeRedisBatchCommandIncrementBy("C", 3)), gospec.Equals, nil)
+		c.Expect(ptr.IsEmpty(), gospec.Equals, false)
+
+		client := &recordingRedisClient{}
+		n, err := ptr.Replay(client, 2)
+		c.Expect(err, gospec.Equals, nil)
+		c.Expect(n, gospec.Equals, 3)
+		c.Expect(client.cmds, gospec.Equals, []string{"INCRBY", "INCRBY", "INCRBY"})
+		c.Expect(client.args, gospec.Equals, [][]string{{"A", "1"}, {"B", "2"}, {"C", "3"}})
+		c.Expect(ptr.IsEmpty(), gospec.Equals, true)
+
+		// Nothing left to replay
+		n, err = ptr.Replay(client, 2)
+		c.Expect(err, gospec.Equals, nil)
+		c.Expect(n, gospec.Equals, 0)
+	})
+
+	c.Specify("[RedisSpillLog] Rolls the segments & resumes from the checkpoint after a restart", func() {
+		dir, err := ioutil.TempDir("", "dog_pool")
+		c.Expect(err, gospec.Equals, nil)
+		defer os.RemoveAll(dir)
+
+		// Every command gets a segment of its own
+		ptr, err := OpenRedisSpillLog(dir, 1)
+		c.Expect(err, gospec.Equals, nil)
+		for _, key := range []string{"A", "B", "C"} {
+			c.Expect(ptr.Append(MakeRedisBatchCommandGet(key)), gospec.Equals, nil)
+		}
+		segments, err := ptr.segments()
+		c.Expect(segments, gospec.Equals, []uint64{1, 2, 3})
+
+		// A connection error stops the replay, without moving the checkpoint
+		connection := &RedisConnection{Url: "127.0.0.1:6991"}
+		connection.Hooks = RedisHooks{RedisHookFuncs{Before: func(call *RedisHookCall) error {
+			return ErrConnectionIsClosed
+		}}}
+		n, err := ptr.Replay(connection, 1)
+		c.Expect(err, gospec.Equals, ErrConnectionIsClosed)
+		c.Expect(n, gospec.Equals, 0)
+
+		client := &recordingRedisClient{}
+		n, err = ptr.Replay(client, 1)
+		c.Expect(err, gospec.Equals, nil)
+		c.Expect(n, gospec.Equals, 3)
+		c.Expect(ptr.Close(), gospec.Equals, nil)
+
+		// The replayed segments are removed
+		segments, err = ptr.segments()
+		c.Expect(segments, gospec.Equals, []uint64{3})
+
+		// Restart
+		ptr, err = OpenRedisSpillLog(dir, 1)
+		c.Expect(err, gospec.Equals, nil)
+		defer ptr.Close()
+		c.Expect(ptr.IsEmpty(), gospec.Equals, true)
+
+		c.Expect(ptr.Append(MakeRedisBatchCommandGet("D")), gospec.Equals, nil)
+		client = &recordingRedisClient{}
+		n, err = ptr.Replay(client, 10)
+		c.Expect(err, gospec.Equals, nil)
+		c.Expect(n, gospec.Equals, 1)
+		c.Expect(client.args, gospec.Equals, [][]string{{"D"}})
+	})
+
+	c.Specify("[RedisSpillLog][Replay] Skips malformed & partially written lines", func() {
+		dir, err := ioutil.TempDir("", "dog_pool")
+		c.Expect(err, gospec.Equals, nil)
+		defer os.RemoveAll(dir)
+
+		ptr, err := OpenRedisSpillLog(dir, 0)
+		c.Expect(err, gospec.Equals, nil)
+		defer ptr.Close()
+		c.Expect(ptr.Append(MakeRedisBatchCommandGet("A")), gospec.Equals, nil)
+
+		file, err := os.OpenFile(filepath.Join(dir, "00000000000000000001.spill"), os.O_WRONLY|os.O_APPEND, 0644)
+		c.Expect(err, gospec.Equals, nil)
+		file.Write([]byte("not json\n{\"cmd\":\"GET\""))
+		file.Close()
+
+		client := &recordingRedisClient{}
+		n, err := ptr.Replay(client, 10)
+		c.Expect(err, gospec.Equals, nil)
+		c.Expect(n, gospec.Equals, 1)
+		c.Expect(client.args, gospec.Equals, [][]string{{"A"}})
+	})
+
+	c.Specify("[RedisBatchQueue][RunAsync] Spills the commands that don't fit to the SpillLog", func() {
+		dir, err := ioutil.TempDir("", "dog_pool")
+		c.Expect(err, gospec.Equals, nil)
+		defer os.RemoveAll(dir)
+
+		spill_log, err := OpenRedisSpillLog(dir, 0)
+		c.Expect(err, gospec.Equals, nil)
+
+		ptr := &RedisBatchQueue{
+			Overflow: RedisBatchOverflowSpill,
+			SpillLog: spill_log,
+			queue:    make(chan *RedisBatchCommand, 1),
+		}
+		defer close(ptr.queue)
+
+		future, err := ptr.RunAsyncFuture(MakeRedisBatchCommandGet("A"), MakeRedisBatchCommandGet("B"))
+		c.Expect(err, gospec.Equals, nil)
+		c.Expect(future.Commands[1].Reply().Err, gospec.Equals, ErrQueueIsSpilled)
+		c.Expect(ptr.Stats().Spilled, gospec.Equals, uint64(1))
+		c.Expect(spill_log.IsEmpty(), gospec.Equals, false)
+
+		// A closed SpillLog drops the command
+		spill_log.Close()
+		err = ptr.RunAsync(MakeRedisBatchCommandGet("C"))
+		c.Expect(err.Error(), gospec.Equals, "[RedisSpillLog][Append] Spill log is closed!")
+		c.Expect(ptr.Stats().Dropped, gospec.Equals, uint64(1))
+
+		client := &recordingRedisClient{}
+		n, err := spill_log.Replay(client, 10)
+		c.Expect(err, gospec.Equals, nil)
+		c.Expect(n, gospec.Equals, 1)
+		c.Expect(client.args, gospec.Equals, [][]string{{"B"}})
+	})
+}
@@ -1,12 +1,10 @@
 package dog_pool
 
-import "fmt"
 import "strings"
 import "time"
-import "github.com/alecthomas/log4go"
 
 type StopWatch struct {
-	*log4go.Logger
+	Logger
 	Connection interface{}
 	Tags       []string
 
@@ -14,7 +12,7 @@ type StopWatch struct {
 	time.Duration
 }
 
-func MakeStopWatch(connection interface{}, logger *log4go.Logger, tag string) *StopWatch {
+func MakeStopWatch(connection interface{}, logger Logger, tag string) *StopWatch {
 	output := &StopWatch{}
 	output.Logger = logger
 	output.Connection = connection
@@ -22,7 +20,7 @@ func MakeStopWatch(connection interface{}, logger *log4go.Logger, tag string) *S
 	return output
 }
 
-func MakeStopWatchTags(connection interface{}, logger *log4go.Logger, tags []string) *StopWatch {
+func MakeStopWatchTags(connection interface{}, logger Logger, tags []string) *StopWatch {
 	output := &StopWatch{}
 	output.Logger = logger
 	output.Connection = connection
@@ -46,17 +44,17 @@ func (p *StopWatch) Stop() *StopWatch {
 }
 
 func (p *StopWatch) LogDuration() *StopWatch {
-	return p.LogDurationAt(log4go.FINEST)
+	return p.LogDurationAt(LogFinest)
 }
 
-func (p *StopWatch) LogDurationAt(level log4go.Level) *StopWatch {
+func (p *StopWatch) LogDurationAt(level LogLevel) *StopWatch {
 	if ns := p.Duration.Nanoseconds(); ns > 0 {
 		micro := ns / int64(time.Microsecond)
 		milli := ns / int64(time.Millisecond)
 		sec := ns / int64(time.Second)
-		p.Logger.Logc(level, func() string {
-			return fmt.Sprintf("[%T | %s] Executed in %d ns / %d micro / %d milli / %d s", p.Connection, strings.Join(p.Tags, " | "), ns, micro, milli, sec)
-		})
+		if isLogEnabled(p.Logger, level) {
+			p.Logger.Log(level, "[%T | %s] Executed in %d ns / %d micro / %d milli / %d s", p.Connection, strings.Join(p.Tags, " | "), ns, micro, milli, sec)
+		}
 	}
 	return p
 }
@@ -0,0 +1,259 @@
+//
+// Tracing spans for Redis and Memcached operations
+//
+// Every RedisConnection command, RedisBatchCommands pipeline and MemcachedConnection operation
+// opens a child span of the context.Context it was called with.
+//
+// Plug in your own tracer (i.e. an OpenTelemetry adapter) by implementing Tracer,
+// or use the MemoryTracer to inspect the spans in tests.
+//
+
+package dog_pool
+
+import "context"
+import "strings"
+import "sync"
+import "time"
+import "github.com/RUNDSP/radix/redis"
+
+//
+// Attributes set on every span
+//
+const (
+	SpanAttributeSystem    = "db.system"      // "redis" or "memcached"
+	SpanAttributeOperation = "db.operation"   // Command name, i.e. "GET"
+	SpanAttributeKeyCount  = "db.key_count"   // Number of keys the command touches
+	SpanAttributeServer    = "server.address" // Server URL
+	SpanAttributeReplyType = "db.reply_type"  // Type of the reply, i.e. "bulk", "miss"
+	SpanAttributeError     = "error"          // Error message, only set on errors
+)
+
+//
+// A single timed operation
+//
+type Span interface {
+	SetAttribute(key string, value interface{})
+	RecordError(err error)
+	End()
+}
+
+//
+// Starts spans, the returned context carries the span for any child spans
+//
+type Tracer interface {
+	Start(ctx context.Context, name string) (context.Context, Span)
+}
+
+//
+// ==================================================
+//
+// No-op Tracer, the default:
+//
+// ==================================================
+//
+
+type NoopTracer struct{}
+
+type noopSpan struct{}
+
+func (p NoopTracer) Start(ctx context.Context, name string) (context.Context, Span) {
+	return ctx, noopSpan{}
+}
+
+func (p noopSpan) SetAttribute(key string, value interface{}) {}
+func (p noopSpan) RecordError(err error)                      {}
+func (p noopSpan) End()                                       {}
+
+//
+// ==================================================
+//
+// In-memory Tracer, for tests:
+//
+// ==================================================
+//
+
+//
+// Records every ended span
+//
+type MemoryTracer struct {
+	mutex   sync.Mutex
+	next_id uint64
+	spans   []*MemorySpan
+}
+
+//
+// Span recorded by the MemoryTracer
+//
+type MemorySpan struct {
+	Id         uint64                 "Unique id of the span"
+	ParentId   uint64                 "Id of the parent span, 0 for root spans"
+	Name       string                 "Name of the span, i.e. 'redis.GET'"
+	Attributes map[string]interface{} "Attributes set on the span"
+	Err        error                  "Last error recorded on the span"
+	StartedAt  time.Time              "When the span started"
+	EndedAt    time.Time              "When the span ended"
+
+	tracer *MemoryTracer
+}
+
+type memorySpanKey struct{}
+
+func MakeMemoryTracer() *MemoryTracer {
+	return &MemoryTracer{}
+}
+
+func (p *MemoryTracer) Start(ctx context.Context, name string) (context.Context, Span) {
+	if nil == ctx {
+		ctx = context.Background()
+	}
+
+	p.mutex.Lock()
+	p.next_id++
+	span := &MemorySpan{Id: p.next_id, Name: name, Attributes: map[string]interface{}{}, StartedAt: time.Now(), tracer: p}
+	p.mutex.Unlock()
+
+	if parent, ok := ctx.Value(memorySpanKey{}).(*MemorySpan); ok && parent.tracer == p {
+		span.ParentId = parent.Id
+	}
+
+	return context.WithValue(ctx, memorySpanKey{}, span), span
+}
+
+//
+// Ended spans, in the order they ended
+//
+func (p *MemoryTracer) Spans() []*MemorySpan {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	output := make([]*MemorySpan, len(p.spans))
+	copy(output, p.spans)
+	return output
+}
+
+//
+// Forget the recorded spans
+//
+func (p *MemoryTracer) Reset() {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	p.spans = nil
+}
+
+func (p *MemorySpan) SetAttribute(key string, value interface{}) {
+	p.Attributes[key] = value
+}
+
+func (p *MemorySpan) RecordError(err error) {
+	p.Err = err
+}
+
+func (p *MemorySpan) End() {
+	p.EndedAt = time.Now()
+
+	p.tracer.mutex.Lock()
+	defer p.tracer.mutex.Unlock()
+	p.tracer.spans = append(p.tracer.spans, p)
+}
+
+//
+// Time between the start & end of the span
+//
+func (p *MemorySpan) Duration() time.Duration {
+	return p.EndedAt.Sub(p.StartedAt)
+}
+
+//
+// ==================================================
+//
+// Helpers:
+//
+// ==================================================
+//
+
+//
+// Start a span with the common attributes, defaults to a no-op span
+//
+// Without a tracer nothing is computed, key_count is only called when the span is traced.
+//
+func startSpan(ctx context.Context, tracer Tracer, system, operation, url string, key_count func() int) (context.Context, Span) {
+	if nil == ctx {
+		ctx = context.Background()
+	}
+	if nil == tracer {
+		return ctx, noopSpan{}
+	}
+
+	ctx, span := tracer.Start(ctx, system+"."+operation)
+	span.SetAttribute(SpanAttributeSystem, system)
+	span.SetAttribute(SpanAttributeOperation, operation)
+	span.SetAttribute(SpanAttributeKeyCount, key_count())
+	span.SetAttribute(SpanAttributeServer, url)
+	return ctx, span
+}
+
+//
+// Record the reply type & error, then end the span
+//
+func endSpan(span Span, reply_type string, err error) {
+	if _, ok := span.(noopSpan); ok {
+		return
+	}
+
+	span.SetAttribute(SpanAttributeReplyType, reply_type)
+	if nil != err {
+		span.SetAttribute(SpanAttributeError, err.Error())
+		span.RecordError(err)
+	}
+	span.End()
+}
+
+//
+// Implemented by clients that carry a Tracer, i.e. RedisConnection
+//
+type redisTracedClient interface {
+	tracer() (Tracer, string)
+}
+
+//
+// Tracer & URL of the client, nil if the client isn't traced
+//
+func redisTracerOf(client RedisClientInterface) (Tracer, string) {
+	if traced, ok := client.(redisTracedClient); ok {
+		return traced.tracer()
+	}
+	return nil, ""
+}
+
+//
+// Number of keys the command touches, 0 for unknown commands
+//
+func redisKeyCount(cmd string, args []interface{}) int {
+	positions, ok := RedisNamespaceCommands[strings.ToUpper(cmd)]
+	if !ok {
+		return 0
+	}
+	return len(positions(flattenArgs(args)))
+}
+
+//
+// Name of the reply type
+//
+func redisReplyTypeName(reply *redis.Reply) string {
+	switch reply.Type {
+	case redis.StatusReply:
+		return "status"
+	case redis.ErrorReply:
+		return "error"
+	case redis.IntegerReply:
+		return "integer"
+	case redis.NilReply:
+		return "nil"
+	case redis.BulkReply:
+		return "bulk"
+	case redis.MultiReply:
+		return "multi"
+	default:
+		return "unknown"
+	}
+}
@@ -0,0 +1,150 @@
+package dog_pool
+
+import "context"
+import "errors"
+import "testing"
+import "github.com/orfjackal/gospec/src/gospec"
+import "github.com/alecthomas/log4go"
+import memcached "github.com/bradfitz/gomemcache/memcache"
+
+func TestTracerSpecs(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping test in benchmark mode.")
+		return
+	}
+	r := gospec.NewRunner()
+	r.AddSpec(TracerSpecs)
+	gospec.MainGoTest(r, t)
+}
+
+// Helpers
+func TracerSpecs(c gospec.Context) {
+
+	c.Specify("[MemoryTracer] Records child spans of the context", func() {
+		tracer := MakeMemoryTracer()
+
+		ctx, parent := tracer.Start(context.Background(), "request")
+		_, child := tracer.Start(ctx, "child")
+		child.RecordError(errors.New("boom"))
+		child.End()
+		parent.End()
+
+		spans := tracer.Spans()
+		c.Expect(len(spans), gospec.Equals, 2)
+		c.Expect(spans[0].Name, gospec.Equals, "child")
+		c.Expect(spans[0].ParentId, gospec.Equals, spans[1].Id)
+		c.Expect(spans[0].Err.Error(), gospec.Equals, "boom")
+		c.Expect(spans[1].Name, gospec.Equals, "request")
+		c.Expect(spans[1].ParentId, gospec.Equals, uint64(0))
+
+		tracer.Reset()
+		c.Expect(len(tracer.Spans()), gospec.Equals, 0)
+	})
+
+	c.Specify("[startSpan] Only counts the keys when traced", func() {
+		counted := 0
+		key_count := func() int {
+			counted++
+			return 2
+		}
+
+		_, span := startSpan(context.Background(), nil, "redis", "MGET", "127.0.0.1:6379", key_count)
+		endSpan(span, "multi", nil)
+		c.Expect(counted, gospec.Equals, 0)
+
+		tracer := MakeMemoryTracer()
+		_, span = startSpan(context.Background(), tracer, "redis", "MGET", "127.0.0.1:6379", key_count)
+		endSpan(span, "multi", nil)
+		c.Expect(counted, gospec.Equals, 1)
+		c.Expect(tracer.Spans()[0].Attributes[SpanAttributeKeyCount], gospec.Equals, 2)
+		c.Expect(tracer.Spans()[0].Attributes[SpanAttributeReplyType], gospec.Equals, "multi")
+	})
+
+	c.Specify("[RedisConnection][CmdContext] Opens a child span per command", func() {
+		logger := MakeLog4goLogger(log4go.NewDefaultLogger(log4go.CRITICAL))
+		server, err := StartRedisServer(logger)
+		if nil != err {
+			panic(err)
+		}
+		defer server.Close()
+
+		tracer := MakeMemoryTracer()
+		connection := server.Connection()
+		connection.Tracer = tracer
+
+		ctx, parent := tracer.Start(context.Background(), "request")
+		c.Expect(connection.CmdContext(ctx, "MSET", "A", "1", "B", "2").Err, gospec.Equals, nil)
+		connection.CmdContext(ctx, "HGET", "A", "field")
+		parent.End()
+
+		spans := tracer.Spans()
+		c.Expect(len(spans), gospec.Equals, 3)
+
+		c.Expect(spans[0].Name, gospec.Equals, "redis.MSET")
+		c.Expect(spans[0].ParentId, gospec.Equals, spans[2].Id)
+		c.Expect(spans[0].Attributes[SpanAttributeSystem], gospec.Equals, "redis")
+		c.Expect(spans[0].Attributes[SpanAttributeOperation], gospec.Equals, "MSET")
+		c.Expect(spans[0].Attributes[SpanAttributeKeyCount], gospec.Equals, 2)
+		c.Expect(spans[0].Attributes[SpanAttributeServer], gospec.Equals, connection.Url)
+		c.Expect(spans[0].Attributes[SpanAttributeReplyType], gospec.Equals, "status")
+		c.Expect(spans[0].Err, gospec.Equals, nil)
+
+		// WRONGTYPE
+		c.Expect(spans[1].Name, gospec.Equals, "redis.HGET")
+		c.Expect(spans[1].Attributes[SpanAttributeReplyType], gospec.Equals, "error")
+		c.Expect(nil != spans[1].Err, gospec.Equals, true)
+	})
+
+	c.Specify("[RedisBatchCommands][ExecuteBatchContext] Opens a span per pipeline", func() {
+		logger := MakeLog4goLogger(log4go.NewDefaultLogger(log4go.CRITICAL))
+		server, err := StartRedisServer(logger)
+		if nil != err {
+			panic(err)
+		}
+		defer server.Close()
+
+		tracer := MakeMemoryTracer()
+		connection := server.Connection()
+		connection.Tracer = tracer
+
+		cmds := RedisBatchCommands{MakeRedisBatchCommandSet("A", []byte("1")), MakeRedisBatchCommandGet("A"), MakeRedisBatchCommandGet("B")}
+		c.Expect(cmds.ExecuteBatchContext(context.Background(), MakeRedisNamespacedClient("ns:", connection)), gospec.Equals, nil)
+
+		spans := tracer.Spans()
+		c.Expect(len(spans), gospec.Equals, 1)
+		c.Expect(spans[0].Name, gospec.Equals, "redis.PIPELINE")
+		c.Expect(spans[0].Attributes["db.command_count"], gospec.Equals, 3)
+		c.Expect(spans[0].Attributes[SpanAttributeKeyCount], gospec.Equals, 3)
+		c.Expect(spans[0].Attributes[SpanAttributeServer], gospec.Equals, connection.Url)
+	})
+
+	c.Specify("[MemcachedConnection][GetContext] Opens a child span per operation, misses are not errors", func() {
+		logger := MakeLog4goLogger(log4go.NewDefaultLogger(log4go.CRITICAL))
+		server, err := StartMemcachedServer(logger)
+		if nil != err {
+			panic(err)
+		}
+		defer server.Close()
+
+		connection := server.Connection()
+		c.Expect(connection.Open(), gospec.Equals, nil)
+
+		tracer := MakeMemoryTracer()
+		connection.Tracer = tracer
+
+		ctx, parent := tracer.Start(context.Background(), "request")
+		c.Expect(connection.SetContext(ctx, &memcached.Item{Key: "A", Value: []byte("1")}), gospec.Equals, nil)
+		_, err = connection.GetContext(ctx, "Missing")
+		c.Expect(err, gospec.Equals, memcached.ErrCacheMiss)
+		parent.End()
+
+		spans := tracer.Spans()
+		c.Expect(len(spans), gospec.Equals, 3)
+		c.Expect(spans[0].Name, gospec.Equals, "memcached.Set")
+		c.Expect(spans[0].ParentId, gospec.Equals, spans[2].Id)
+		c.Expect(spans[0].Attributes[SpanAttributeReplyType], gospec.Equals, "ok")
+		c.Expect(spans[1].Name, gospec.Equals, "memcached.Get")
+		c.Expect(spans[1].Attributes[SpanAttributeReplyType], gospec.Equals, "miss")
+		c.Expect(spans[1].Err, gospec.Equals, nil)
+	})
+}